@@ -12,10 +12,11 @@ import (
 func main() {
 	io.NewLogger(version.IsDebug())
 	ctx := context.Background()
-	app := app.NewApp(version.GetVersion())
+	cls3 := app.NewApp(version.GetVersion())
 
-	if err := app.Run(ctx); err != nil {
+	err := cls3.Run(ctx)
+	if err != nil {
 		io.Logger.Error().Msg(err.Error())
-		os.Exit(1)
+		os.Exit(app.ExitCode(err))
 	}
 }
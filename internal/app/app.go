@@ -3,318 +3,1831 @@ package app
 import (
 	"context"
 	"fmt"
+	goio "io"
+	"math"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-to-k/cls3/internal/checkpoint"
+	"github.com/go-to-k/cls3/internal/interrupt"
+	"github.com/go-to-k/cls3/internal/inventory"
 	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/keylist"
+	"github.com/go-to-k/cls3/internal/manifest"
+	"github.com/go-to-k/cls3/internal/plan"
+	"github.com/go-to-k/cls3/internal/protection"
+	"github.com/go-to-k/cls3/internal/report"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"github.com/go-to-k/cls3/pkg/endpoint"
 	"github.com/urfave/cli/v2"
 )
 
 const (
 	UnspecifiedConcurrencyNumber = 0
+	UnspecifiedMaxRetries        = -1
 )
 
 type App struct {
-	Cli                  *cli.App
-	BucketNames          *cli.StringSlice
-	Profile              string
-	Region               string
-	EndpointUrl          string
-	PathStyle            bool
-	ForceMode            bool
-	InteractiveMode      bool
-	OldVersionsOnly      bool
-	QuietMode            bool
-	ConcurrentMode       bool
-	ConcurrencyNumber    int
-	DirectoryBucketsMode bool
-	TableBucketsMode     bool
-	VectorBucketsMode    bool
-	KeyPrefix            string
-	targetBuckets        []string // bucket names for S3 and S3Vectors, bucket arns for S3Tables
-	bucketSelector       IBucketSelector
-	bucketProcessor      IBucketProcessor
-	s3Wrapper            wrapper.IWrapper
+	Cli                        *cli.App
+	BucketNames                *cli.StringSlice
+	BucketPatterns             []string
+	ExcludeBucketPatterns      []string
+	Yes                        bool
+	ConfirmThreshold           int
+	CreatedBefore              string
+	CreatedAfter               string
+	BucketRegions              []string
+	Tags                       []string
+	ProtectionConfig           string
+	ProtectBucketPatterns      []string
+	ProtectTags                []string
+	ProtectAccountIds          []string
+	Profile                    string
+	AccountIds                 []string
+	Region                     string
+	EndpointUrl                string
+	PathStyle                  bool
+	ForceMode                  bool
+	InteractiveMode            bool
+	OldVersionsOnly            bool
+	QuietMode                  bool
+	ConcurrentMode             bool
+	ConcurrencyNumber          int
+	DirectoryBucketsMode       bool
+	TableBucketsMode           bool
+	VectorBucketsMode          bool
+	KeyPrefixes                []string
+	IncludePatterns            []string
+	ExcludePatterns            []string
+	DryRun                     bool
+	PlanFile                   string
+	Resume                     bool
+	CheckpointDir              string
+	Output                     string
+	ReportFile                 string
+	ManifestFile               string
+	OlderThan                  string
+	NewerThan                  string
+	KeepVersions               int
+	KeepNoncurrentDays         int
+	StorageClasses             *cli.StringSlice
+	MinSize                    string
+	MaxSize                    string
+	KeysFrom                   string
+	Inventory                  string
+	Partitions                 int
+	SplitPoints                []string
+	DeleteWorkers              int
+	MaxRequestsPerSecond       int
+	MaxDeleteRequestsPerSecond int
+	MaxInFlightRequests        int
+	RetryableErrorCodes        []string
+	MaxRetries                 int
+	RetryBaseDelay             time.Duration
+	RetryMaxDelay              time.Duration
+	ContinueOnError            bool
+	ExpiresAtTagKey            string
+	TTLTagKey                  string
+	targetBuckets              []string // bucket names for S3 and S3Vectors, bucket arns for S3Tables
+	targetEnvironment          *wrapper.TargetEnvironment
+	objectFilter               *client.ObjectFilter
+	bucketFilter               *BucketFilter
+	bucketProtection           *BucketProtection
+	deletionConfirmer          IDeletionConfirmer
+	targetRecorder             wrapper.ITargetRecorder
+	targetSource               wrapper.ITargetSource
+	checkpointer               wrapper.ICheckpointer
+	deletionRecorder           wrapper.IDeletionRecorder
+	reporter                   *report.Reporter
+	bucketSelector             IBucketSelector
+	bucketProcessor            IBucketProcessor
+	s3Wrapper                  wrapper.IWrapper
 }
 
 func NewApp(version string) *App {
 	app := App{}
 
 	app.BucketNames = cli.NewStringSlice()
+	app.StorageClasses = cli.NewStringSlice()
 	app.targetBuckets = []string{}
 
 	app.Cli = &cli.App{
 		Name:  "cls3",
 		Usage: "A CLI tool to clear all objects in S3 Buckets or delete Buckets.",
-		Flags: []cli.Flag{
-			&cli.StringSliceFlag{
-				Name:        "bucketName",
-				Aliases:     []string{"b"},
-				Usage:       "S3 bucket names(one or more)",
-				Destination: app.BucketNames,
+		Flags: app.getFlags(),
+		Commands: []*cli.Command{
+			{
+				Name:  "plan",
+				Usage: "Write the targets to be deleted to a plan file without deleting anything. The options are the same as without this command.",
+				Flags: append(app.getFlags(), &cli.StringFlag{
+					Name:        "planFile",
+					Aliases:     []string{"O"},
+					Usage:       "Path of the plan file to be written",
+					Required:    true,
+					Destination: &app.PlanFile,
+				}),
+				Action: app.withReport(app.getPlanAction()),
 			},
-			&cli.StringFlag{
-				Name:        "profile",
-				Aliases:     []string{"p"},
-				Usage:       "AWS profile name",
-				Destination: &app.Profile,
+			{
+				Name:      "apply",
+				Usage:     "Delete only the targets in a plan file written by the plan command.",
+				ArgsUsage: "<planFile>",
+				Flags:     app.getApplyFlags(),
+				Action:    app.withReport(app.getApplyAction()),
 			},
-			&cli.StringFlag{
-				Name:        "region",
-				Aliases:     []string{"r"},
-				Usage:       "AWS region",
-				Destination: &app.Region,
-			},
-			&cli.StringFlag{
-				Name:        "endpointUrl",
-				Aliases:     []string{"e"},
-				Usage:       "Custom endpoint URL",
-				EnvVars:     []string{"CLS3_ENDPOINT_URL"},
-				Destination: &app.EndpointUrl,
-			},
-			&cli.BoolFlag{
-				Name:        "pathStyle",
-				Aliases:     []string{"P"},
-				Value:       false,
-				Usage:       "Use path-style URL addressing (e.g., https://endpoint.com/bucket) instead of virtual-hosted-style (e.g., https://bucket.endpoint.com)",
-				Destination: &app.PathStyle,
-			},
-			&cli.BoolFlag{
-				Name:        "force",
-				Aliases:     []string{"f"},
-				Value:       false,
-				Usage:       "Delete a bucket together. If you specify this option with -t (--tableBucketsMode), it will delete not only the namespaces and the tables but also the table bucket itself.",
-				Destination: &app.ForceMode,
-			},
-			&cli.BoolFlag{
-				Name:        "interactive",
-				Aliases:     []string{"i"},
-				Value:       false,
-				Usage:       "Interactive Mode",
-				Destination: &app.InteractiveMode,
-			},
-			&cli.BoolFlag{
-				Name:        "oldVersionsOnly",
-				Aliases:     []string{"o"},
-				Value:       false,
-				Usage:       "Delete old version objects only (including all delete-markers)",
-				Destination: &app.OldVersionsOnly,
-			},
-			&cli.BoolFlag{
-				Name:        "quietMode",
-				Aliases:     []string{"q"},
-				Value:       false,
-				Usage:       "Hide live display of number of deletions",
-				Destination: &app.QuietMode,
-			},
-			&cli.BoolFlag{
-				Name:        "concurrentMode",
-				Aliases:     []string{"c"},
-				Value:       false,
-				Usage:       "Delete multiple buckets in parallel. If you want to limit the number of parallel deletions, specify the -n option. This option is not available in the Table Buckets Mode -t because the throttling threshold for S3 Tables is very low.",
-				Destination: &app.ConcurrentMode,
-			},
-			&cli.IntFlag{
-				Name:        "concurrencyNumber",
-				Aliases:     []string{"n"},
-				Value:       UnspecifiedConcurrencyNumber,
-				Usage:       "Specify the number of parallel deletions. To specify this option, the -c option must be specified. The default is to delete all buckets in parallel if only the -c option is specified.",
-				Destination: &app.ConcurrencyNumber,
-			},
-			&cli.BoolFlag{
-				Name:        "directoryBucketsMode",
-				Aliases:     []string{"d"},
-				Value:       false,
-				Usage:       "Clear Directory Buckets for S3 Express One Zone",
-				Destination: &app.DirectoryBucketsMode,
-			},
-			&cli.BoolFlag{
-				Name:        "tableBucketsMode",
-				Aliases:     []string{"t"},
-				Value:       false,
-				Usage:       "Clear Table Buckets for S3 Tables. If you specify this option WITHOUT -f (--force), it will delete ONLY the namespaces and the tables without the table bucket itself.",
-				Destination: &app.TableBucketsMode,
-			},
-			&cli.BoolFlag{
-				Name:        "vectorBucketsMode",
-				Aliases:     []string{"V"},
-				Value:       false,
-				Usage:       "Clear Vector Buckets for S3 Vectors. If you specify this option WITHOUT -f (--force), it will delete ONLY the indexes without the vector bucket itself.",
-				Destination: &app.VectorBucketsMode,
-			},
-			&cli.StringFlag{
-				Name:        "keyPrefix",
-				Aliases:     []string{"k"},
-				Usage:       "Key prefix of the objects to be deleted.",
-				Destination: &app.KeyPrefix,
+			{
+				Name:   "sweep",
+				Usage:  "Clear or delete all general purpose buckets whose expiry tag has passed. Directory, table and vector buckets are not swept because their tags cannot be read.",
+				Flags:  app.getSweepFlags(),
+				Action: app.withReport(app.getSweepAction()),
 			},
 		},
 	}
 
 	app.Cli.Version = version
-	app.Cli.Action = app.getAction()
+	app.Cli.Action = app.withReport(app.getAction())
 	app.Cli.HideHelpCommand = true
 
 	return &app
 }
 
-func (a *App) Run(ctx context.Context) error {
-	return a.Cli.RunContext(ctx, os.Args)
+// getFlags returns new flags every time because a flag cannot be shared between commands
+func (a *App) getFlags() []cli.Flag {
+	return []cli.Flag{
+		&cli.StringSliceFlag{
+			Name:        "bucketName",
+			Aliases:     []string{"b"},
+			Usage:       "S3 bucket names(one or more)",
+			Destination: a.BucketNames,
+		},
+		&cli.GenericFlag{
+			Name:        "bucketPattern",
+			Usage:       "Clear the buckets whose names match this pattern (one or more) instead of specifying the names with -b. Specify a glob (e.g. 'pr-1234-*') or a regular expression with the prefix 'regex:' (e.g. 'regex:pr-[0-9]+-.*'), both of which must match the whole name. The matched buckets are printed before the confirmation.",
+			Destination: (*stringList)(&a.BucketPatterns),
+		},
+		&cli.GenericFlag{
+			Name:        "excludeBucketPattern",
+			Usage:       "Do not clear the buckets whose names match this pattern (one or more) even if they match --bucketPattern. Specify a glob or a regular expression with the prefix 'regex:', both of which must match the whole name.",
+			Destination: (*stringList)(&a.ExcludeBucketPatterns),
+		},
+		&cli.BoolFlag{
+			Name:        "yes",
+			Aliases:     []string{"y"},
+			Value:       false,
+			Usage:       "Clear the buckets without the confirmation before anything is deleted. It is required in a non-interactive environment such as CI, where the run fails otherwise.",
+			Destination: &a.Yes,
+		},
+		&cli.IntFlag{
+			Name:        "confirmThreshold",
+			Value:       DefaultTypedConfirmationThreshold,
+			Usage:       "Number of the buckets above which the number of them must be typed instead of y to confirm the deletion. Specify 0 to always type it.",
+			Destination: &a.ConfirmThreshold,
+		},
+		&cli.StringFlag{
+			Name:        "createdBefore",
+			Usage:       "Clear only the buckets created before this time. Specify a duration before now (e.g. 7d, 12h, 1d12h) or an RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z).",
+			Destination: &a.CreatedBefore,
+		},
+		&cli.StringFlag{
+			Name:        "createdAfter",
+			Usage:       "Clear only the buckets created after this time. Specify a duration before now (e.g. 7d, 12h, 1d12h) or an RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z).",
+			Destination: &a.CreatedAfter,
+		},
+		&cli.GenericFlag{
+			Name:        "bucketRegion",
+			Usage:       "Clear only the buckets in this region (one or more).",
+			Destination: (*stringList)(&a.BucketRegions),
+		},
+		&cli.GenericFlag{
+			Name:        "tag",
+			Usage:       "Clear only the buckets with this tag (one or more). Specify key=value, or key for any value. With the prefix '!' (e.g. '!env=prod'), clear only the buckets without the tag. This option is not available with the -d, -t and -V options.",
+			Destination: (*stringList)(&a.Tags),
+		},
+		&cli.StringFlag{
+			Name:        "protectionConfig",
+			Usage:       "Path of a JSON file with the rules of the buckets that cls3 must never clear. Default is cls3/protection.json in the user config directory if it exists.",
+			Destination: &a.ProtectionConfig,
+		},
+		&cli.GenericFlag{
+			Name:        "protectBucketPattern",
+			Usage:       "Protect the buckets whose names match this pattern (one or more) in addition to the protection config. A glob or a regular expression with the prefix 'regex:', both of which must match the whole name.",
+			Destination: (*stringList)(&a.ProtectBucketPatterns),
+		},
+		&cli.GenericFlag{
+			Name:        "protectTag",
+			Usage:       "Protect the buckets with this tag (one or more) in addition to the protection config. Specify key=value, or key for any value. Only for general purpose buckets, so it cannot be specified with the -d, -t or -V option.",
+			Destination: (*stringList)(&a.ProtectTags),
+		},
+		&cli.GenericFlag{
+			Name:        "protectAccountId",
+			Usage:       "Protect all buckets in this AWS account (one or more) in addition to the protection config.",
+			Destination: (*stringList)(&a.ProtectAccountIds),
+		},
+		&cli.StringFlag{
+			Name:        "profile",
+			Aliases:     []string{"p"},
+			Usage:       "AWS profile name",
+			Destination: &a.Profile,
+		},
+		&cli.GenericFlag{
+			Name:        "accountId",
+			Usage:       "Clear the buckets only if the credentials are for this AWS account (one or more). The account is checked before anything is cleared, and the requests for the objects and the buckets fail if the buckets are owned by another account.",
+			Destination: (*stringList)(&a.AccountIds),
+		},
+		&cli.StringFlag{
+			Name:        "region",
+			Aliases:     []string{"r"},
+			Usage:       "AWS region",
+			Destination: &a.Region,
+		},
+		&cli.StringFlag{
+			Name:        "endpointUrl",
+			Aliases:     []string{"e"},
+			Usage:       "Custom endpoint URL",
+			EnvVars:     []string{"CLS3_ENDPOINT_URL"},
+			Destination: &a.EndpointUrl,
+		},
+		&cli.BoolFlag{
+			Name:        "pathStyle",
+			Aliases:     []string{"P"},
+			Value:       false,
+			Usage:       "Use path-style URL addressing (e.g., https://endpoint.com/bucket) instead of virtual-hosted-style (e.g., https://bucket.endpoint.com)",
+			Destination: &a.PathStyle,
+		},
+		&cli.BoolFlag{
+			Name:        "force",
+			Aliases:     []string{"f"},
+			Value:       false,
+			Usage:       "Delete a bucket together. If you specify this option with -t (--tableBucketsMode), it will delete not only the namespaces and the tables but also the table bucket itself.",
+			Destination: &a.ForceMode,
+		},
+		&cli.BoolFlag{
+			Name:        "interactive",
+			Aliases:     []string{"i"},
+			Value:       false,
+			Usage:       "Interactive Mode",
+			Destination: &a.InteractiveMode,
+		},
+		&cli.BoolFlag{
+			Name:        "oldVersionsOnly",
+			Aliases:     []string{"o"},
+			Value:       false,
+			Usage:       "Delete old version objects only (including all delete-markers)",
+			Destination: &a.OldVersionsOnly,
+		},
+		&cli.BoolFlag{
+			Name:        "quietMode",
+			Aliases:     []string{"q"},
+			Value:       false,
+			Usage:       "Hide live display of number of deletions",
+			Destination: &a.QuietMode,
+		},
+		&cli.BoolFlag{
+			Name:        "concurrentMode",
+			Aliases:     []string{"c"},
+			Value:       false,
+			Usage:       "Delete multiple buckets in parallel. If you want to limit the number of parallel deletions, specify the -n option. This option is not available in the Table Buckets Mode -t because the throttling threshold for S3 Tables is very low.",
+			Destination: &a.ConcurrentMode,
+		},
+		&cli.IntFlag{
+			Name:        "concurrencyNumber",
+			Aliases:     []string{"n"},
+			Value:       UnspecifiedConcurrencyNumber,
+			Usage:       "Specify the number of parallel deletions. To specify this option, the -c option must be specified. The default is to delete all buckets in parallel if only the -c option is specified.",
+			Destination: &a.ConcurrencyNumber,
+		},
+		&cli.BoolFlag{
+			Name:        "directoryBucketsMode",
+			Aliases:     []string{"d"},
+			Value:       false,
+			Usage:       "Clear Directory Buckets for S3 Express One Zone",
+			Destination: &a.DirectoryBucketsMode,
+		},
+		&cli.BoolFlag{
+			Name:        "tableBucketsMode",
+			Aliases:     []string{"t"},
+			Value:       false,
+			Usage:       "Clear Table Buckets for S3 Tables. If you specify this option WITHOUT -f (--force), it will delete ONLY the namespaces and the tables without the table bucket itself.",
+			Destination: &a.TableBucketsMode,
+		},
+		&cli.BoolFlag{
+			Name:        "vectorBucketsMode",
+			Aliases:     []string{"V"},
+			Value:       false,
+			Usage:       "Clear Vector Buckets for S3 Vectors. If you specify this option WITHOUT -f (--force), it will delete ONLY the indexes without the vector bucket itself.",
+			Destination: &a.VectorBucketsMode,
+		},
+		&cli.GenericFlag{
+			Name:        "keyPrefix",
+			Aliases:     []string{"k"},
+			Usage:       "Key prefix of the objects to be deleted. Specify it multiple times to clear the prefixes in parallel.",
+			Destination: (*stringList)(&a.KeyPrefixes),
+		},
+		&cli.GenericFlag{
+			Name:        "include",
+			Usage:       "Delete only the objects whose keys match this pattern (one or more). Specify a glob (e.g. 'builds/*.zip') or a regular expression with the prefix 'regex:'.",
+			Destination: (*stringList)(&a.IncludePatterns),
+		},
+		&cli.GenericFlag{
+			Name:        "exclude",
+			Usage:       "Do not delete the objects whose keys match this pattern (one or more). Specify a glob (e.g. '*/release/*') or a regular expression with the prefix 'regex:'.",
+			Destination: (*stringList)(&a.ExcludePatterns),
+		},
+		&cli.StringFlag{
+			Name:        "olderThan",
+			Usage:       "Delete only the objects last modified before this time. Specify a duration before now (e.g. 7d, 12h, 1d12h) or an RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z).",
+			Destination: &a.OlderThan,
+		},
+		&cli.StringFlag{
+			Name:        "newerThan",
+			Usage:       "Delete only the objects last modified after this time. Specify a duration before now (e.g. 7d, 12h, 1d12h) or an RFC3339 timestamp (e.g. 2025-01-01T00:00:00Z).",
+			Destination: &a.NewerThan,
+		},
+		&cli.IntFlag{
+			Name:        "keepVersions",
+			Usage:       "Keep the newest N noncurrent versions of each key and delete the rest. To specify this option, the -o option must be specified.",
+			Destination: &a.KeepVersions,
+		},
+		&cli.IntFlag{
+			Name:        "keepNoncurrentDays",
+			Usage:       "Keep the noncurrent versions that became noncurrent within the last N days and delete the rest. To specify this option, the -o option must be specified.",
+			Destination: &a.KeepNoncurrentDays,
+		},
+		&cli.StringSliceFlag{
+			Name:        "storageClass",
+			Usage:       "Delete only the objects in these storage classes (one or more), e.g. STANDARD, GLACIER, DEEP_ARCHIVE, INTELLIGENT_TIERING.",
+			Destination: a.StorageClasses,
+		},
+		&cli.StringFlag{
+			Name:        "minSize",
+			Usage:       "Delete only the objects of this size or larger. Specify bytes or a size with a unit (e.g. 1024, 10MB, 1GiB).",
+			Destination: &a.MinSize,
+		},
+		&cli.StringFlag{
+			Name:        "maxSize",
+			Usage:       "Delete only the objects of this size or smaller. Specify bytes or a size with a unit (e.g. 1024, 10MB, 1GiB).",
+			Destination: &a.MaxSize,
+		},
+		&cli.StringFlag{
+			Name:        "keysFrom",
+			Usage:       "Delete only the objects in a key list file without listing them, or from stdin with '-'. The deletion cannot be confirmed when the keys are read from stdin, so the --yes option must also be specified with '-' unless --dryRun is specified. Each line is 'key' or 'key<TAB>versionId', or the file can be JSON Lines (.jsonl) or CSV (.csv) with the key and versionId fields.",
+			Destination: &a.KeysFrom,
+		},
+		&cli.StringFlag{
+			Name:        "inventory",
+			Usage:       "Delete the objects in an S3 Inventory report without listing them. Specify the manifest.json of the report as a local path or an S3 URI (s3://bucket/path/manifest.json). Only the CSV format is supported.",
+			Destination: &a.Inventory,
+		},
+		&cli.IntFlag{
+			Name:        "partitions",
+			Usage:       "List each bucket (or each key prefix) in up to this number of key ranges in parallel, split by the top-level prefixes (delimited by '/') found in it. It speeds up clearing a bucket with many objects, where listing is the bottleneck.",
+			Destination: &a.Partitions,
+		},
+		&cli.GenericFlag{
+			Name:        "splitAt",
+			Usage:       "List each bucket in key ranges in parallel, split after this key. The keys up to it (inclusive) are in the range before it. Specify this option multiple times to split at several keys.",
+			Value:       &stringList{},
+			Destination: (*stringList)(&a.SplitPoints),
+		},
+		&cli.IntFlag{
+			Name:        "deleteWorkers",
+			Usage:       "Number of the workers that delete the listed objects in parallel for each bucket. The listing waits while all of them are busy, so this also bounds the memory. The default is 8.",
+			Destination: &a.DeleteWorkers,
+		},
+		&cli.IntFlag{
+			Name:        "maxRequestsPerSecond",
+			Usage:       "Maximum number of the requests per second to S3 (or S3 Tables and S3 Vectors) for all buckets together, including the retries, whatever the -c and -n options are.",
+			Destination: &a.MaxRequestsPerSecond,
+		},
+		&cli.IntFlag{
+			Name:        "maxDeleteRequestsPerSecond",
+			Usage:       "Maximum number of the delete requests (e.g. DeleteObjects) per second for all buckets together. The requests to list are not counted. It can be specified with --maxRequestsPerSecond.",
+			Destination: &a.MaxDeleteRequestsPerSecond,
+		},
+		&cli.IntFlag{
+			Name:        "maxInFlightRequests",
+			DefaultText: "unlimited",
+			Usage:       "Maximum number of the requests in flight to S3 (or S3 Tables and S3 Vectors) for all buckets together. The limit of them is halved on the throttling responses (e.g. SlowDown) and raised gradually up to this number while the requests succeed.",
+			Destination: &a.MaxInFlightRequests,
+		},
+		&cli.GenericFlag{
+			Name:        "retryableErrorCode",
+			Usage:       "API error code (e.g. RequestTimeout) of the failed requests to be retried in addition to the default ones such as SlowDown and InternalError. Specify this option multiple times to add several codes.",
+			Value:       &stringList{},
+			Destination: (*stringList)(&a.RetryableErrorCodes),
+		},
+		&cli.IntFlag{
+			Name:        "maxRetries",
+			Value:       UnspecifiedMaxRetries,
+			DefaultText: strconv.Itoa(client.MaxAttempts - 1),
+			Usage:       "Maximum number of the retries of each failed request. Specify 0 to fail fast without retrying.",
+			EnvVars:     []string{"CLS3_MAX_RETRIES"},
+			Destination: &a.MaxRetries,
+		},
+		&cli.DurationFlag{
+			Name:        "retryBaseDelay",
+			DefaultText: client.DefaultRetryBaseDelay.String(),
+			Usage:       "Cap of the random delay before the first retry (e.g. 500ms, 2s). The cap is doubled for each retry up to --retryMaxDelay.",
+			EnvVars:     []string{"CLS3_RETRY_BASE_DELAY"},
+			Destination: &a.RetryBaseDelay,
+		},
+		&cli.DurationFlag{
+			Name:        "retryMaxDelay",
+			DefaultText: "20s, or 3s for Table Buckets",
+			Usage:       "Cap of the random delay before each retry (e.g. 10s, 1m).",
+			EnvVars:     []string{"CLS3_RETRY_MAX_DELAY"},
+			Destination: &a.RetryMaxDelay,
+		},
+		&cli.BoolFlag{
+			Name:        "continueOnError",
+			Value:       false,
+			Usage:       "Clear all buckets even if some of them fail, and output a summary of the succeeded, partially cleared and failed buckets at the end. The exit code is 1 if all buckets failed, and 2 if only some of them failed or were partially cleared.",
+			Destination: &a.ContinueOnError,
+		},
+		&cli.BoolFlag{
+			Name:        "dryRun",
+			Value:       false,
+			Usage:       "List the objects (or tables and indexes) to be deleted and output the numbers of them for each bucket without deleting anything.",
+			Destination: &a.DryRun,
+		},
+		&cli.BoolFlag{
+			Name:        "resume",
+			Value:       false,
+			Usage:       "Save checkpoints while clearing buckets, and resume each bucket from its checkpoint if the previous run with this option stopped. The checkpoints are removed when the buckets are cleared.",
+			Destination: &a.Resume,
+		},
+		&cli.StringFlag{
+			Name:        "checkpointDir",
+			Usage:       "Directory of the checkpoint files for the --resume option. The default is the cls3/checkpoints directory in the user cache directory.",
+			Destination: &a.CheckpointDir,
+		},
+		&cli.StringFlag{
+			Name:        "output",
+			Value:       report.FormatText,
+			Usage:       "Output format of the result: text or json. With json, a JSON report of the run is written to stdout instead of the live display.",
+			Destination: &a.Output,
+		},
+		&cli.StringFlag{
+			Name:        "reportFile",
+			Usage:       "Path of a file to write the JSON report of the run to. It can be specified with either output format.",
+			Destination: &a.ReportFile,
+		},
+		&cli.StringFlag{
+			Name:        "manifest",
+			Usage:       "Path of a file to write every deleted object (or table and index) to for auditing. The format is CSV for a .csv file, otherwise JSON Lines. The ones that could not be deleted are written to a separate failures file (e.g. deleted.failures.jsonl for deleted.jsonl).",
+			Destination: &a.ManifestFile,
+		},
+	}
+}
+
+// getApplyFlags returns the flags for the apply command. The other options are taken from the plan file.
+func (a *App) getApplyFlags() []cli.Flag {
+	applyFlagNames := []string{
+		"yes",
+		"confirmThreshold",
+		"protectionConfig",
+		"protectBucketPattern",
+		"protectTag",
+		"protectAccountId",
+		"profile",
+		"accountId",
+		"region",
+		"endpointUrl",
+		"pathStyle",
+		"quietMode",
+		"concurrentMode",
+		"concurrencyNumber",
+		"continueOnError",
+		"output",
+		"reportFile",
+		"manifest",
+	}
+
+	flags := []cli.Flag{}
+	for _, flag := range a.getFlags() {
+		if slices.Contains(applyFlagNames, flag.Names()[0]) {
+			flags = append(flags, flag)
+		}
+	}
+	return flags
+}
+
+// getSweepFlags returns the flags for the sweep command. The buckets are selected by their expiry tags instead of the options.
+func (a *App) getSweepFlags() []cli.Flag {
+	sweepFlagNames := []string{
+		"yes",
+		"confirmThreshold",
+		"protectionConfig",
+		"protectBucketPattern",
+		"protectTag",
+		"protectAccountId",
+		"profile",
+		"accountId",
+		"region",
+		"endpointUrl",
+		"pathStyle",
+		"force",
+		"quietMode",
+		"concurrentMode",
+		"concurrencyNumber",
+		"deleteWorkers",
+		"maxRequestsPerSecond",
+		"maxDeleteRequestsPerSecond",
+		"maxInFlightRequests",
+		"retryableErrorCode",
+		"maxRetries",
+		"retryBaseDelay",
+		"retryMaxDelay",
+		"continueOnError",
+		"dryRun",
+		"output",
+		"reportFile",
+		"manifest",
+	}
+
+	flags := []cli.Flag{}
+	for _, flag := range a.getFlags() {
+		if slices.Contains(sweepFlagNames, flag.Names()[0]) {
+			flags = append(flags, flag)
+		}
+	}
+	return append(flags,
+		&cli.StringFlag{
+			Name:        "expiresAtTagKey",
+			Value:       DefaultExpiresAtTagKey,
+			Usage:       "Key of the tag with the time when a bucket expires in RFC3339 (e.g. 2025-01-31T00:00:00Z)",
+			Destination: &a.ExpiresAtTagKey,
+		},
+		&cli.StringFlag{
+			Name:        "ttlTagKey",
+			Value:       DefaultTTLTagKey,
+			Usage:       "Key of the tag with the time to live of a bucket after its creation (e.g. 7d, 12h or 1d12h)",
+			Destination: &a.TTLTagKey,
+		},
+	)
+}
+
+func (a *App) Run(ctx context.Context) error {
+	return a.Cli.RunContext(ctx, os.Args)
+}
+
+func (a *App) getAction() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		io.Logger.Debug().Msg("Debug mode...")
+
+		if err := a.validateOptions(); err != nil {
+			return err
+		}
+		if err := a.checkAccount(c.Context); err != nil {
+			return err
+		}
+		if err := a.initTargetSource(c.Context); err != nil {
+			return err
+		}
+
+		if confirmed, err := a.selectAndConfirmBuckets(c.Context); err != nil || !confirmed {
+			return err
+		}
+
+		if err := a.initCheckpointer(c.Context); err != nil {
+			return err
+		}
+		return a.processBuckets(c.Context)
+	}
+}
+
+func (a *App) getPlanAction() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		io.Logger.Debug().Msg("Debug mode...")
+
+		// NOTE: The plan is made by listing the targets in the dry-run mode.
+		a.DryRun = true
+
+		if err := a.validateOptions(); err != nil {
+			return err
+		}
+		if err := a.checkAccount(c.Context); err != nil {
+			return err
+		}
+		if err := a.initTargetSource(c.Context); err != nil {
+			return err
+		}
+
+		if err := a.initTargetEnvironment(c.Context); err != nil {
+			return err
+		}
+
+		continuation, err := a.selectBuckets(c.Context)
+		if err != nil {
+			return err
+		}
+		if !continuation {
+			return nil
+		}
+
+		planWriter, err := plan.NewWriter(a.PlanFile, plan.Header{
+			CreatedAt:       time.Now().UTC(),
+			AccountId:       a.targetEnvironment.AccountId,
+			Region:          a.targetEnvironment.Region,
+			EndpointUrl:     a.targetEnvironment.EndpointUrl,
+			Mode:            a.getPlanMode(),
+			ForceMode:       a.ForceMode,
+			OldVersionsOnly: a.OldVersionsOnly,
+			KeyPrefixes:     a.KeyPrefixes,
+			Buckets:         a.targetBuckets,
+		})
+		if err != nil {
+			return err
+		}
+
+		a.targetRecorder = planWriter
+
+		if err := a.initBucketProcessor(); err != nil {
+			planWriter.Close()
+			return err
+		}
+		ctx, stopHandlingInterrupt := handleInterrupt(c.Context)
+		defer stopHandlingInterrupt()
+		if err := a.bucketProcessor.Process(ctx); err != nil {
+			// NOTE: An incomplete plan must not be applied, so it is removed.
+			planWriter.Close()
+			if removeErr := os.Remove(a.PlanFile); removeErr != nil {
+				io.Logger.Warn().Msgf("Failed to remove the incomplete plan file %v: %v", a.PlanFile, removeErr)
+			}
+			return err
+		}
+		if err := planWriter.Close(); err != nil {
+			return err
+		}
+
+		io.Logger.Info().Msgf("The plan has been written to %v. Run `cls3 apply %v` to delete only the targets in it.", a.PlanFile, a.PlanFile)
+		return nil
+	}
+}
+
+func (a *App) getApplyAction() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		io.Logger.Debug().Msg("Debug mode...")
+
+		if c.NArg() != 1 {
+			errMsg := fmt.Sprintln("Specify one plan file to apply: cls3 apply <planFile>")
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+
+		planReader, err := plan.NewReader(c.Args().First())
+		if err != nil {
+			return err
+		}
+		header := planReader.Header()
+		a.setOptionsFromPlan(header)
+
+		if err := a.validateApplyOptions(); err != nil {
+			return err
+		}
+
+		if err := a.initTargetEnvironment(c.Context); err != nil {
+			return err
+		}
+		if err := header.CheckEnvironment(a.targetEnvironment); err != nil {
+			return err
+		}
+		if err := a.checkAccountIds(c.Context); err != nil {
+			return err
+		}
+
+		if err := a.checkProtectedAccount(c.Context); err != nil {
+			return err
+		}
+
+		if err := a.initS3Wrapper(c.Context); err != nil {
+			return err
+		}
+		if err := a.verifyProtectedPlanBuckets(c.Context, header.Buckets); err != nil {
+			return err
+		}
+		a.targetBuckets = append(a.targetBuckets, header.Buckets...)
+		a.targetSource = planReader
+
+		if confirmed, err := a.confirmDeletion(c.Context); err != nil || !confirmed {
+			return err
+		}
+		return a.processBuckets(c.Context)
+	}
+}
+
+func (a *App) getSweepAction() func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		io.Logger.Debug().Msg("Debug mode...")
+
+		if err := a.validateSweepOptions(); err != nil {
+			return err
+		}
+		io.Logger.Info().Msg("Only the general purpose buckets are swept. Directory, table and vector buckets are not swept because their tags cannot be read.")
+		// NOTE: The account is checked before the client is created so that it checks the owner of the buckets.
+		if err := a.checkAccount(c.Context); err != nil {
+			return err
+		}
+		if err := a.initS3Wrapper(c.Context); err != nil {
+			return err
+		}
+
+		if a.bucketSelector == nil {
+			sweeperConfig := BucketSweeperConfig{
+				ExpiresAtTagKey: a.ExpiresAtTagKey,
+				TTLTagKey:       a.TTLTagKey,
+				Reporter:        a.reporter,
+				Protection:      a.bucketProtection,
+			}
+			a.bucketSelector = NewBucketSweeper(sweeperConfig, a.s3Wrapper)
+		}
+
+		continuation, err := a.selectBuckets(c.Context)
+		if err != nil {
+			return err
+		}
+		if !continuation {
+			return nil
+		}
+
+		if confirmed, err := a.confirmDeletion(c.Context); err != nil || !confirmed {
+			return err
+		}
+		return a.processBuckets(c.Context)
+	}
+}
+
+// verifyProtectedPlanBuckets returns an error if any bucket in a plan has been protected after the plan was written
+func (a *App) verifyProtectedPlanBuckets(ctx context.Context, buckets []string) error {
+	if a.bucketProtection == nil {
+		return nil
+	}
+	outputs, err := lookupBuckets(ctx, a.s3Wrapper, buckets)
+	if err != nil {
+		return err
+	}
+	return a.bucketProtection.Verify(ctx, a.s3Wrapper, outputs)
+}
+
+// handleInterrupt makes the first signal stop the clearing after the deletions in progress, and the second one force
+// cls3 to exit. Until it is called, nothing has been deleted, so the signals abort cls3 at once as usual
+// (e.g. at the prompts or while the buckets are listed).
+func handleInterrupt(ctx context.Context) (context.Context, func()) {
+	return interrupt.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+}
+
+// processBuckets clears the target buckets, writing the deleted targets to the manifest if the --manifest option is specified
+func (a *App) processBuckets(ctx context.Context) error {
+	ctx, stopHandlingInterrupt := handleInterrupt(ctx)
+	defer stopHandlingInterrupt()
+
+	if a.ManifestFile == "" {
+		if err := a.initBucketProcessor(); err != nil {
+			return err
+		}
+		return a.bucketProcessor.Process(ctx)
+	}
+
+	manifestWriter, err := manifest.NewWriter(a.ManifestFile)
+	if err != nil {
+		return err
+	}
+	a.deletionRecorder = manifestWriter
+
+	if err = a.initBucketProcessor(); err != nil {
+		manifestWriter.Close()
+		return err
+	}
+	err = a.bucketProcessor.Process(ctx)
+
+	// NOTE: The manifest is kept even if the clearing fails because it has the targets deleted so far.
+	if closeErr := manifestWriter.Close(); closeErr != nil {
+		if err != nil {
+			io.Logger.Warn().Msgf("Failed to close the manifest file %v: %v", a.ManifestFile, closeErr)
+			return err
+		}
+		return closeErr
+	}
+	io.Logger.Info().Msgf("The deleted targets have been written to %v, and the ones that could not be deleted to %v.", a.ManifestFile, manifest.FailuresPath(a.ManifestFile))
+	return err
+}
+
+// withReport outputs the report of the run after the action if it is requested by the --output or --reportFile option
+func (a *App) withReport(action cli.ActionFunc) cli.ActionFunc {
+	return func(c *cli.Context) error {
+		if a.Output != report.FormatText && a.Output != report.FormatJSON {
+			errMsg := fmt.Sprintf("The --output option must be %v or %v, but got %q.\n", report.FormatText, report.FormatJSON, a.Output)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		if a.Output != report.FormatJSON && a.ReportFile == "" {
+			return action(c)
+		}
+
+		a.reporter = report.NewReporter()
+		err := action(c)
+
+		if reportErr := a.outputReport(c.App.Writer, err); reportErr != nil {
+			if err != nil {
+				io.Logger.Warn().Msgf("Failed to output the report: %v", reportErr)
+				return err
+			}
+			return reportErr
+		}
+		return err
+	}
+}
+
+// outputReport writes the report of the run to stdout for the json output and to the report file if specified
+func (a *App) outputReport(stdout goio.Writer, runErr error) error {
+	region := a.Region
+	if a.targetEnvironment != nil {
+		region = a.targetEnvironment.Region
+	}
+	runReport := a.reporter.Report(report.Options{
+		BucketType: string(a.getPlanMode()),
+		Region:     region,
+		DryRun:     a.DryRun,
+	}, runErr)
+	// NOTE: The exit code in the report must be the same as the one of the process (e.g. 130 when interrupted).
+	if runErr != nil {
+		runReport.ExitCode = ExitCode(runErr)
+	}
+	switch runReport.ExitCode {
+	case ExitCodePartiallyFailed:
+		runReport.Status = report.StatusPartiallyFailed
+	case interrupt.ExitCode:
+		runReport.Status = report.StatusInterrupted
+	}
+
+	if a.Output == report.FormatJSON {
+		if err := report.Write(stdout, runReport); err != nil {
+			return err
+		}
+	}
+	if a.ReportFile != "" {
+		if err := report.WriteFile(a.ReportFile, runReport); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// selectBuckets selects the target buckets, and returns false if the user cancels it
+func (a *App) selectBuckets(ctx context.Context) (bool, error) {
+	if err := a.initS3Wrapper(ctx); err != nil {
+		return false, err
+	}
+	if err := a.initBucketSelector(); err != nil {
+		return false, err
+	}
+
+	selectedBuckets, continuation, err := a.bucketSelector.SelectBuckets(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !continuation {
+		return false, nil
+	}
+	a.targetBuckets = append(a.targetBuckets, selectedBuckets...)
+
+	return true, nil
+}
+
+// selectAndConfirmBuckets selects the target buckets and confirms the deletion of them, and returns false if
+// the user cancels it. In the interactive mode, the buckets are selected again if the deletion is declined.
+func (a *App) selectAndConfirmBuckets(ctx context.Context) (bool, error) {
+	for {
+		continuation, err := a.selectBuckets(ctx)
+		if err != nil || !continuation {
+			return false, err
+		}
+
+		confirmed, err := a.confirmDeletion(ctx)
+		if err != nil || confirmed || !a.InteractiveMode {
+			return confirmed, err
+		}
+		a.targetBuckets = []string{}
+		io.Logger.Info().Msg("Select the buckets again, or cancel the selection to finish.")
+	}
+}
+
+// confirmDeletion shows the target buckets and the environment, and returns false if the user cancels the deletion.
+// Nothing is deleted in the dry-run mode, so it is not confirmed.
+func (a *App) confirmDeletion(ctx context.Context) (bool, error) {
+	if a.Yes || a.DryRun {
+		return true, nil
+	}
+	if err := a.initTargetEnvironment(ctx); err != nil {
+		return false, err
+	}
+	if a.deletionConfirmer == nil {
+		a.deletionConfirmer = NewDeletionConfirmer(a.ConfirmThreshold)
+	}
+	return a.deletionConfirmer.Confirm(DeletionSummary{
+		Buckets:   a.targetBuckets,
+		AccountId: a.targetEnvironment.AccountId,
+		Region:    a.targetEnvironment.Region,
+		Mode:      string(a.getPlanMode()),
+		ForceMode: a.ForceMode,
+	})
+}
+
+func (a *App) getPlanMode() plan.Mode {
+	switch {
+	case a.DirectoryBucketsMode:
+		return plan.ModeDirectory
+	case a.TableBucketsMode:
+		return plan.ModeTable
+	case a.VectorBucketsMode:
+		return plan.ModeVector
+	default:
+		return plan.ModeGeneral
+	}
+}
+
+// getTargetsName returns the name of the targets counted while clearing the buckets in the mode
+func (a *App) getTargetsName() string {
+	switch {
+	case a.TableBucketsMode:
+		return "tables"
+	case a.VectorBucketsMode:
+		return "indexes"
+	default:
+		return "objects"
+	}
+}
+
+// setOptionsFromPlan sets the options that determine the targets from a plan
+func (a *App) setOptionsFromPlan(header *plan.Header) {
+	a.DirectoryBucketsMode = header.Mode == plan.ModeDirectory
+	a.TableBucketsMode = header.Mode == plan.ModeTable
+	a.VectorBucketsMode = header.Mode == plan.ModeVector
+	a.ForceMode = header.ForceMode
+	a.OldVersionsOnly = header.OldVersionsOnly
+	a.KeyPrefixes = header.KeyPrefixes
+}
+
+func (a *App) getS3WrapperInput() wrapper.CreateS3WrapperInput {
+	return wrapper.CreateS3WrapperInput{
+		Region:               a.Region,
+		Profile:              a.Profile,
+		EndpointUrl:          a.EndpointUrl,
+		PathStyle:            a.PathStyle,
+		TableBucketsMode:     a.TableBucketsMode,
+		DirectoryBucketsMode: a.DirectoryBucketsMode,
+		VectorBucketsMode:    a.VectorBucketsMode,
+		MaxAttempts:          a.getMaxAttempts(),
+		RetryBaseDelay:       a.RetryBaseDelay,
+		RetryMaxDelay:        a.RetryMaxDelay,
+		RetryableErrorCodes:  a.RetryableErrorCodes,
+		ExpectedBucketOwner:  a.getExpectedBucketOwner(),
+	}
+}
+
+// getExpectedBucketOwner returns the account that must own the buckets after it is checked for the --accountId option, or empty
+func (a *App) getExpectedBucketOwner() string {
+	if len(a.AccountIds) == 0 || a.targetEnvironment == nil {
+		return ""
+	}
+	return a.targetEnvironment.AccountId
+}
+
+// getMaxAttempts returns the number of the attempts of each request for the --maxRetries option, or 0 for the default
+func (a *App) getMaxAttempts() int {
+	if a.MaxRetries == UnspecifiedMaxRetries {
+		return 0
+	}
+	return a.MaxRetries + 1
+}
+
+func (a *App) initTargetEnvironment(ctx context.Context) error {
+	if a.targetEnvironment == nil {
+		targetEnvironment, err := wrapper.GetTargetEnvironment(ctx, a.getS3WrapperInput())
+		if err != nil {
+			return err
+		}
+		a.targetEnvironment = targetEnvironment
+	}
+	return nil
+}
+
+func (a *App) initS3Wrapper(ctx context.Context) error {
+	if a.s3Wrapper == nil {
+		s3Wrapper, err := wrapper.CreateS3Wrapper(ctx, a.getS3WrapperInput())
+		if err != nil {
+			return err
+		}
+		a.s3Wrapper = s3Wrapper
+	}
+	return nil
+}
+
+func (a *App) initCheckpointer(ctx context.Context) error {
+	if !a.Resume || a.checkpointer != nil {
+		return nil
+	}
+	if err := a.initTargetEnvironment(ctx); err != nil {
+		return err
+	}
+
+	dir := a.CheckpointDir
+	if dir == "" {
+		defaultDir, err := checkpoint.DefaultDir()
+		if err != nil {
+			return err
+		}
+		dir = defaultDir
+	}
+
+	store, err := checkpoint.NewStore(dir, checkpoint.Options{
+		AccountId:          a.targetEnvironment.AccountId,
+		Region:             a.targetEnvironment.Region,
+		EndpointUrl:        a.EndpointUrl,
+		Mode:               string(a.getPlanMode()),
+		OldVersionsOnly:    a.OldVersionsOnly,
+		KeyPrefixes:        a.KeyPrefixes,
+		IncludePatterns:    a.IncludePatterns,
+		ExcludePatterns:    a.ExcludePatterns,
+		OlderThan:          a.OlderThan,
+		NewerThan:          a.NewerThan,
+		KeepVersions:       a.KeepVersions,
+		KeepNoncurrentDays: a.KeepNoncurrentDays,
+		StorageClasses:     strings.Join(a.StorageClasses.Value(), ","),
+		MinSize:            a.MinSize,
+		MaxSize:            a.MaxSize,
+		Partitions:         a.Partitions,
+		SplitPoints:        a.SplitPoints,
+	})
+	if err != nil {
+		return err
+	}
+	a.checkpointer = store
+
+	io.Logger.Info().Msgf("Checkpoints are saved in %v. If the clearing stops, run the same command again to resume it.", dir)
+	return nil
+}
+
+// initTargetSource sets the key list or the inventory report as the targets if the --keysFrom or --inventory option is specified
+func (a *App) initTargetSource(ctx context.Context) error {
+	if a.targetSource != nil {
+		return nil
+	}
+
+	if a.KeysFrom != "" {
+		keyListReader, err := keylist.NewReader(a.KeysFrom, os.Stdin)
+		if err != nil {
+			return err
+		}
+		a.targetSource = keyListReader
+	}
+
+	if a.Inventory != "" {
+		var s3Client client.IS3
+		if strings.HasPrefix(a.Inventory, inventory.S3URIPrefix) {
+			c, err := wrapper.CreateS3Client(ctx, a.getS3WrapperInput())
+			if err != nil {
+				return err
+			}
+			s3Client = c
+		}
+		inventoryReader, err := inventory.NewReader(ctx, a.Inventory, s3Client, inventory.Options{
+			OldVersionsOnly: a.OldVersionsOnly,
+			Prefixes:        a.KeyPrefixes,
+			Filter:          a.objectFilter,
+		})
+		if err != nil {
+			return err
+		}
+		if sourceBucket := inventoryReader.Manifest().SourceBucket; sourceBucket != a.BucketNames.Value()[0] {
+			errMsg := fmt.Sprintf("The inventory report is for the bucket %v, but %v is specified with the -b option.\n", sourceBucket, a.BucketNames.Value()[0])
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		a.targetSource = inventoryReader
+	}
+	return nil
+}
+
+func (a *App) initBucketSelector() error {
+	if a.bucketSelector == nil {
+		selectorConfig := BucketSelectorConfig{
+			InteractiveMode:       a.InteractiveMode,
+			BucketNames:           a.BucketNames,
+			BucketPatterns:        a.BucketPatterns,
+			ExcludeBucketPatterns: a.ExcludeBucketPatterns,
+			Filter:                a.bucketFilter,
+			Protection:            a.bucketProtection,
+			Reporter:              a.reporter,
+		}
+		a.bucketSelector = NewBucketSelector(selectorConfig, a.s3Wrapper)
+	}
+	return nil
+}
+
+func (a *App) initBucketProcessor() error {
+	if a.bucketProcessor == nil {
+		// NOTE: The live display is hidden for the json output so that only the report is written to stdout.
+		processorConfig := BucketProcessorConfig{
+			TargetBuckets:              a.targetBuckets,
+			QuietMode:                  a.QuietMode || a.Output == report.FormatJSON,
+			ConcurrentMode:             a.ConcurrentMode,
+			ConcurrencyNumber:          a.ConcurrencyNumber,
+			ForceMode:                  a.ForceMode,
+			OldVersionsOnly:            a.OldVersionsOnly,
+			Prefixes:                   a.KeyPrefixes,
+			Partitions:                 a.Partitions,
+			SplitPoints:                a.SplitPoints,
+			DeleteWorkers:              a.DeleteWorkers,
+			MaxRequestsPerSecond:       a.MaxRequestsPerSecond,
+			MaxDeleteRequestsPerSecond: a.MaxDeleteRequestsPerSecond,
+			MaxInFlightRequests:        a.MaxInFlightRequests,
+			ContinueOnError:            a.ContinueOnError,
+			Filter:                     a.objectFilter,
+			DryRun:                     a.DryRun,
+			Recorder:                   a.targetRecorder,
+			Targets:                    a.targetSource,
+			Checkpointer:               a.checkpointer,
+			Manifest:                   a.deletionRecorder,
+			Reporter:                   a.reporter,
+			TargetsName:                a.getTargetsName(),
+		}
+		a.bucketProcessor = NewBucketProcessor(processorConfig, a.s3Wrapper)
+	}
+	return nil
+}
+
+func (a *App) validateOptions() error {
+	if !a.InteractiveMode && len(a.BucketNames.Value()) == 0 && len(a.BucketPatterns) == 0 {
+		errMsg := fmt.Sprintln("At least one bucket name must be specified in command options (-b), patterns (--bucketPattern) or a flow of the interactive mode (-i).")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.InteractiveMode && len(a.BucketNames.Value()) != 0 {
+		errMsg := fmt.Sprintln("When specifying -i, do not specify the -b option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if err := a.validateBucketPatterns(); err != nil {
+		return err
+	}
+	if err := a.validateBucketFilter(); err != nil {
+		return err
+	}
+	if err := a.validateSharedOptions(); err != nil {
+		return err
+	}
+	if a.ForceMode && a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("When specifying -o, do not specify the -f option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.DirectoryBucketsMode && a.TableBucketsMode {
+		errMsg := fmt.Sprintln("You cannot specify both -d and -t options.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.DirectoryBucketsMode && a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("You cannot specify both -d and -V options.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.TableBucketsMode && a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("You cannot specify both -t and -V options.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.DirectoryBucketsMode {
+		errMsg := fmt.Sprintln("Directory Buckets mode (-d) is not supported with non-AWS S3 endpoints.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.TableBucketsMode {
+		errMsg := fmt.Sprintln("Table Buckets mode (-t) is not supported with non-AWS S3 endpoints.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("Vector Buckets mode (-V) is not supported with non-AWS S3 endpoints.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if endpoint.IsCloudflareR2Endpoint(a.EndpointUrl) && a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("The -o option is not supported with Cloudflare R2.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.DirectoryBucketsMode && a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("When specifying -d, do not specify the -o option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.DirectoryBucketsMode && a.Region == "" {
+		io.Logger.Warn().Msg("You are in the Directory Buckets Mode `-d` to clear the Directory Buckets. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	}
+	if a.TableBucketsMode && a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("When specifying -t, do not specify the -o option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.TableBucketsMode && a.Region == "" {
+		io.Logger.Warn().Msg("You are in the Table Buckets Mode `-t` to clear the Table Buckets for S3 Tables. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	}
+	if a.VectorBucketsMode && a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("When specifying -V, do not specify the -o option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.VectorBucketsMode && a.Region == "" {
+		io.Logger.Warn().Msg("You are in the Vector Buckets Mode `-V` to clear the Vector Buckets for S3 Vectors. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	}
+	if len(a.KeyPrefixes) != 0 && a.TableBucketsMode {
+		errMsg := fmt.Sprintln("When specifying -t, do not specify the -k option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if len(a.KeyPrefixes) != 0 && a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying -k, do not specify the -f option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if len(a.KeyPrefixes) > 1 && a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("When specifying -V, specify the -k option only once.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if err := a.validateObjectFilter(); err != nil {
+		return err
+	}
+	if err := a.validateKeysFrom(); err != nil {
+		return err
+	}
+	if err := a.validateInventory(); err != nil {
+		return err
+	}
+	if err := a.validatePartitions(); err != nil {
+		return err
+	}
+	if a.Resume && a.DryRun {
+		errMsg := fmt.Sprintln("When specifying --resume, do not specify the --dryRun option or use the plan command because nothing is deleted.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if !a.Resume && a.CheckpointDir != "" {
+		errMsg := fmt.Sprintln("When specifying --checkpointDir, you must specify the --resume option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	for i, keyPrefix := range a.KeyPrefixes {
+		if a.DirectoryBucketsMode && !strings.HasSuffix(keyPrefix, "/") {
+			io.Logger.Warn().Msgf("The key prefix `%s` for the Directory Buckets does not end with a delimiter ( / ). It has been added automatically.", keyPrefix)
+			a.KeyPrefixes[i] += "/"
+		}
+	}
+	a.KeyPrefixes = dedupeKeyPrefixes(a.KeyPrefixes)
+	return nil
+}
+
+// validateBucketPatterns validates the --bucketPattern and --excludeBucketPattern options
+func (a *App) validateBucketPatterns() error {
+	if len(a.BucketPatterns) == 0 {
+		if len(a.ExcludeBucketPatterns) != 0 {
+			errMsg := fmt.Sprintln("When specifying --excludeBucketPattern, you must specify the --bucketPattern option.")
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		return nil
+	}
+	if a.InteractiveMode {
+		errMsg := fmt.Sprintln("When specifying --bucketPattern, do not specify the -i option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if len(a.BucketNames.Value()) != 0 {
+		errMsg := fmt.Sprintln("When specifying --bucketPattern, do not specify the -b option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if _, err := compileBucketPatterns(a.BucketPatterns); err != nil {
+		return err
+	}
+	if _, err := compileBucketPatterns(a.ExcludeBucketPatterns); err != nil {
+		return err
+	}
+	return nil
 }
 
-func (a *App) getAction() func(c *cli.Context) error {
-	return func(c *cli.Context) error {
-		io.Logger.Debug().Msg("Debug mode...")
-
-		if err := a.validateOptions(); err != nil {
-			return err
-		}
+// validateBucketFilter validates the options to narrow down the buckets, and builds the filter of them
+func (a *App) validateBucketFilter() error {
+	if a.CreatedBefore == "" && a.CreatedAfter == "" && len(a.BucketRegions) == 0 && len(a.Tags) == 0 {
+		return nil
+	}
+	if len(a.Tags) != 0 && (a.DirectoryBucketsMode || a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When specifying --tag, do not specify the -d, -t or -V option because only the tags of general purpose buckets can be read.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if slices.Contains(a.BucketRegions, "") {
+		errMsg := fmt.Sprintln("You must specify a non-empty region for the --bucketRegion option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
 
-		if err := a.initS3Wrapper(c.Context); err != nil {
-			return err
-		}
-		if err := a.initBucketSelector(); err != nil {
-			return err
+	now := time.Now()
+	filter := &BucketFilter{
+		Regions: a.BucketRegions,
+	}
+	if a.CreatedBefore != "" {
+		createdBefore, err := parseTimeOption(a.CreatedBefore, now)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --createdBefore option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got %q.\n", a.CreatedBefore)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
 		}
-
-		selectedBuckets, continuation, err := a.bucketSelector.SelectBuckets(c.Context)
+		filter.CreatedBefore = &createdBefore
+	}
+	if a.CreatedAfter != "" {
+		createdAfter, err := parseTimeOption(a.CreatedAfter, now)
 		if err != nil {
-			return err
+			errMsg := fmt.Sprintf("The --createdAfter option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got %q.\n", a.CreatedAfter)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
 		}
-		if !continuation {
-			return nil
+		filter.CreatedAfter = &createdAfter
+	}
+	if filter.CreatedBefore != nil && filter.CreatedAfter != nil && !filter.CreatedAfter.Before(*filter.CreatedBefore) {
+		errMsg := fmt.Sprintln("The time of --createdAfter must be before the time of --createdBefore, or no buckets match.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	for _, tag := range a.Tags {
+		condition, err := ParseTagCondition(tag)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --tag option must be key=value or key with the optional prefix %q, but got %q.\n", TagConditionNegationPrefix, tag)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
 		}
-		a.targetBuckets = append(a.targetBuckets, selectedBuckets...)
+		filter.Tags = append(filter.Tags, condition)
+	}
 
-		if err := a.initBucketProcessor(); err != nil {
-			return err
-		}
-		return a.bucketProcessor.Process(c.Context)
+	a.bucketFilter = filter
+	return nil
+}
+
+// validateProtection loads the protection config and the rules specified by the options, and sets the protection.
+// It must be called after the mode (-d, -t, -V) is set.
+func (a *App) validateProtection() error {
+	var config protection.Config
+	var err error
+	if a.ProtectionConfig != "" {
+		config, err = protection.Load(a.ProtectionConfig)
+	} else {
+		config, err = protection.LoadDefault()
+	}
+	if err != nil {
+		return err
+	}
+	config = config.Merge(protection.Config{
+		BucketPatterns: a.ProtectBucketPatterns,
+		Tags:           a.ProtectTags,
+		AccountIds:     a.ProtectAccountIds,
+	})
+	if len(config.BucketPatterns) == 0 && len(config.Tags) == 0 && len(config.AccountIds) == 0 {
+		return nil
 	}
+
+	// NOTE: A rule that cannot be checked must not be dropped, so that no protected bucket is cleared by mistake.
+	if len(config.Tags) != 0 && (a.DirectoryBucketsMode || a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When the protected tags are specified with --protectTag or the protection config, do not specify the -d, -t or -V option because only the tags of general purpose buckets can be read and the buckets cannot be checked. Specify another protection config with --protectionConfig if needed.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+
+	bucketProtection, err := NewBucketProtection(config)
+	if err != nil {
+		return err
+	}
+	a.bucketProtection = bucketProtection
+	return nil
 }
 
-func (a *App) initS3Wrapper(ctx context.Context) error {
-	if a.s3Wrapper == nil {
-		s3Wrapper, err := wrapper.CreateS3Wrapper(ctx, wrapper.CreateS3WrapperInput{
-			Region:               a.Region,
-			Profile:              a.Profile,
-			EndpointUrl:          a.EndpointUrl,
-			PathStyle:            a.PathStyle,
-			TableBucketsMode:     a.TableBucketsMode,
-			DirectoryBucketsMode: a.DirectoryBucketsMode,
-			VectorBucketsMode:    a.VectorBucketsMode,
-		})
-		if err != nil {
-			return err
+// validateAccountIds validates the --accountId option
+func (a *App) validateAccountIds() error {
+	if len(a.AccountIds) == 0 {
+		return nil
+	}
+	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) {
+		errMsg := fmt.Sprintln("When specifying --accountId, do not specify the endpoint URL of S3-compatible storage (-e) because its account cannot be identified.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	for _, accountId := range a.AccountIds {
+		if !accountIdPattern.MatchString(accountId) {
+			errMsg := fmt.Sprintf("The account ID %q for the --accountId option must be 12 digits.\n", accountId)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
 		}
-		a.s3Wrapper = s3Wrapper
 	}
 	return nil
 }
 
-func (a *App) initBucketSelector() error {
-	if a.bucketSelector == nil {
-		a.bucketSelector = NewBucketSelector(a.InteractiveMode, a.BucketNames, a.s3Wrapper)
+// checkAccountIds returns an error if the credentials are not for any of the accounts specified with --accountId
+func (a *App) checkAccountIds(ctx context.Context) error {
+	if len(a.AccountIds) == 0 {
+		return nil
+	}
+	if err := a.initTargetEnvironment(ctx); err != nil {
+		return err
+	}
+	if !slices.Contains(a.AccountIds, a.targetEnvironment.AccountId) {
+		return fmt.Errorf(
+			"AccountMismatchError: The credentials are for the account %v, but %v is specified with --accountId, so nothing has been cleared. Check the -p option.",
+			a.targetEnvironment.AccountId,
+			strings.Join(a.AccountIds, ", "),
+		)
 	}
 	return nil
 }
 
-func (a *App) initBucketProcessor() error {
-	if a.bucketProcessor == nil {
-		processorConfig := BucketProcessorConfig{
-			TargetBuckets:     a.targetBuckets,
-			QuietMode:         a.QuietMode,
-			ConcurrentMode:    a.ConcurrentMode,
-			ConcurrencyNumber: a.ConcurrencyNumber,
-			ForceMode:         a.ForceMode,
-			OldVersionsOnly:   a.OldVersionsOnly,
-			Prefix:            aws.String(a.KeyPrefix),
-		}
-		a.bucketProcessor = NewBucketProcessor(processorConfig, a.s3Wrapper)
+// checkAccount returns an error if the target account is not the one specified with --accountId or is protected.
+// NOTE: It must be called before any requests other than STS, so that nothing is read from a wrong account (e.g. an inventory report).
+func (a *App) checkAccount(ctx context.Context) error {
+	if err := a.checkAccountIds(ctx); err != nil {
+		return err
+	}
+	return a.checkProtectedAccount(ctx)
+}
+
+// checkProtectedAccount returns an error if the target account is protected
+func (a *App) checkProtectedAccount(ctx context.Context) error {
+	if a.bucketProtection == nil || !a.bucketProtection.HasAccountIds() {
+		return nil
+	}
+	if err := a.initTargetEnvironment(ctx); err != nil {
+		return err
+	}
+	return a.bucketProtection.CheckAccount(a.targetEnvironment.AccountId)
+}
+
+// validateKeysFrom validates the --keysFrom option after the filter options are validated
+func (a *App) validateKeysFrom() error {
+	if a.KeysFrom == "" {
+		return nil
+	}
+	if a.InteractiveMode || len(a.BucketNames.Value()) != 1 {
+		errMsg := fmt.Sprintln("When specifying --keysFrom, specify only one bucket with the -b option because the keys are for one bucket.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.TableBucketsMode || a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("When specifying --keysFrom, do not specify the -t or -V option because it is only for objects.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying --keysFrom, do not specify the -f option because only the objects in the key list are deleted.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.OldVersionsOnly || len(a.KeyPrefixes) != 0 || a.objectFilter != nil {
+		errMsg := fmt.Sprintln("When specifying --keysFrom, do not specify the -o, -k or filter options because the objects are not listed.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.Resume {
+		errMsg := fmt.Sprintln("When specifying --keysFrom, do not specify the --resume option because the checkpoints are only for listing.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
 	return nil
 }
 
-func (a *App) validateOptions() error {
-	if !a.InteractiveMode && len(a.BucketNames.Value()) == 0 {
-		errMsg := fmt.Sprintln("At least one bucket name must be specified in command options (-b) or a flow of the interactive mode (-i).")
+// validateInventory validates the --inventory option after the filter options are validated
+func (a *App) validateInventory() error {
+	if a.Inventory == "" {
+		return nil
+	}
+	if a.KeysFrom != "" {
+		errMsg := fmt.Sprintln("You cannot specify both --inventory and --keysFrom options.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.InteractiveMode && len(a.BucketNames.Value()) != 0 {
-		errMsg := fmt.Sprintln("When specifying -i, do not specify the -b option.")
+	if a.InteractiveMode || len(a.BucketNames.Value()) != 1 {
+		errMsg := fmt.Sprintln("When specifying --inventory, specify only the source bucket of the inventory report with the -b option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.ForceMode && a.OldVersionsOnly {
-		errMsg := fmt.Sprintln("When specifying -o, do not specify the -f option.")
+	if a.DirectoryBucketsMode || a.TableBucketsMode || a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("When specifying --inventory, do not specify the -d, -t or -V option because S3 Inventory is only for general purpose buckets.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.DirectoryBucketsMode && a.TableBucketsMode {
-		errMsg := fmt.Sprintln("You cannot specify both -d and -t options.")
+	if a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying --inventory, do not specify the -f option because the objects put after the inventory report was created are not deleted.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.DirectoryBucketsMode && a.VectorBucketsMode {
-		errMsg := fmt.Sprintln("You cannot specify both -d and -V options.")
+	if a.KeepVersions > 0 || a.KeepNoncurrentDays > 0 {
+		errMsg := fmt.Sprintln("When specifying --inventory, do not specify the --keepVersions or --keepNoncurrentDays option because the versions of a key can be in different inventory files.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.TableBucketsMode && a.VectorBucketsMode {
-		errMsg := fmt.Sprintln("You cannot specify both -t and -V options.")
+	if a.Resume {
+		errMsg := fmt.Sprintln("When specifying --inventory, do not specify the --resume option because the checkpoints are only for listing.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.PathStyle && a.DirectoryBucketsMode {
-		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -d option.")
+	return nil
+}
+
+// validatePartitions validates the --partitions and --splitAt options and sorts the split points
+func (a *App) validatePartitions() error {
+	if a.Partitions < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --partitions option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.PathStyle && a.TableBucketsMode {
-		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -t option.")
+	if a.Partitions <= 1 && len(a.SplitPoints) == 0 {
+		return nil
+	}
+	if a.Partitions > 1 && len(a.SplitPoints) > 0 {
+		errMsg := fmt.Sprintln("You cannot specify both --partitions and --splitAt options.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.PathStyle && a.VectorBucketsMode {
-		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -V option.")
+	if a.DirectoryBucketsMode || a.TableBucketsMode || a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("When specifying --partitions or --splitAt, do not specify the -d, -t or -V option because the keys must be listed in order from a key, which only general purpose buckets support.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.DirectoryBucketsMode {
-		errMsg := fmt.Sprintln("Directory Buckets mode (-d) is not supported with non-AWS S3 endpoints.")
+	if a.KeysFrom != "" || a.Inventory != "" {
+		errMsg := fmt.Sprintln("When specifying --partitions or --splitAt, do not specify the --keysFrom or --inventory option because the objects are not listed.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.TableBucketsMode {
-		errMsg := fmt.Sprintln("Table Buckets mode (-t) is not supported with non-AWS S3 endpoints.")
+	for _, splitPoint := range a.SplitPoints {
+		if splitPoint == "" {
+			errMsg := fmt.Sprintln("The --splitAt option must not be empty.")
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+	}
+	// NOTE: The key ranges are made from the split points in order.
+	slices.Sort(a.SplitPoints)
+	a.SplitPoints = slices.Compact(a.SplitPoints)
+	return nil
+}
+
+// dedupeKeyPrefixes drops the key prefixes under another prefix, because the objects under them are
+// cleared with the other prefix, and the prefixes cleared in parallel must not overlap each other.
+func dedupeKeyPrefixes(keyPrefixes []string) []string {
+	deduped := []string{}
+	for _, keyPrefix := range keyPrefixes {
+		covered := slices.ContainsFunc(keyPrefixes, func(other string) bool {
+			return other != keyPrefix && strings.HasPrefix(keyPrefix, other)
+		})
+		if covered || slices.Contains(deduped, keyPrefix) {
+			io.Logger.Warn().Msgf("The key prefix `%s` is specified more than once or under another key prefix, so it has been ignored.", keyPrefix)
+			continue
+		}
+		deduped = append(deduped, keyPrefix)
+	}
+	return deduped
+}
+
+// validateRetryOptions validates the options for the retries of the failed requests
+func (a *App) validateRetryOptions() error {
+	if a.MaxRetries < UnspecifiedMaxRetries {
+		errMsg := fmt.Sprintln("You must specify 0 or a positive number for the --maxRetries option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if !endpoint.IsAWSS3Endpoint(a.EndpointUrl) && a.VectorBucketsMode {
-		errMsg := fmt.Sprintln("Vector Buckets mode (-V) is not supported with non-AWS S3 endpoints.")
+	if a.RetryBaseDelay < 0 || a.RetryMaxDelay < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive duration for the --retryBaseDelay and --retryMaxDelay options.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if endpoint.IsCloudflareR2Endpoint(a.EndpointUrl) && a.OldVersionsOnly {
-		errMsg := fmt.Sprintln("The -o option is not supported with Cloudflare R2.")
+	if a.RetryBaseDelay > 0 && a.RetryMaxDelay > 0 && a.RetryBaseDelay > a.RetryMaxDelay {
+		errMsg := fmt.Sprintln("The --retryBaseDelay option must not be longer than the --retryMaxDelay option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.DirectoryBucketsMode && a.OldVersionsOnly {
-		errMsg := fmt.Sprintln("When specifying -d, do not specify the -o option.")
+	return nil
+}
+
+// stringList is the value of a flag that can be specified multiple times. Unlike cli.StringSlice,
+// it does not split a value by commas because key prefixes and patterns can have them.
+type stringList []string
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ", ")
+}
+
+// validateObjectFilter validates the filter options for the objects and sets the filter
+func (a *App) validateObjectFilter() error {
+	if a.KeepVersions < 0 || a.KeepNoncurrentDays < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --keepVersions and --keepNoncurrentDays options.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.DirectoryBucketsMode && a.Region == "" {
-		io.Logger.Warn().Msg("You are in the Directory Buckets Mode `-d` to clear the Directory Buckets. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	hasRetention := a.KeepVersions > 0 || a.KeepNoncurrentDays > 0
+	if hasRetention && !a.OldVersionsOnly {
+		errMsg := fmt.Sprintln("When specifying --keepVersions or --keepNoncurrentDays, you must specify the -o option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.TableBucketsMode && a.OldVersionsOnly {
-		errMsg := fmt.Sprintln("When specifying -t, do not specify the -o option.")
+	hasTimeFilter := a.OlderThan != "" || a.NewerThan != ""
+	var storageClasses []string
+	if a.StorageClasses != nil {
+		storageClasses = a.StorageClasses.Value()
+	}
+	hasAttributeFilter := len(storageClasses) > 0 || a.MinSize != "" || a.MaxSize != ""
+	hasKeyFilter := len(a.IncludePatterns) > 0 || len(a.ExcludePatterns) > 0
+	if !hasTimeFilter && !hasRetention && !hasAttributeFilter && !hasKeyFilter {
+		return nil
+	}
+	if hasTimeFilter && (a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When specifying --olderThan or --newerThan, do not specify the -t or -V option because they are only for objects.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.TableBucketsMode && a.ConcurrentMode {
-		errMsg := fmt.Sprintln("When specifying -t, do not specify the -c option because the throttling threshold for S3 Tables is very low.")
+	if hasTimeFilter && a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying --olderThan or --newerThan, do not specify the -f option because the bucket cannot be deleted with the remaining objects.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.TableBucketsMode && a.Region == "" {
-		io.Logger.Warn().Msg("You are in the Table Buckets Mode `-t` to clear the Table Buckets for S3 Tables. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	if hasAttributeFilter && (a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When specifying --storageClass, --minSize or --maxSize, do not specify the -t or -V option because they are only for objects.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.VectorBucketsMode && a.OldVersionsOnly {
-		errMsg := fmt.Sprintln("When specifying -V, do not specify the -o option.")
+	if hasAttributeFilter && a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying --storageClass, --minSize or --maxSize, do not specify the -f option because the bucket cannot be deleted with the remaining objects.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.VectorBucketsMode && a.Region == "" {
-		io.Logger.Warn().Msg("You are in the Vector Buckets Mode `-V` to clear the Vector Buckets for S3 Vectors. In this mode, operation across regions is not possible, but only in one region. You can specify the region with the `-r` option.")
+	if hasKeyFilter && (a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When specifying --include or --exclude, do not specify the -t or -V option because they are only for objects.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if hasKeyFilter && a.ForceMode {
+		errMsg := fmt.Sprintln("When specifying --include or --exclude, do not specify the -f option because the bucket cannot be deleted with the remaining objects.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+
+	now := time.Now()
+	filter := &client.ObjectFilter{
+		KeepVersions: a.KeepVersions,
+	}
+	if a.OlderThan != "" {
+		olderThan, err := parseTimeOption(a.OlderThan, now)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --olderThan option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got %q.\n", a.OlderThan)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.OlderThan = &olderThan
+	}
+	if a.NewerThan != "" {
+		newerThan, err := parseTimeOption(a.NewerThan, now)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --newerThan option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got %q.\n", a.NewerThan)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.NewerThan = &newerThan
+	}
+	if filter.OlderThan != nil && filter.NewerThan != nil && !filter.NewerThan.Before(*filter.OlderThan) {
+		errMsg := fmt.Sprintln("The time of --newerThan must be before the time of --olderThan, or no objects match.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.KeepNoncurrentDays > 0 {
+		keepNoncurrentSince := now.AddDate(0, 0, -a.KeepNoncurrentDays)
+		filter.KeepNoncurrentSince = &keepNoncurrentSince
+	}
+
+	knownStorageClasses := types.ObjectStorageClass("").Values()
+	for _, storageClass := range storageClasses {
+		storageClass = strings.ToUpper(strings.TrimSpace(storageClass))
+		if !slices.Contains(knownStorageClasses, types.ObjectStorageClass(storageClass)) {
+			errMsg := fmt.Sprintf("The --storageClass option must be one of %v, but got %q.\n", knownStorageClasses, storageClass)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.StorageClasses = append(filter.StorageClasses, storageClass)
+	}
+	if a.MinSize != "" {
+		minSize, err := parseSizeOption(a.MinSize)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --minSize option must be bytes or a size with a unit (e.g. 10MB), but got %q.\n", a.MinSize)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.MinSize = &minSize
+	}
+	if a.MaxSize != "" {
+		maxSize, err := parseSizeOption(a.MaxSize)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --maxSize option must be bytes or a size with a unit (e.g. 10MB), but got %q.\n", a.MaxSize)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.MaxSize = &maxSize
+	}
+	if filter.MinSize != nil && filter.MaxSize != nil && *filter.MinSize > *filter.MaxSize {
+		errMsg := fmt.Sprintln("The size of --minSize must not be larger than the size of --maxSize, or no objects match.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+
+	for _, pattern := range a.IncludePatterns {
+		compiled, err := client.CompileKeyPattern(pattern)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --include option must be a glob or a regular expression with the prefix %q, but got %q: %v\n", client.RegexPatternPrefix, pattern, err)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.Include = append(filter.Include, compiled)
+	}
+	for _, pattern := range a.ExcludePatterns {
+		compiled, err := client.CompileKeyPattern(pattern)
+		if err != nil {
+			errMsg := fmt.Sprintf("The --exclude option must be a glob or a regular expression with the prefix %q, but got %q: %v\n", client.RegexPatternPrefix, pattern, err)
+			return fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		filter.Exclude = append(filter.Exclude, compiled)
+	}
+
+	a.objectFilter = filter
+	return nil
+}
+
+// parseTimeOption parses an RFC3339 timestamp, or a duration before now with the day unit (d) in addition to
+// the units of time.ParseDuration (e.g. 7d, 12h, 1d12h).
+func parseTimeOption(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	days, duration, err := parseDurationOption(value)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return now.AddDate(0, 0, -days).Add(-duration), nil
+}
+
+// parseDurationOption parses a positive duration with the day unit (d) in addition to the units of time.ParseDuration
+// (e.g. 7d, 12h, 1d12h). The days are returned separately from the rest because a day is not always 24 hours.
+func parseDurationOption(value string) (int, time.Duration, error) {
+	var days int
+	durationStr := value
+	if index := strings.Index(value, "d"); index > 0 {
+		parsedDays, err := strconv.Atoi(value[:index])
+		if err != nil {
+			return 0, 0, err
+		}
+		days = parsedDays
+		durationStr = value[index+1:]
+	}
+
+	var duration time.Duration
+	if durationStr != "" {
+		parsedDuration, err := time.ParseDuration(durationStr)
+		if err != nil {
+			return 0, 0, err
+		}
+		duration = parsedDuration
+	}
+	if days < 0 || duration < 0 || (days == 0 && duration == 0) {
+		return 0, 0, fmt.Errorf("the duration must be positive: %v", value)
+	}
+
+	return days, duration, nil
+}
+
+// sizeUnits are the units of the size options. The longer suffixes must be checked first.
+var sizeUnits = []struct {
+	suffix string
+	bytes  int64
+}{
+	{suffix: "KIB", bytes: 1 << 10},
+	{suffix: "MIB", bytes: 1 << 20},
+	{suffix: "GIB", bytes: 1 << 30},
+	{suffix: "TIB", bytes: 1 << 40},
+	{suffix: "KB", bytes: 1000},
+	{suffix: "MB", bytes: 1000 * 1000},
+	{suffix: "GB", bytes: 1000 * 1000 * 1000},
+	{suffix: "TB", bytes: 1000 * 1000 * 1000 * 1000},
+	{suffix: "B", bytes: 1},
+}
+
+// parseSizeOption parses a size in bytes, or with a unit of KB, MB, GB and TB (powers of 1000) or KiB, MiB, GiB
+// and TiB (powers of 1024) case-insensitively (e.g. 1024, 10MB, 1GiB).
+func parseSizeOption(value string) (int64, error) {
+	number := strings.ToUpper(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range sizeUnits {
+		if strings.HasSuffix(number, unit.suffix) {
+			number = strings.TrimSpace(strings.TrimSuffix(number, unit.suffix))
+			multiplier = unit.bytes
+			break
+		}
+	}
+
+	size, err := strconv.ParseInt(number, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+	if size < 0 || size > math.MaxInt64/multiplier {
+		return 0, fmt.Errorf("the size is out of range: %v", value)
+	}
+
+	return size * multiplier, nil
+}
+
+// validateSweepOptions validates the options for the sweep command
+func (a *App) validateSweepOptions() error {
+	if a.ExpiresAtTagKey == "" || a.TTLTagKey == "" {
+		errMsg := fmt.Sprintln("You must specify a non-empty tag key for the --expiresAtTagKey and --ttlTagKey options.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	return a.validateSharedOptions()
+}
+
+// validateApplyOptions validates the options for the apply command after they are set from a plan
+func (a *App) validateApplyOptions() error {
+	return a.validateSharedOptions()
+}
+
+// validateSharedOptions validates the options shared by the commands. It must be called after the mode (-d, -t, -V) is set.
+func (a *App) validateSharedOptions() error {
+	if err := a.validateProtection(); err != nil {
+		return err
+	}
+	if err := a.validateAccountIds(); err != nil {
+		return err
+	}
+	if a.PathStyle && a.DirectoryBucketsMode {
+		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -d option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.PathStyle && a.TableBucketsMode {
+		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -t option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.PathStyle && a.VectorBucketsMode {
+		errMsg := fmt.Sprintln("When specifying -P (--pathStyle), do not specify the -V option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.TableBucketsMode && a.ConcurrentMode {
+		errMsg := fmt.Sprintln("When specifying -t, do not specify the -c option because the throttling threshold for S3 Tables is very low.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
 	if !a.ConcurrentMode && a.ConcurrencyNumber != UnspecifiedConcurrencyNumber {
 		errMsg := fmt.Sprintln("When specifying -n, you must specify the -c option.")
@@ -324,17 +1837,40 @@ func (a *App) validateOptions() error {
 		errMsg := fmt.Sprintln("You must specify a positive number for the -n option when specifying the -c option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.KeyPrefix != "" && a.TableBucketsMode {
-		errMsg := fmt.Sprintln("When specifying -t, do not specify the -k option.")
+	if a.ConfirmThreshold < 0 {
+		errMsg := fmt.Sprintln("You must specify 0 or a positive number for the --confirmThreshold option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.KeyPrefix != "" && a.ForceMode {
-		errMsg := fmt.Sprintln("When specifying -k, do not specify the -f option.")
+	if a.DeleteWorkers < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --deleteWorkers option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.DeleteWorkers > 0 && (a.TableBucketsMode || a.VectorBucketsMode) {
+		errMsg := fmt.Sprintln("When specifying --deleteWorkers, do not specify the -t or -V option because it is only for objects.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.MaxRequestsPerSecond < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --maxRequestsPerSecond option.")
 		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
-	if a.DirectoryBucketsMode && a.KeyPrefix != "" && !strings.HasSuffix(a.KeyPrefix, "/") {
-		io.Logger.Warn().Msgf("The key prefix `%s` for the Directory Buckets does not end with a delimiter ( / ). It has been added automatically.", a.KeyPrefix)
-		a.KeyPrefix += "/"
+	if a.MaxDeleteRequestsPerSecond < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --maxDeleteRequestsPerSecond option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if a.MaxInFlightRequests < 0 {
+		errMsg := fmt.Sprintln("You must specify a positive number for the --maxInFlightRequests option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if slices.Contains(a.RetryableErrorCodes, "") {
+		errMsg := fmt.Sprintln("You must specify a non-empty error code for the --retryableErrorCode option.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+	if err := a.validateRetryOptions(); err != nil {
+		return err
+	}
+	if a.ManifestFile != "" && a.DryRun {
+		errMsg := fmt.Sprintln("When specifying --manifest, do not specify the --dryRun option or use the plan command because nothing is deleted.")
+		return fmt.Errorf("InvalidOptionError: %v", errMsg)
 	}
 	return nil
 }
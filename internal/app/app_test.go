@@ -2,12 +2,24 @@ package app
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-to-k/cls3/internal/interrupt"
 	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/manifest"
+	"github.com/go-to-k/cls3/internal/plan"
+	"github.com/go-to-k/cls3/internal/report"
 	"github.com/go-to-k/cls3/internal/wrapper"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
@@ -15,16 +27,34 @@ import (
 	"go.uber.org/mock/gomock"
 )
 
+// isolateUserConfigDir points the user config directory to an empty one, so that the default protection config of
+// the developer is not read in the tests.
+func isolateUserConfigDir(t *testing.T) {
+	t.Helper()
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+}
+
 func Test_validateOptions(t *testing.T) {
+	isolateUserConfigDir(t)
+
 	var buf bytes.Buffer
 	logger := zerolog.New(&buf)
 	io.Logger = &logger
 
+	protectionConfigDir := t.TempDir()
+	protectionConfigPath := filepath.Join(protectionConfigDir, "protection.json")
+	if err := os.WriteFile(protectionConfigPath, []byte(`{"bucketPatterns": ["prod-*"], "tags": ["cls3:protected=true"]}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
-		name            string
-		app             *App
-		expectedErr     string
-		expectedWarning string
+		name                string
+		app                 *App
+		expectedErr         string
+		expectedWarning     string
+		expectedKeyPrefixes []string
 	}{
 		{
 			name: "error when no bucket names specified in non-interactive mode",
@@ -33,7 +63,7 @@ func Test_validateOptions(t *testing.T) {
 				BucketNames:       cli.NewStringSlice(),
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
-			expectedErr: "InvalidOptionError: At least one bucket name must be specified in command options (-b) or a flow of the interactive mode (-i).\n",
+			expectedErr: "InvalidOptionError: At least one bucket name must be specified in command options (-b), patterns (--bucketPattern) or a flow of the interactive mode (-i).\n",
 		},
 		{
 			name: "error when bucket names specified in interactive mode",
@@ -44,6 +74,64 @@ func Test_validateOptions(t *testing.T) {
 			},
 			expectedErr: "InvalidOptionError: When specifying -i, do not specify the -b option.\n",
 		},
+		{
+			name: "successfully validate options with bucket patterns",
+			app: &App{
+				BucketNames:           cli.NewStringSlice(),
+				BucketPatterns:        []string{"pr-1234-*", "regex:pr-[0-9]+-.*"},
+				ExcludeBucketPatterns: []string{"*-logs"},
+				Yes:                   true,
+				ConcurrencyNumber:     UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when bucket patterns specified in interactive mode",
+			app: &App{
+				InteractiveMode:   true,
+				BucketNames:       cli.NewStringSlice(),
+				BucketPatterns:    []string{"pr-*"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --bucketPattern, do not specify the -i option.\n",
+		},
+		{
+			name: "error when bucket patterns specified with bucket names",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				BucketPatterns:    []string{"pr-*"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --bucketPattern, do not specify the -b option.\n",
+		},
+		{
+			name: "error when exclude bucket patterns specified without bucket patterns",
+			app: &App{
+				BucketNames:           cli.NewStringSlice("bucket1"),
+				ExcludeBucketPatterns: []string{"*-logs"},
+				ConcurrencyNumber:     UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --excludeBucketPattern, you must specify the --bucketPattern option.\n",
+		},
+		{
+			name: "error when bucket pattern is an invalid regular expression",
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				BucketPatterns:    []string{"regex:pr-("},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The bucket pattern \"regex:pr-(\" is invalid: error parsing regexp: missing closing ): `pr-(`",
+		},
+		{
+			name: "error when exclude bucket pattern is an invalid regular expression",
+			app: &App{
+				BucketNames:           cli.NewStringSlice(),
+				BucketPatterns:        []string{"pr-*"},
+				ExcludeBucketPatterns: []string{"regex:["},
+				ConcurrencyNumber:     UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The bucket pattern \"regex:[\" is invalid: error parsing regexp: missing closing ]: `[`",
+		},
 		{
 			name: "error when both force mode and old versions only specified",
 			app: &App{
@@ -191,7 +279,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
 				TableBucketsMode:  true,
-				KeyPrefix:         "prefix",
+				KeyPrefixes:       []string{"prefix"},
 				Region:            "us-east-1",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
@@ -202,7 +290,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
 				ForceMode:         true,
-				KeyPrefix:         "prefix",
+				KeyPrefixes:       []string{"prefix"},
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
 			expectedErr: "InvalidOptionError: When specifying -k, do not specify the -f option.\n",
@@ -212,7 +300,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:          cli.NewStringSlice("bucket1"),
 				DirectoryBucketsMode: true,
-				KeyPrefix:            "prefix",
+				KeyPrefixes:          []string{"prefix"},
 				Region:               "us-east-1",
 				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
 			},
@@ -472,7 +560,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:          cli.NewStringSlice("bucket1"),
 				DirectoryBucketsMode: true,
-				KeyPrefix:            "test-prefix/",
+				KeyPrefixes:          []string{"test-prefix/"},
 				Region:               "us-east-1",
 				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
 			},
@@ -482,7 +570,7 @@ func Test_validateOptions(t *testing.T) {
 			name: "succeed with valid options - key prefix does not end with delimiter not in directory buckets mode",
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
-				KeyPrefix:         "test-prefix",
+				KeyPrefixes:       []string{"test-prefix"},
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
 			expectedErr: "",
@@ -491,7 +579,7 @@ func Test_validateOptions(t *testing.T) {
 			name: "succeed with valid options - key prefix with interactive mode",
 			app: &App{
 				InteractiveMode:   true,
-				KeyPrefix:         "prefix",
+				KeyPrefixes:       []string{"prefix"},
 				BucketNames:       cli.NewStringSlice(),
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
@@ -502,7 +590,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
 				OldVersionsOnly:   true,
-				KeyPrefix:         "prefix",
+				KeyPrefixes:       []string{"prefix"},
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
 			expectedErr: "",
@@ -556,7 +644,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
 				VectorBucketsMode: true,
-				KeyPrefix:         "prefix",
+				KeyPrefixes:       []string{"prefix"},
 				Region:            "us-east-1",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
@@ -621,7 +709,7 @@ func Test_validateOptions(t *testing.T) {
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
 				EndpointUrl:       "https://custom.endpoint.com",
-				KeyPrefix:         "test-prefix/",
+				KeyPrefixes:       []string{"test-prefix/"},
 				Region:            "us-east-1",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
@@ -755,138 +843,2183 @@ func Test_validateOptions(t *testing.T) {
 			},
 			expectedErr: "InvalidOptionError: Vector Buckets mode (-V) is not supported with non-AWS S3 endpoints.\n",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			buf.Reset()
-
-			originalKeyPrefix := tt.app.KeyPrefix
-			err := tt.app.validateOptions()
-
-			if tt.expectedErr != "" {
-				assert.EqualError(t, err, tt.expectedErr)
-			} else {
-				assert.NoError(t, err)
-			}
-
-			if tt.expectedWarning != "" {
-				assert.Equal(t, tt.expectedWarning, buf.String()[:len(buf.String())-1])
-			} else {
-				assert.Empty(t, buf.String())
-			}
-
-			// Check if the delimiter is added automatically when the key prefix does not end with delimiter in directory buckets mode
-			if originalKeyPrefix != "" && !strings.HasSuffix(originalKeyPrefix, "/") && tt.app.DirectoryBucketsMode {
-				assert.True(t, strings.HasSuffix(tt.app.KeyPrefix, "/"))
-			}
-			// Check if the delimiter is NOT added automatically when the key prefix ends with delimiter in directory buckets mode
-			if strings.HasSuffix(originalKeyPrefix, "/") && tt.app.DirectoryBucketsMode {
-				assert.Equal(t, tt.app.KeyPrefix, originalKeyPrefix)
-			}
-			// Check if the delimiter is NOT added automatically when the key prefix is not specified
-			if originalKeyPrefix == "" {
-				assert.Equal(t, tt.app.KeyPrefix, "")
-			}
-		})
-	}
-}
-
-func TestApp_getAction(t *testing.T) {
-	tests := []struct {
-		name                  string
-		prepareMockFn         func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor)
-		app                   *App
-		wantErr               bool
-		expectedErr           string
-		expectedTargetBuckets []string
-	}{
 		{
-			name: "successfully process buckets",
-			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
-				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1", "bucket2"}, true, nil)
-				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			name: "error when resume specified with dry run",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Resume:            true,
+				DryRun:            true,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
+			expectedErr: "InvalidOptionError: When specifying --resume, do not specify the --dryRun option or use the plan command because nothing is deleted.\n",
+		},
+		{
+			name: "error when checkpoint dir specified without resume",
 			app: &App{
-				BucketNames:       cli.NewStringSlice("bucket1", "bucket2"),
-				targetBuckets:     []string{},
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				CheckpointDir:     "checkpoints",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
-			wantErr:               false,
-			expectedTargetBuckets: []string{"bucket1", "bucket2"},
+			expectedErr: "InvalidOptionError: When specifying --checkpointDir, you must specify the --resume option.\n",
 		},
 		{
-			name: "error when select buckets fails",
-			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
-				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, fmt.Errorf("SelectBucketsError"))
+			name: "error when manifest specified with dry run",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ManifestFile:      "deleted.jsonl",
+				DryRun:            true,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
+			expectedErr: "InvalidOptionError: When specifying --manifest, do not specify the --dryRun option or use the plan command because nothing is deleted.\n",
+		},
+		{
+			name: "error when older than specified with force mode",
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
-				targetBuckets:     []string{},
+				ForceMode:         true,
+				OlderThan:         "7d",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
-			wantErr:               true,
-			expectedErr:           "SelectBucketsError",
-			expectedTargetBuckets: []string{},
+			expectedErr: "InvalidOptionError: When specifying --olderThan or --newerThan, do not specify the -f option because the bucket cannot be deleted with the remaining objects.\n",
 		},
 		{
-			name: "no error when select buckets returns no continuation",
-			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
-				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, nil)
+			name: "error when newer than specified with table buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				NewerThan:         "7d",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
+			expectedErr: "InvalidOptionError: When specifying --olderThan or --newerThan, do not specify the -t or -V option because they are only for objects.\n",
+		},
+		{
+			name: "error when older than is invalid",
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
-				targetBuckets:     []string{},
+				OlderThan:         "a week",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
-			wantErr:               false,
-			expectedTargetBuckets: []string{},
+			expectedErr: "InvalidOptionError: The --olderThan option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got \"a week\".\n",
 		},
 		{
-			name: "error when process buckets fails",
-			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
-				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
-				mp.EXPECT().Process(gomock.Any()).Return(fmt.Errorf("ProcessError"))
+			name: "error when newer than is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				NewerThan:         "2025-01-01",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
+			expectedErr: "InvalidOptionError: The --newerThan option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got \"2025-01-01\".\n",
+		},
+		{
+			name: "error when newer than is not before older than",
 			app: &App{
 				BucketNames:       cli.NewStringSlice("bucket1"),
-				targetBuckets:     []string{},
+				OlderThan:         "30d",
+				NewerThan:         "7d",
 				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
 			},
-			wantErr:               true,
-			expectedErr:           "ProcessError",
-			expectedTargetBuckets: []string{"bucket1"},
+			expectedErr: "InvalidOptionError: The time of --newerThan must be before the time of --olderThan, or no objects match.\n",
 		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			ctrl := gomock.NewController(t)
-			mockWrapper := wrapper.NewMockIWrapper(ctrl)
-			mockSelector := NewMockIBucketSelector(ctrl)
-			mockProcessor := NewMockIBucketProcessor(ctrl)
-
-			// Set up the mocks before calling prepareMockFn
-			tt.app.s3Wrapper = mockWrapper
-			tt.app.bucketSelector = mockSelector
-			tt.app.bucketProcessor = mockProcessor
-
-			// Set up the mock expectations
-			tt.prepareMockFn(mockWrapper, mockSelector, mockProcessor)
-
-			action := tt.app.getAction()
-			err := action(cli.NewContext(tt.app.Cli, &flag.FlagSet{}, nil))
-
-			if (err != nil) != tt.wantErr {
-				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
-				return
-			}
-			if tt.wantErr {
-				assert.EqualError(t, err, tt.expectedErr)
-			}
-
-			// Verify targetBuckets
-			assert.Equal(t, tt.expectedTargetBuckets, tt.app.targetBuckets, "targetBuckets mismatch")
+		{
+			name: "successfully validate options with bucket filter",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				CreatedBefore:     "7d",
+				CreatedAfter:      "2025-01-01T00:00:00Z",
+				BucketRegions:     []string{"us-east-1"},
+				Tags:              []string{"env=pr", "!protected"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "successfully validate options with bucket region in table buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				BucketRegions:     []string{"us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when tag specified in directory buckets mode",
+			app: &App{
+				BucketNames:          cli.NewStringSlice("bucket1"),
+				DirectoryBucketsMode: true,
+				Region:               "us-east-1",
+				Tags:                 []string{"env=pr"},
+				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --tag, do not specify the -d, -t or -V option because only the tags of general purpose buckets can be read.\n",
+		},
+		{
+			name: "error when tag specified in vector buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				VectorBucketsMode: true,
+				Region:            "us-east-1",
+				Tags:              []string{"env=pr"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --tag, do not specify the -d, -t or -V option because only the tags of general purpose buckets can be read.\n",
+		},
+		{
+			name: "error when tag is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Tags:              []string{"=pr"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --tag option must be key=value or key with the optional prefix \"!\", but got \"=pr\".\n",
+		},
+		{
+			name: "error when bucket region is empty",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				BucketRegions:     []string{""},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a non-empty region for the --bucketRegion option.\n",
+		},
+		{
+			name: "error when created before is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				CreatedBefore:     "a week",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --createdBefore option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got \"a week\".\n",
+		},
+		{
+			name: "error when created after is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				CreatedAfter:      "2025-01-01",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --createdAfter option must be a duration (e.g. 7d) or an RFC3339 timestamp, but got \"2025-01-01\".\n",
+		},
+		{
+			name: "error when created after is not before created before",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				CreatedBefore:     "30d",
+				CreatedAfter:      "7d",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The time of --createdAfter must be before the time of --createdBefore, or no buckets match.\n",
+		},
+		{
+			name: "succeed with valid options - older than and newer than",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				OlderThan:         "7d",
+				NewerThan:         "2025-01-01T00:00:00Z",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when keep versions specified without old versions only",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				KeepVersions:      3,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keepVersions or --keepNoncurrentDays, you must specify the -o option.\n",
+		},
+		{
+			name: "error when keep noncurrent days is negative",
+			app: &App{
+				BucketNames:        cli.NewStringSlice("bucket1"),
+				OldVersionsOnly:    true,
+				KeepNoncurrentDays: -1,
+				ConcurrencyNumber:  UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --keepVersions and --keepNoncurrentDays options.\n",
+		},
+		{
+			name: "succeed with valid options - keep versions and keep noncurrent days with old versions only",
+			app: &App{
+				BucketNames:        cli.NewStringSlice("bucket1"),
+				OldVersionsOnly:    true,
+				KeepVersions:       3,
+				KeepNoncurrentDays: 30,
+				ConcurrencyNumber:  UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when storage class specified with force mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ForceMode:         true,
+				StorageClasses:    cli.NewStringSlice("STANDARD"),
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --storageClass, --minSize or --maxSize, do not specify the -f option because the bucket cannot be deleted with the remaining objects.\n",
+		},
+		{
+			name: "error when min size specified with vector buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				VectorBucketsMode: true,
+				Region:            "us-east-1",
+				MinSize:           "1KB",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --storageClass, --minSize or --maxSize, do not specify the -t or -V option because they are only for objects.\n",
+		},
+		{
+			name: "error when storage class is unknown",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				StorageClasses:    cli.NewStringSlice("STANDARD", "COLD"),
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: fmt.Sprintf("InvalidOptionError: The --storageClass option must be one of %v, but got \"COLD\".\n", types.ObjectStorageClass("").Values()),
+		},
+		{
+			name: "error when max size is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MaxSize:           "10 bytes",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --maxSize option must be bytes or a size with a unit (e.g. 10MB), but got \"10 bytes\".\n",
+		},
+		{
+			name: "error when min size is larger than max size",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MinSize:           "1MiB",
+				MaxSize:           "1MB",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The size of --minSize must not be larger than the size of --maxSize, or no objects match.\n",
+		},
+		{
+			name: "succeed with valid options - storage classes and size range",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				StorageClasses:    cli.NewStringSlice("standard", "GLACIER"),
+				MinSize:           "1KB",
+				MaxSize:           "1GiB",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when multiple key prefixes specified with vector buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				VectorBucketsMode: true,
+				Region:            "us-east-1",
+				KeyPrefixes:       []string{"a-", "b-"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying -V, specify the -k option only once.\n",
+		},
+		{
+			name: "error when include pattern specified with table buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				IncludePatterns:   []string{"*.log"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --include or --exclude, do not specify the -t or -V option because they are only for objects.\n",
+		},
+		{
+			name: "error when exclude pattern specified with force mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ForceMode:         true,
+				ExcludePatterns:   []string{"*.log"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --include or --exclude, do not specify the -f option because the bucket cannot be deleted with the remaining objects.\n",
+		},
+		{
+			name: "error when include pattern is an invalid regular expression",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				IncludePatterns:   []string{"regex:^logs/(a"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --include option must be a glob or a regular expression with the prefix \"regex:\", but got \"regex:^logs/(a\": error parsing regexp: missing closing ): `^logs/(a`\n",
+		},
+		{
+			name: "succeed with valid options - include and exclude patterns",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				IncludePatterns:   []string{"builds/*.zip", "regex:^logs/[0-9]+$"},
+				ExcludePatterns:   []string{"*/release/*"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - overlapping key prefixes are ignored",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				KeyPrefixes:       []string{"logs/", "tmp/", "logs/2024/", "tmp/"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr:         "",
+			expectedWarning:     "{\"level\":\"warn\",\"message\":\"The key prefix `logs/2024/` is specified more than once or under another key prefix, so it has been ignored.\"}\n{\"level\":\"warn\",\"message\":\"The key prefix `tmp/` is specified more than once or under another key prefix, so it has been ignored.\"}",
+			expectedKeyPrefixes: []string{"logs/", "tmp/"},
+		},
+		{
+			name: "error when keys from specified with multiple buckets",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1", "bucket2"),
+				KeysFrom:          "keys.txt",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keysFrom, specify only one bucket with the -b option because the keys are for one bucket.\n",
+		},
+		{
+			name: "error when keys from specified with vector buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				VectorBucketsMode: true,
+				Region:            "us-east-1",
+				KeysFrom:          "keys.txt",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keysFrom, do not specify the -t or -V option because it is only for objects.\n",
+		},
+		{
+			name: "error when keys from specified with force mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ForceMode:         true,
+				KeysFrom:          "keys.txt",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keysFrom, do not specify the -f option because only the objects in the key list are deleted.\n",
+		},
+		{
+			name: "error when keys from specified with a filter option",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				OlderThan:         "7d",
+				KeysFrom:          "keys.txt",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keysFrom, do not specify the -o, -k or filter options because the objects are not listed.\n",
+		},
+		{
+			name: "error when keys from specified with resume",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Resume:            true,
+				KeysFrom:          "-",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --keysFrom, do not specify the --resume option because the checkpoints are only for listing.\n",
+		},
+		{
+			name: "succeed with valid options - keys from stdin with dry run",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				KeysFrom:          "-",
+				DryRun:            true,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when inventory specified with keys from",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Inventory:         "manifest.json",
+				KeysFrom:          "keys.txt",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You cannot specify both --inventory and --keysFrom options.\n",
+		},
+		{
+			name: "error when inventory specified without bucket names",
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				InteractiveMode:   true,
+				Inventory:         "manifest.json",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --inventory, specify only the source bucket of the inventory report with the -b option.\n",
+		},
+		{
+			name: "error when inventory specified with directory buckets mode",
+			app: &App{
+				BucketNames:          cli.NewStringSlice("bucket1"),
+				DirectoryBucketsMode: true,
+				Region:               "us-east-1",
+				Inventory:            "manifest.json",
+				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --inventory, do not specify the -d, -t or -V option because S3 Inventory is only for general purpose buckets.\n",
+		},
+		{
+			name: "error when inventory specified with force mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ForceMode:         true,
+				Inventory:         "manifest.json",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --inventory, do not specify the -f option because the objects put after the inventory report was created are not deleted.\n",
+		},
+		{
+			name: "error when inventory specified with keep versions",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				OldVersionsOnly:   true,
+				KeepVersions:      3,
+				Inventory:         "manifest.json",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --inventory, do not specify the --keepVersions or --keepNoncurrentDays option because the versions of a key can be in different inventory files.\n",
+		},
+		{
+			name: "error when inventory specified with resume",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Resume:            true,
+				Inventory:         "s3://inventory/manifest.json",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --inventory, do not specify the --resume option because the checkpoints are only for listing.\n",
+		},
+		{
+			name: "succeed with valid options - inventory with key prefixes and filters",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				OldVersionsOnly:   true,
+				KeyPrefixes:       []string{"logs/"},
+				OlderThan:         "7d",
+				IncludePatterns:   []string{"*.log"},
+				Inventory:         "manifest.json",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - partitions with key prefixes and resume",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				KeyPrefixes:       []string{"logs/", "tmp/"},
+				Partitions:        8,
+				Resume:            true,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - split at keys",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				SplitPoints:       []string{"m", "f", "m"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when partitions is negative",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Partitions:        -1,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --partitions option.\n",
+		},
+		{
+			name: "error when partitions specified with split at",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Partitions:        4,
+				SplitPoints:       []string{"m"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You cannot specify both --partitions and --splitAt options.\n",
+		},
+		{
+			name: "error when partitions specified with directory buckets mode",
+			app: &App{
+				BucketNames:          cli.NewStringSlice("bucket1"),
+				DirectoryBucketsMode: true,
+				Region:               "us-east-1",
+				Partitions:           4,
+				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --partitions or --splitAt, do not specify the -d, -t or -V option because the keys must be listed in order from a key, which only general purpose buckets support.\n",
+		},
+		{
+			name: "error when split at specified with keys from",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				KeysFrom:          "keys.txt",
+				SplitPoints:       []string{"m"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --partitions or --splitAt, do not specify the --keysFrom or --inventory option because the objects are not listed.\n",
+		},
+		{
+			name: "error when split at is empty",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				SplitPoints:       []string{""},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --splitAt option must not be empty.\n",
+		},
+		{
+			name: "succeed with valid options - delete workers",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				DeleteWorkers:     32,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when confirm threshold is negative",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ConfirmThreshold:  -1,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify 0 or a positive number for the --confirmThreshold option.\n",
+		},
+		{
+			name: "error when delete workers is negative",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				DeleteWorkers:     -1,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --deleteWorkers option.\n",
+		},
+		{
+			name: "error when delete workers specified with table buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				DeleteWorkers:     4,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --deleteWorkers, do not specify the -t or -V option because it is only for objects.\n",
+		},
+		{
+			name: "error when max requests per second is negative",
+			app: &App{
+				BucketNames:          cli.NewStringSlice("bucket1"),
+				MaxRequestsPerSecond: -1,
+				ConcurrencyNumber:    UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --maxRequestsPerSecond option.\n",
+		},
+		{
+			name: "error when max delete requests per second is negative",
+			app: &App{
+				BucketNames:                cli.NewStringSlice("bucket1"),
+				MaxDeleteRequestsPerSecond: -1,
+				ConcurrencyNumber:          UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --maxDeleteRequestsPerSecond option.\n",
+		},
+		{
+			name: "error when max in-flight requests is negative",
+			app: &App{
+				BucketNames:         cli.NewStringSlice("bucket1"),
+				MaxInFlightRequests: -1,
+				ConcurrencyNumber:   UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive number for the --maxInFlightRequests option.\n",
+		},
+		{
+			name: "error when retryable error code is empty",
+			app: &App{
+				BucketNames:         cli.NewStringSlice("bucket1"),
+				RetryableErrorCodes: []string{"RequestTimeout", ""},
+				ConcurrencyNumber:   UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a non-empty error code for the --retryableErrorCode option.\n",
+		},
+		{
+			name: "error when max retries is negative",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MaxRetries:        -2,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify 0 or a positive number for the --maxRetries option.\n",
+		},
+		{
+			name: "error when retry base delay is negative",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MaxRetries:        UnspecifiedMaxRetries,
+				RetryBaseDelay:    -time.Second,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: You must specify a positive duration for the --retryBaseDelay and --retryMaxDelay options.\n",
+		},
+		{
+			name: "error when retry base delay is longer than retry max delay",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MaxRetries:        UnspecifiedMaxRetries,
+				RetryBaseDelay:    10 * time.Second,
+				RetryMaxDelay:     5 * time.Second,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The --retryBaseDelay option must not be longer than the --retryMaxDelay option.\n",
+		},
+		{
+			name: "succeed with valid options - no retries",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				MaxRetries:        0,
+				RetryMaxDelay:     time.Second,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - retryable error codes",
+			app: &App{
+				BucketNames:         cli.NewStringSlice("bucket1"),
+				RetryableErrorCodes: []string{"RequestTimeout", "AccessDenied"},
+				ConcurrencyNumber:   UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - max requests per second with table buckets mode",
+			app: &App{
+				BucketNames:                cli.NewStringSlice("bucket1"),
+				TableBucketsMode:           true,
+				Region:                     "us-east-1",
+				MaxRequestsPerSecond:       10,
+				MaxDeleteRequestsPerSecond: 5,
+				ConcurrencyNumber:          UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - resume with checkpoint dir",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Resume:            true,
+				CheckpointDir:     "checkpoints",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "succeed with valid options - protection config and flags",
+			app: &App{
+				BucketNames:           cli.NewStringSlice("bucket1"),
+				ProtectionConfig:      protectionConfigPath,
+				ProtectBucketPatterns: []string{"*-logs"},
+				ProtectTags:           []string{"aws:cloudformation:stack-name"},
+				ProtectAccountIds:     []string{"210987654321"},
+				ConcurrencyNumber:     UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "",
+		},
+		{
+			name: "error when the protection config does not exist",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ProtectionConfig:  filepath.Join(protectionConfigDir, "not-exist.json"),
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "ProtectionConfigError: open " + filepath.Join(protectionConfigDir, "not-exist.json") + ": no such file or directory",
+		},
+		{
+			name: "error when a protected tag is negated",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ProtectTags:       []string{"!env=dev"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The protected tag \"!env=dev\" must be in the form of key=value or key.\n",
+		},
+		{
+			name: "error when a protected account id is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ProtectAccountIds: []string{"12345"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The protected account ID \"12345\" must be 12 digits.\n",
+		},
+		{
+			name: "error when an account id is invalid",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				AccountIds:        []string{"123456789012", "1234-5678-9012"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: The account ID \"1234-5678-9012\" for the --accountId option must be 12 digits.\n",
+		},
+		{
+			name: "error when an account id is specified for S3-compatible storage",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				AccountIds:        []string{"123456789012"},
+				EndpointUrl:       "http://localhost:9000",
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When specifying --accountId, do not specify the endpoint URL of S3-compatible storage (-e) because its account cannot be identified.\n",
+		},
+		{
+			name: "error when protected tags are specified in table buckets mode",
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				ProtectTags:       []string{"cls3:protected=true"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			expectedErr: "InvalidOptionError: When the protected tags are specified with --protectTag or the protection config, do not specify the -d, -t or -V option because only the tags of general purpose buckets can be read and the buckets cannot be checked. Specify another protection config with --protectionConfig if needed.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+
+			originalKeyPrefixes := slices.Clone(tt.app.KeyPrefixes)
+			err := tt.app.validateOptions()
+
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			if tt.expectedWarning != "" {
+				assert.Equal(t, tt.expectedWarning, buf.String()[:len(buf.String())-1])
+			} else {
+				assert.Empty(t, buf.String())
+			}
+
+			if tt.expectedKeyPrefixes != nil {
+				assert.Equal(t, tt.expectedKeyPrefixes, tt.app.KeyPrefixes)
+				return
+			}
+			for i, originalKeyPrefix := range originalKeyPrefixes {
+				// Check if the delimiter is added automatically when the key prefix does not end with delimiter in directory buckets mode
+				if !strings.HasSuffix(originalKeyPrefix, "/") && tt.app.DirectoryBucketsMode {
+					assert.True(t, strings.HasSuffix(tt.app.KeyPrefixes[i], "/"))
+				}
+				// Check if the delimiter is NOT added automatically when the key prefix ends with delimiter in directory buckets mode
+				if strings.HasSuffix(originalKeyPrefix, "/") && tt.app.DirectoryBucketsMode {
+					assert.Equal(t, tt.app.KeyPrefixes[i], originalKeyPrefix)
+				}
+			}
+			// Check if the delimiter is NOT added automatically when the key prefix is not specified
+			if len(originalKeyPrefixes) == 0 {
+				assert.Empty(t, tt.app.KeyPrefixes)
+			}
+		})
+	}
+}
+
+func TestApp_getS3WrapperInput_Retry(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		envs     map[string]string
+		expected wrapper.CreateS3WrapperInput
+	}{
+		{
+			name: "default retry options",
+			args: []string{"cls3", "-b", "bucket1"},
+			expected: wrapper.CreateS3WrapperInput{
+				MaxAttempts: 0,
+			},
+		},
+		{
+			name: "retry options by flags",
+			args: []string{"cls3", "-b", "bucket1", "--maxRetries", "0", "--retryBaseDelay", "500ms", "--retryMaxDelay", "1m"},
+			expected: wrapper.CreateS3WrapperInput{
+				MaxAttempts:    1,
+				RetryBaseDelay: 500 * time.Millisecond,
+				RetryMaxDelay:  time.Minute,
+			},
+		},
+		{
+			name: "retry options by environment variables",
+			args: []string{"cls3", "-b", "bucket1"},
+			envs: map[string]string{
+				"CLS3_MAX_RETRIES":      "5",
+				"CLS3_RETRY_BASE_DELAY": "2s",
+				"CLS3_RETRY_MAX_DELAY":  "30s",
+			},
+			expected: wrapper.CreateS3WrapperInput{
+				MaxAttempts:    6,
+				RetryBaseDelay: 2 * time.Second,
+				RetryMaxDelay:  30 * time.Second,
+			},
+		},
+		{
+			name: "flags take precedence over environment variables",
+			args: []string{"cls3", "-b", "bucket1", "--maxRetries", "1"},
+			envs: map[string]string{
+				"CLS3_MAX_RETRIES": "5",
+			},
+			expected: wrapper.CreateS3WrapperInput{
+				MaxAttempts: 2,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for key, value := range tt.envs {
+				t.Setenv(key, value)
+			}
+
+			app := NewApp("test")
+			var input wrapper.CreateS3WrapperInput
+			app.Cli.Action = func(c *cli.Context) error {
+				input = app.getS3WrapperInput()
+				return nil
+			}
+			err := app.Cli.Run(tt.args)
+			assert.NoError(t, err)
+
+			assert.Equal(t, tt.expected.MaxAttempts, input.MaxAttempts)
+			assert.Equal(t, tt.expected.RetryBaseDelay, input.RetryBaseDelay)
+			assert.Equal(t, tt.expected.RetryMaxDelay, input.RetryMaxDelay)
+		})
+	}
+}
+
+func TestApp_getExpectedBucketOwner(t *testing.T) {
+	tests := []struct {
+		name     string
+		app      *App
+		expected string
+	}{
+		{
+			name: "the account of the credentials when account ids are specified",
+			app: &App{
+				AccountIds:        []string{"123456789012"},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+			},
+			expected: "123456789012",
+		},
+		{
+			name: "empty when no account ids are specified",
+			app: &App{
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+			},
+			expected: "",
+		},
+		{
+			name: "empty before the account is checked",
+			app: &App{
+				AccountIds: []string{"123456789012"},
+			},
+			expected: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.app.getExpectedBucketOwner())
+			assert.Equal(t, tt.expected, tt.app.getS3WrapperInput().ExpectedBucketOwner)
+		})
+	}
+}
+
+func Test_parseTimeOption(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "days",
+			value: "7d",
+			want:  time.Date(2025, 6, 8, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "hours",
+			value: "12h",
+			want:  time.Date(2025, 6, 15, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:  "days and hours",
+			value: "1d12h30m",
+			want:  time.Date(2025, 6, 13, 23, 30, 0, 0, time.UTC),
+		},
+		{
+			name:  "RFC3339 timestamp",
+			value: "2025-01-01T09:00:00+09:00",
+			want:  time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "error for a negative duration",
+			value:   "-7d",
+			wantErr: true,
+		},
+		{
+			name:    "error for a zero duration",
+			value:   "0d",
+			wantErr: true,
+		},
+		{
+			name:    "error for an unknown unit",
+			value:   "1w",
+			wantErr: true,
+		},
+		{
+			name:    "error for a date without time",
+			value:   "2025-01-01",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseTimeOption(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.True(t, tt.want.Equal(got), "got %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_parseSizeOption(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{
+			name:  "bytes",
+			value: "1024",
+			want:  1024,
+		},
+		{
+			name:  "bytes with the unit",
+			value: "0B",
+			want:  0,
+		},
+		{
+			name:  "decimal unit",
+			value: "10MB",
+			want:  10 * 1000 * 1000,
+		},
+		{
+			name:  "binary unit in lower case",
+			value: "1gib",
+			want:  1 << 30,
+		},
+		{
+			name:  "unit with a space",
+			value: "5 KiB",
+			want:  5 * 1024,
+		},
+		{
+			name:    "error for a negative size",
+			value:   "-1",
+			wantErr: true,
+		},
+		{
+			name:    "error for a fractional size",
+			value:   "1.5GB",
+			wantErr: true,
+		},
+		{
+			name:    "error for an unknown unit",
+			value:   "1PB",
+			wantErr: true,
+		},
+		{
+			name:    "error for an overflow",
+			value:   "9000000TiB",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSizeOption(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr {
+				assert.Equal(t, tt.want, got)
+			}
+		})
+	}
+}
+
+func TestApp_getAction(t *testing.T) {
+	isolateUserConfigDir(t)
+	tests := []struct {
+		name                  string
+		prepareMockFn         func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer)
+		app                   *App
+		wantErr               bool
+		expectedErr           string
+		expectedTargetBuckets []string
+	}{
+		{
+			name: "successfully process buckets",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1", "bucket2"}, true, nil)
+				mc.EXPECT().Confirm(DeletionSummary{
+					Buckets:   []string{"bucket1", "bucket2"},
+					AccountId: "123456789012",
+					Region:    "us-east-1",
+					Mode:      "general",
+				}).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1", "bucket2"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1", "bucket2"},
+		},
+		{
+			name: "error when select buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, fmt.Errorf("SelectBucketsError"))
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "SelectBucketsError",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "no error when select buckets returns no continuation",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when process buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(fmt.Errorf("ProcessError"))
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "ProcessError",
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "confirm the deletion of buckets in the force mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(DeletionSummary{
+					Buckets:   []string{"bucket1"},
+					AccountId: "123456789012",
+					Region:    "us-east-1",
+					Mode:      "general",
+					ForceMode: true,
+				}).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ForceMode:         true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "no error and nothing processed when the deletion is canceled",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(false, nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "error and nothing processed when the deletion cannot be confirmed",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(false, fmt.Errorf("ConfirmError"))
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "ConfirmError",
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "successfully process buckets without the confirmation when yes specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Yes:               true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "successfully process buckets without the confirmation in the dry-run mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				DryRun:            true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "select the buckets again when the deletion is declined in the interactive mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				gomock.InOrder(
+					ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1", "bucket2"}, true, nil),
+					mc.EXPECT().Confirm(gomock.Any()).Return(false, nil),
+					ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket2"}, true, nil),
+					mc.EXPECT().Confirm(DeletionSummary{
+						Buckets:   []string{"bucket2"},
+						AccountId: "123456789012",
+						Region:    "us-east-1",
+						Mode:      "general",
+					}).Return(true, nil),
+					mp.EXPECT().Process(gomock.Any()).Return(nil),
+				)
+			},
+			app: &App{
+				InteractiveMode:   true,
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket2"},
+		},
+		{
+			name: "no error and nothing processed when the selection is canceled after the deletion is declined in the interactive mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				gomock.InOrder(
+					ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil),
+					mc.EXPECT().Confirm(gomock.Any()).Return(false, nil),
+					ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, nil),
+				)
+			},
+			app: &App{
+				InteractiveMode:   true,
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when the account is protected",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ProtectAccountIds: []string{"123456789012"},
+				targetBuckets:     []string{},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "ProtectedAccountError: The account 123456789012 is protected, so no buckets in it can be cleared.",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when the account is not the one specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				AccountIds:        []string{"210987654321", "345678901234"},
+				targetBuckets:     []string{},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "AccountMismatchError: The credentials are for the account 123456789012, but 210987654321, 345678901234 is specified with --accountId, so nothing has been cleared. Check the -p option.",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error before the inventory report is read when the account is not the one specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Inventory:         "not-exist/manifest.json",
+				AccountIds:        []string{"210987654321"},
+				targetBuckets:     []string{},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               true,
+			expectedErr:           "AccountMismatchError: The credentials are for the account 123456789012, but 210987654321 is specified with --accountId, so nothing has been cleared. Check the -p option.",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "successfully process buckets in the account specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				AccountIds:        []string{"210987654321", "123456789012"},
+				targetBuckets:     []string{},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "successfully process buckets in an account not protected",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				ProtectAccountIds: []string{"210987654321"},
+				targetBuckets:     []string{},
+				targetEnvironment: &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			mockSelector := NewMockIBucketSelector(ctrl)
+			mockProcessor := NewMockIBucketProcessor(ctrl)
+			mockConfirmer := NewMockIDeletionConfirmer(ctrl)
+
+			// Set up the mocks before calling prepareMockFn
+			tt.app.s3Wrapper = mockWrapper
+			tt.app.bucketSelector = mockSelector
+			tt.app.bucketProcessor = mockProcessor
+			tt.app.deletionConfirmer = mockConfirmer
+			if tt.app.targetEnvironment == nil {
+				tt.app.targetEnvironment = &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"}
+			}
+
+			// Set up the mock expectations
+			tt.prepareMockFn(mockWrapper, mockSelector, mockProcessor, mockConfirmer)
+
+			action := tt.app.getAction()
+			err := action(cli.NewContext(tt.app.Cli, &flag.FlagSet{}, nil))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			// Verify targetBuckets
+			assert.Equal(t, tt.expectedTargetBuckets, tt.app.targetBuckets, "targetBuckets mismatch")
+		})
+	}
+}
+
+func TestApp_getPlanAction(t *testing.T) {
+	isolateUserConfigDir(t)
+	io.NewLogger(false)
+
+	tests := []struct {
+		name          string
+		prepareMockFn func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor)
+		app           *App
+		wantErr       bool
+		expectedErr   string
+		wantPlanFile  bool
+		wantHeader    *plan.Header
+	}{
+		{
+			name: "successfully write a plan",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1", "bucket2"}, true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1", "bucket2"),
+				ForceMode:         true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:      false,
+			wantPlanFile: true,
+			wantHeader: &plan.Header{
+				Version:   plan.Version,
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				ForceMode: true,
+				Buckets:   []string{"bucket1", "bucket2"},
+			},
+		},
+		{
+			name: "successfully write a plan for table buckets",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"}, true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("test"),
+				TableBucketsMode:  true,
+				Region:            "us-east-1",
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:      false,
+			wantPlanFile: true,
+			wantHeader: &plan.Header{
+				Version:   plan.Version,
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeTable,
+				Buckets:   []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"},
+			},
+		},
+		{
+			name: "no plan when select buckets returns no continuation",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:      false,
+			wantPlanFile: false,
+		},
+		{
+			name: "error before the inventory report is read when the account is not the one specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				Inventory:         "not-exist/manifest.json",
+				AccountIds:        []string{"210987654321"},
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:      true,
+			expectedErr:  "AccountMismatchError: The credentials are for the account 123456789012, but 210987654321 is specified with --accountId, so nothing has been cleared. Check the -p option.",
+			wantPlanFile: false,
+		},
+		{
+			name: "remove the incomplete plan when process buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(fmt.Errorf("ProcessError"))
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice("bucket1"),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			wantErr:      true,
+			expectedErr:  "ProcessError",
+			wantPlanFile: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			mockSelector := NewMockIBucketSelector(ctrl)
+			mockProcessor := NewMockIBucketProcessor(ctrl)
+
+			tt.app.s3Wrapper = mockWrapper
+			tt.app.bucketSelector = mockSelector
+			tt.app.bucketProcessor = mockProcessor
+			tt.app.targetEnvironment = &wrapper.TargetEnvironment{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+			}
+			tt.app.PlanFile = filepath.Join(t.TempDir(), "plan.jsonl")
+
+			tt.prepareMockFn(mockWrapper, mockSelector, mockProcessor)
+
+			action := tt.app.getPlanAction()
+			err := action(cli.NewContext(tt.app.Cli, &flag.FlagSet{}, nil))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			_, statErr := os.Stat(tt.app.PlanFile)
+			assert.Equal(t, tt.wantPlanFile, statErr == nil, "plan file existence mismatch")
+			if !tt.wantPlanFile {
+				return
+			}
+
+			assert.True(t, tt.app.DryRun, "the plan must be made in the dry-run mode")
+			reader, err := plan.NewReader(tt.app.PlanFile)
+			assert.NoError(t, err)
+			header := reader.Header()
+			header.CreatedAt = tt.wantHeader.CreatedAt
+			assert.Equal(t, tt.wantHeader, header)
+		})
+	}
+}
+
+func TestApp_getApplyAction(t *testing.T) {
+	isolateUserConfigDir(t)
+	io.NewLogger(false)
+
+	writePlan := func(t *testing.T, header plan.Header) string {
+		path := filepath.Join(t.TempDir(), "plan.jsonl")
+		writer, err := plan.NewWriter(path, header)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := writer.Close(); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	tests := []struct {
+		name                  string
+		prepareMockFn         func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer)
+		app                   *App
+		header                *plan.Header // the plan file is not written if nil
+		args                  []string     // the plan file path is used if nil
+		wantErr               bool
+		expectedErr           string
+		expectedTargetBuckets []string
+		expectedForceMode     bool
+		expectedTableMode     bool
+	}{
+		{
+			name: "successfully apply a plan",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				mc.EXPECT().Confirm(DeletionSummary{
+					Buckets:   []string{"bucket1", "bucket2"},
+					AccountId: "123456789012",
+					Region:    "us-east-1",
+					Mode:      "general",
+					ForceMode: true,
+				}).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				ForceMode: true,
+				Buckets:   []string{"bucket1", "bucket2"},
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1", "bucket2"},
+			expectedForceMode:     true,
+		},
+		{
+			name: "successfully apply a plan for table buckets",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				mc.EXPECT().Confirm(DeletionSummary{
+					Buckets:   []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"},
+					AccountId: "123456789012",
+					Region:    "us-east-1",
+					Mode:      "table",
+				}).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeTable,
+				Buckets:   []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"},
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"},
+			expectedTableMode:     true,
+		},
+		{
+			name:          "error when no plan file is specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			args:                  []string{},
+			wantErr:               true,
+			expectedErr:           "InvalidOptionError: Specify one plan file to apply: cls3 apply <planFile>\n",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name:          "error when the plan is for another account",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "210987654321",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1"},
+			},
+			wantErr:               true,
+			expectedErr:           `PlanMismatchError: the plan was created for a different environment. account: "210987654321" in the plan, but "123456789012" now`,
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name:          "error when concurrent mode is specified for a plan for table buckets",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				ConcurrentMode:    true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeTable,
+				Buckets:   []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test"},
+			},
+			wantErr:               true,
+			expectedErr:           "InvalidOptionError: When specifying -t, do not specify the -c option because the throttling threshold for S3 Tables is very low.\n",
+			expectedTargetBuckets: []string{},
+			expectedTableMode:     true,
+		},
+		{
+			name: "error when a bucket in the plan is protected",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+						{BucketName: "prod-bucket", TargetBucket: "prod-bucket"},
+					},
+					nil,
+				)
+			},
+			app: &App{
+				BucketNames:           cli.NewStringSlice(),
+				ProtectBucketPatterns: []string{"prod-*"},
+				targetBuckets:         []string{},
+				ConcurrencyNumber:     UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1", "prod-bucket"},
+			},
+			wantErr:               true,
+			expectedErr:           "ProtectedBucketError: The following buckets are protected, so nothing has been cleared:\nprod-bucket: It matches the protected bucket pattern \"prod-*\".",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name:          "error when the account of the plan is protected",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				ProtectAccountIds: []string{"123456789012"},
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1"},
+			},
+			wantErr:               true,
+			expectedErr:           "ProtectedAccountError: The account 123456789012 is protected, so no buckets in it can be cleared.",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when process buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				mc.EXPECT().Confirm(gomock.Any()).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(fmt.Errorf("ProcessError"))
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1"},
+			},
+			wantErr:               true,
+			expectedErr:           "ProcessError",
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "no error and nothing processed when the deletion is canceled",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				mc.EXPECT().Confirm(gomock.Any()).Return(false, nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1"},
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "successfully apply a plan without the confirmation when yes specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				BucketNames:       cli.NewStringSlice(),
+				Yes:               true,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+			},
+			header: &plan.Header{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      plan.ModeGeneral,
+				Buckets:   []string{"bucket1"},
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			mockProcessor := NewMockIBucketProcessor(ctrl)
+			mockConfirmer := NewMockIDeletionConfirmer(ctrl)
+
+			tt.app.s3Wrapper = mockWrapper
+			tt.app.bucketProcessor = mockProcessor
+			tt.app.deletionConfirmer = mockConfirmer
+			tt.app.targetEnvironment = &wrapper.TargetEnvironment{
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+			}
+
+			tt.prepareMockFn(mockWrapper, mockProcessor, mockConfirmer)
+
+			args := tt.args
+			if tt.header != nil {
+				args = []string{writePlan(t, *tt.header)}
+			}
+			flagSet := &flag.FlagSet{}
+			if err := flagSet.Parse(args); err != nil {
+				t.Fatal(err)
+			}
+
+			action := tt.app.getApplyAction()
+			err := action(cli.NewContext(tt.app.Cli, flagSet, nil))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			assert.Equal(t, tt.expectedTargetBuckets, tt.app.targetBuckets, "targetBuckets mismatch")
+			assert.Equal(t, tt.expectedForceMode, tt.app.ForceMode, "ForceMode mismatch")
+			assert.Equal(t, tt.expectedTableMode, tt.app.TableBucketsMode, "TableBucketsMode mismatch")
+		})
+	}
+}
+
+func TestApp_getSweepAction(t *testing.T) {
+	isolateUserConfigDir(t)
+	io.NewLogger(false)
+
+	tests := []struct {
+		name                  string
+		prepareMockFn         func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer)
+		app                   *App
+		wantErr               bool
+		expectedErr           string
+		expectedTargetBuckets []string
+	}{
+		{
+			name: "successfully sweep expired buckets",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1", "bucket2"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(true, nil)
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1", "bucket2"},
+		},
+		{
+			name: "no error and nothing processed when the deletion is canceled",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return([]string{"bucket1"}, true, nil)
+				mc.EXPECT().Confirm(gomock.Any()).Return(false, nil)
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{"bucket1"},
+		},
+		{
+			name: "no error when no buckets have expired",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, nil)
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               false,
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when select buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+				ms.EXPECT().SelectBuckets(gomock.Any()).Return(nil, false, fmt.Errorf("SelectBucketsError"))
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               true,
+			expectedErr:           "SelectBucketsError",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error before listing buckets when the account is not the one specified",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				AccountIds:        []string{"210987654321"},
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               true,
+			expectedErr:           "AccountMismatchError: The credentials are for the account 123456789012, but 210987654321 is specified with --accountId, so nothing has been cleared. Check the -p option.",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when the tag key is empty",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				ExpiresAtTagKey:   "",
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               true,
+			expectedErr:           "InvalidOptionError: You must specify a non-empty tag key for the --expiresAtTagKey and --ttlTagKey options.\n",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when manifest is specified in the dry-run mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				DryRun:            true,
+				ManifestFile:      "manifest.jsonl",
+				targetBuckets:     []string{},
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               true,
+			expectedErr:           "InvalidOptionError: When specifying --manifest, do not specify the --dryRun option or use the plan command because nothing is deleted.\n",
+			expectedTargetBuckets: []string{},
+		},
+		{
+			name: "error when -n is specified without -c",
+			prepareMockFn: func(m *wrapper.MockIWrapper, ms *MockIBucketSelector, mp *MockIBucketProcessor, mc *MockIDeletionConfirmer) {
+			},
+			app: &App{
+				ExpiresAtTagKey:   DefaultExpiresAtTagKey,
+				TTLTagKey:         DefaultTTLTagKey,
+				targetBuckets:     []string{},
+				ConcurrencyNumber: 2,
+				MaxRetries:        UnspecifiedMaxRetries,
+			},
+			wantErr:               true,
+			expectedErr:           "InvalidOptionError: When specifying -n, you must specify the -c option.\n",
+			expectedTargetBuckets: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			mockSelector := NewMockIBucketSelector(ctrl)
+			mockProcessor := NewMockIBucketProcessor(ctrl)
+			mockConfirmer := NewMockIDeletionConfirmer(ctrl)
+
+			tt.app.s3Wrapper = mockWrapper
+			tt.app.bucketSelector = mockSelector
+			tt.app.bucketProcessor = mockProcessor
+			tt.app.deletionConfirmer = mockConfirmer
+			tt.app.targetEnvironment = &wrapper.TargetEnvironment{AccountId: "123456789012", Region: "us-east-1"}
+
+			tt.prepareMockFn(mockWrapper, mockSelector, mockProcessor, mockConfirmer)
+
+			action := tt.app.getSweepAction()
+			err := action(cli.NewContext(tt.app.Cli, &flag.FlagSet{}, nil))
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			assert.Equal(t, tt.expectedTargetBuckets, tt.app.targetBuckets, "targetBuckets mismatch")
+		})
+	}
+}
+
+func TestApp_processBuckets(t *testing.T) {
+	io.NewLogger(false)
+
+	tests := []struct {
+		name             string
+		prepareMockFn    func(mp *MockIBucketProcessor)
+		useManifestFile  bool
+		manifestDirError bool
+		wantErr          bool
+		expectedErr      string
+		wantManifestFile bool
+	}{
+		{
+			name: "process buckets without a manifest",
+			prepareMockFn: func(mp *MockIBucketProcessor) {
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name: "process buckets with the context stopped gracefully by the signals",
+			prepareMockFn: func(mp *MockIBucketProcessor) {
+				mp.EXPECT().Process(gomock.Any()).DoAndReturn(func(ctx context.Context) error {
+					if interrupt.Stopping(ctx) == nil {
+						return fmt.Errorf("the signals are not handled")
+					}
+					return nil
+				})
+			},
+			wantErr: false,
+		},
+		{
+			name: "write the manifest and its failures file",
+			prepareMockFn: func(mp *MockIBucketProcessor) {
+				mp.EXPECT().Process(gomock.Any()).Return(nil)
+			},
+			useManifestFile:  true,
+			wantErr:          false,
+			wantManifestFile: true,
+		},
+		{
+			name: "keep the manifest even if the process fails",
+			prepareMockFn: func(mp *MockIBucketProcessor) {
+				mp.EXPECT().Process(gomock.Any()).Return(fmt.Errorf("ProcessError"))
+			},
+			useManifestFile:  true,
+			wantErr:          true,
+			expectedErr:      "ProcessError",
+			wantManifestFile: true,
+		},
+		{
+			name:             "error when the manifest cannot be created",
+			prepareMockFn:    func(mp *MockIBucketProcessor) {},
+			useManifestFile:  true,
+			manifestDirError: true,
+			wantErr:          true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockProcessor := NewMockIBucketProcessor(ctrl)
+			tt.prepareMockFn(mockProcessor)
+
+			app := &App{
+				bucketProcessor: mockProcessor,
+			}
+			if tt.useManifestFile {
+				app.ManifestFile = filepath.Join(t.TempDir(), "deleted.jsonl")
+				if tt.manifestDirError {
+					app.ManifestFile = filepath.Join(t.TempDir(), "not-exist", "deleted.jsonl")
+				}
+			}
+
+			err := app.processBuckets(context.Background())
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.expectedErr != "" {
+				assert.EqualError(t, err, tt.expectedErr)
+			}
+
+			if tt.wantManifestFile {
+				assert.NotNil(t, app.deletionRecorder)
+				assert.FileExists(t, app.ManifestFile)
+				assert.FileExists(t, manifest.FailuresPath(app.ManifestFile))
+			} else {
+				assert.Nil(t, app.deletionRecorder)
+			}
+		})
+	}
+}
+
+func TestApp_withReport(t *testing.T) {
+	io.NewLogger(false)
+
+	tests := []struct {
+		name          string
+		output        string
+		useReportFile bool
+		actionErr     error
+		wantErr       bool
+		expectedErr   string
+		wantStdout    bool
+		wantStatus    report.Status
+		wantExitCode  int
+	}{
+		{
+			name:       "write the report to stdout for the json output",
+			output:     report.FormatJSON,
+			wantStdout: true,
+			wantStatus: report.StatusSucceeded,
+		},
+		{
+			name:          "write the report only to the report file for the text output",
+			output:        report.FormatText,
+			useReportFile: true,
+			wantStatus:    report.StatusSucceeded,
+		},
+		{
+			name:          "write the report of a failed run and return the error",
+			output:        report.FormatJSON,
+			useReportFile: true,
+			actionErr:     fmt.Errorf("ProcessError"),
+			wantErr:       true,
+			expectedErr:   "ProcessError",
+			wantStdout:    true,
+			wantStatus:    report.StatusFailed,
+			wantExitCode:  ExitCodeFailed,
+		},
+		{
+			name:   "write the report of a partially failed run with continue on error",
+			output: report.FormatJSON,
+			actionErr: &BucketsError{
+				BucketsCount: 2,
+				Errors:       []BucketError{{Bucket: "bucket2", Err: fmt.Errorf("ClearBucketError")}},
+			},
+			wantErr:      true,
+			expectedErr:  "BucketsError: 1 of 2 buckets were not cleared.\nbucket2: ClearBucketError",
+			wantStdout:   true,
+			wantStatus:   report.StatusPartiallyFailed,
+			wantExitCode: ExitCodePartiallyFailed,
+		},
+		{
+			name:   "write the report of an interrupted run with the exit code of the process",
+			output: report.FormatJSON,
+			actionErr: &BucketsError{
+				BucketsCount: 2,
+				Errors:       []BucketError{{Bucket: "bucket2", Err: interrupt.ErrInterrupted}},
+			},
+			wantErr:      true,
+			expectedErr:  "BucketsError: 1 of 2 buckets were not cleared.\nbucket2: " + interrupt.ErrInterrupted.Error(),
+			wantStdout:   true,
+			wantStatus:   report.StatusInterrupted,
+			wantExitCode: interrupt.ExitCode,
+		},
+		{
+			name:   "no report for the text output without the report file",
+			output: report.FormatText,
+		},
+		{
+			name:        "error for an unknown output format",
+			output:      "yaml",
+			wantErr:     true,
+			expectedErr: "InvalidOptionError: The --output option must be text or json, but got \"yaml\".\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stdout := &bytes.Buffer{}
+			app := &App{
+				Cli:    &cli.App{Writer: stdout},
+				Output: tt.output,
+				Region: "us-east-1",
+			}
+			if tt.useReportFile {
+				app.ReportFile = filepath.Join(t.TempDir(), "report.json")
+			}
+
+			action := app.withReport(func(c *cli.Context) error {
+				if app.reporter != nil {
+					now := time.Now()
+					app.reporter.AddBucket("bucket1", &wrapper.ClearBucketOutput{ObjectsCount: 3}, nil, now, now)
+				}
+				return tt.actionErr
+			})
+			err := action(cli.NewContext(app.Cli, &flag.FlagSet{}, nil))
+
+			if tt.wantErr {
+				assert.EqualError(t, err, tt.expectedErr)
+			} else {
+				assert.NoError(t, err)
+			}
+
+			reports := []string{}
+			if tt.wantStdout {
+				reports = append(reports, stdout.String())
+			} else {
+				assert.Empty(t, stdout.String())
+			}
+			if tt.useReportFile {
+				data, readErr := os.ReadFile(app.ReportFile)
+				assert.NoError(t, readErr)
+				reports = append(reports, string(data))
+			}
+
+			for _, data := range reports {
+				got := report.Report{}
+				assert.NoError(t, json.Unmarshal([]byte(data), &got))
+				assert.Equal(t, tt.wantStatus, got.Status)
+				assert.Equal(t, tt.wantExitCode, got.ExitCode)
+				assert.Equal(t, 1, got.Summary.BucketsCount)
+				assert.Equal(t, int64(3), got.Summary.ObjectsCount)
+				assert.Equal(t, "general", got.Buckets[0].Type)
+				assert.Equal(t, "us-east-1", got.Buckets[0].Region)
+			}
 		})
 	}
 }
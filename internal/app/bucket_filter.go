@@ -0,0 +1,135 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"golang.org/x/sync/errgroup"
+)
+
+// BucketFilterConcurrency is the number of the buckets whose tags are read in parallel.
+const BucketFilterConcurrency = 32
+
+// TagConditionNegationPrefix is the prefix of a tag condition to match the buckets without the tag.
+const TagConditionNegationPrefix = "!"
+
+// BucketFilter narrows down the selected buckets by their attributes. A bucket must match all conditions.
+type BucketFilter struct {
+	CreatedBefore *time.Time
+	CreatedAfter  *time.Time
+	Regions       []string // any region if empty
+	Tags          []TagCondition
+}
+
+// TagCondition is a condition of a tag of a bucket in the form of key=value, or key for any value.
+// With the "!" prefix, it matches the buckets without the tag.
+type TagCondition struct {
+	Key     string
+	Value   *string // any value if nil
+	Negated bool
+}
+
+// ParseTagCondition parses key=value, key, !key=value or !key
+func ParseTagCondition(value string) (TagCondition, error) {
+	condition := TagCondition{}
+	value, condition.Negated = strings.CutPrefix(value, TagConditionNegationPrefix)
+
+	key, tagValue, hasValue := strings.Cut(value, "=")
+	if key == "" {
+		return TagCondition{}, fmt.Errorf("the tag key must not be empty")
+	}
+	condition.Key = key
+	if hasValue {
+		condition.Value = &tagValue
+	}
+	return condition, nil
+}
+
+// String returns the condition in the same form as ParseTagCondition parses
+func (c TagCondition) String() string {
+	condition := c.Key
+	if c.Value != nil {
+		condition += "=" + *c.Value
+	}
+	if c.Negated {
+		condition = TagConditionNegationPrefix + condition
+	}
+	return condition
+}
+
+func (c TagCondition) matches(tags map[string]string) bool {
+	value, ok := tags[c.Key]
+	hasTag := ok && (c.Value == nil || *c.Value == value)
+	return hasTag != c.Negated
+}
+
+// Filter returns the buckets matching all conditions in the same order.
+// The tags are read in parallel only for the buckets matching the other conditions.
+func (f *BucketFilter) Filter(
+	ctx context.Context,
+	s3Wrapper wrapper.IWrapper,
+	buckets []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) ([]wrapper.ListBucketNamesFilteredByKeywordOutput, error) {
+	candidates := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
+	for _, bucket := range buckets {
+		if f.matchesAttributes(bucket) {
+			candidates = append(candidates, bucket)
+		}
+	}
+	if len(f.Tags) == 0 {
+		return candidates, nil
+	}
+
+	matched := make([]bool, len(candidates))
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(BucketFilterConcurrency)
+	for i, bucket := range candidates {
+		eg.Go(func() error {
+			tags, err := s3Wrapper.GetBucketTags(egCtx, bucket)
+			if err != nil {
+				return err
+			}
+			matched[i] = f.matchesTags(tags)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+
+	filtered := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
+	for i, bucket := range candidates {
+		if matched[i] {
+			filtered = append(filtered, bucket)
+		}
+	}
+	return filtered, nil
+}
+
+// matchesAttributes returns true if the bucket matches the conditions other than the tags.
+// NOTE: A bucket whose creation date or region is unknown does not match the conditions of them.
+func (f *BucketFilter) matchesAttributes(bucket wrapper.ListBucketNamesFilteredByKeywordOutput) bool {
+	if f.CreatedBefore != nil && (bucket.CreationDate == nil || !bucket.CreationDate.Before(*f.CreatedBefore)) {
+		return false
+	}
+	if f.CreatedAfter != nil && (bucket.CreationDate == nil || !bucket.CreationDate.After(*f.CreatedAfter)) {
+		return false
+	}
+	if len(f.Regions) != 0 && !slices.Contains(f.Regions, bucket.Region) {
+		return false
+	}
+	return true
+}
+
+func (f *BucketFilter) matchesTags(tags map[string]string) bool {
+	for _, condition := range f.Tags {
+		if !condition.matches(tags) {
+			return false
+		}
+	}
+	return true
+}
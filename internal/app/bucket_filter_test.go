@@ -0,0 +1,229 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestParseTagCondition(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    TagCondition
+		wantErr bool
+	}{
+		{
+			name:  "key and value",
+			value: "env=pr",
+			want:  TagCondition{Key: "env", Value: aws.String("pr")},
+		},
+		{
+			name:  "key and empty value",
+			value: "env=",
+			want:  TagCondition{Key: "env", Value: aws.String("")},
+		},
+		{
+			name:  "key and value with equal signs",
+			value: "query=a=b",
+			want:  TagCondition{Key: "query", Value: aws.String("a=b")},
+		},
+		{
+			name:  "key only",
+			value: "cls3:ephemeral",
+			want:  TagCondition{Key: "cls3:ephemeral"},
+		},
+		{
+			name:  "negated key and value",
+			value: "!env=prod",
+			want:  TagCondition{Key: "env", Value: aws.String("prod"), Negated: true},
+		},
+		{
+			name:  "negated key only",
+			value: "!protected",
+			want:  TagCondition{Key: "protected", Negated: true},
+		},
+		{
+			name:    "empty key",
+			value:   "=pr",
+			wantErr: true,
+		},
+		{
+			name:    "negated empty key",
+			value:   "!",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTagCondition(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.value, got.String())
+		})
+	}
+}
+
+func TestTagCondition_matches(t *testing.T) {
+	tags := map[string]string{"env": "pr", "owner": ""}
+
+	tests := []struct {
+		name      string
+		condition TagCondition
+		want      bool
+	}{
+		{
+			name:      "same value",
+			condition: TagCondition{Key: "env", Value: aws.String("pr")},
+			want:      true,
+		},
+		{
+			name:      "different value",
+			condition: TagCondition{Key: "env", Value: aws.String("prod")},
+			want:      false,
+		},
+		{
+			name:      "empty value",
+			condition: TagCondition{Key: "owner", Value: aws.String("")},
+			want:      true,
+		},
+		{
+			name:      "any value",
+			condition: TagCondition{Key: "env"},
+			want:      true,
+		},
+		{
+			name:      "no tag",
+			condition: TagCondition{Key: "team"},
+			want:      false,
+		},
+		{
+			name:      "negated different value",
+			condition: TagCondition{Key: "env", Value: aws.String("prod"), Negated: true},
+			want:      true,
+		},
+		{
+			name:      "negated same value",
+			condition: TagCondition{Key: "env", Value: aws.String("pr"), Negated: true},
+			want:      false,
+		},
+		{
+			name:      "negated no tag",
+			condition: TagCondition{Key: "team", Negated: true},
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.condition.matches(tags))
+		})
+	}
+}
+
+func TestBucketFilter_Filter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	oldBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{
+		BucketName:   "old",
+		TargetBucket: "old",
+		CreationDate: aws.Time(now.AddDate(0, -3, 0)),
+		Region:       "us-east-1",
+	}
+	newBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{
+		BucketName:   "new",
+		TargetBucket: "new",
+		CreationDate: aws.Time(now.AddDate(0, 0, -1)),
+		Region:       "ap-northeast-1",
+	}
+	unknownBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{
+		BucketName:   "unknown",
+		TargetBucket: "unknown",
+	}
+	buckets := []wrapper.ListBucketNamesFilteredByKeywordOutput{oldBucket, newBucket, unknownBucket}
+
+	tests := []struct {
+		name          string
+		filter        *BucketFilter
+		prepareMockFn func(m *wrapper.MockIWrapper)
+		want          []wrapper.ListBucketNamesFilteredByKeywordOutput
+		wantErr       string
+	}{
+		{
+			name:          "created before",
+			filter:        &BucketFilter{CreatedBefore: aws.Time(now.AddDate(0, -1, 0))},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {},
+			want:          []wrapper.ListBucketNamesFilteredByKeywordOutput{oldBucket},
+		},
+		{
+			name:          "created after",
+			filter:        &BucketFilter{CreatedAfter: aws.Time(now.AddDate(0, -1, 0))},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {},
+			want:          []wrapper.ListBucketNamesFilteredByKeywordOutput{newBucket},
+		},
+		{
+			name:          "regions",
+			filter:        &BucketFilter{Regions: []string{"ap-northeast-1", "eu-west-1"}},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {},
+			want:          []wrapper.ListBucketNamesFilteredByKeywordOutput{newBucket},
+		},
+		{
+			name: "tags",
+			filter: &BucketFilter{Tags: []TagCondition{
+				{Key: "env", Value: aws.String("pr")},
+				{Key: "protected", Negated: true},
+			}},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().GetBucketTags(gomock.Any(), oldBucket).Return(map[string]string{"env": "pr"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), newBucket).Return(map[string]string{"env": "pr", "protected": "true"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), unknownBucket).Return(map[string]string{}, nil)
+			},
+			want: []wrapper.ListBucketNamesFilteredByKeywordOutput{oldBucket},
+		},
+		{
+			name: "tags only for the buckets matching the other conditions",
+			filter: &BucketFilter{
+				Regions: []string{"us-east-1"},
+				Tags:    []TagCondition{{Key: "env"}},
+			},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().GetBucketTags(gomock.Any(), oldBucket).Return(map[string]string{"env": "pr"}, nil)
+			},
+			want: []wrapper.ListBucketNamesFilteredByKeywordOutput{oldBucket},
+		},
+		{
+			name:   "error when getting tags fails",
+			filter: &BucketFilter{Tags: []TagCondition{{Key: "env"}}},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().GetBucketTags(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("GetBucketTagsError")).AnyTimes()
+			},
+			wantErr: "GetBucketTagsError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			tt.prepareMockFn(mockWrapper)
+
+			got, err := tt.filter.Filter(context.Background(), mockWrapper, buckets)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -3,9 +3,19 @@ package app
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/interrupt"
 	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/report"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"golang.org/x/sync/errgroup"
 	"golang.org/x/sync/semaphore"
 )
@@ -16,23 +26,107 @@ type IBucketProcessor interface {
 
 var _ IBucketProcessor = (*BucketProcessor)(nil)
 
+const (
+	ExitCodeSucceeded       = 0
+	ExitCodeFailed          = 1 // all buckets failed, or the run failed regardless of the buckets
+	ExitCodePartiallyFailed = 2 // some buckets failed or were partially cleared with --continueOnError
+)
+
+// ExitCode returns the exit code for the error of a run
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitCodeSucceeded
+	}
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		return interrupt.ExitCode
+	}
+	var bucketsErr *BucketsError
+	if errors.As(err, &bucketsErr) {
+		return bucketsErr.exitCode()
+	}
+	return ExitCodeFailed
+}
+
+var _ error = (*BucketsError)(nil)
+
+// BucketsError provides the errors of all buckets that failed or were partially cleared with --continueOnError
+type BucketsError struct {
+	BucketsCount int // the number of all target buckets
+	Errors       []BucketError
+}
+
+// BucketError is the error of a bucket
+type BucketError struct {
+	Bucket string
+	Err    error
+}
+
+func (e *BucketsError) Error() string {
+	msg := fmt.Sprintf("BucketsError: %v of %v buckets were not cleared.", len(e.Errors), e.BucketsCount)
+	for _, bucketErr := range e.Errors {
+		msg += fmt.Sprintf("\n%v: %v", bucketErr.Bucket, bucketErr.Err)
+	}
+	return msg
+}
+
+func (e *BucketsError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, bucketErr := range e.Errors {
+		errs = append(errs, bucketErr.Err)
+	}
+	return errs
+}
+
+// exitCode returns ExitCodeFailed only if all buckets failed without deleting anything
+func (e *BucketsError) exitCode() int {
+	if len(e.Errors) < e.BucketsCount {
+		return ExitCodePartiallyFailed
+	}
+	for _, bucketErr := range e.Errors {
+		var partialErr *wrapper.PartiallyClearedError
+		if errors.As(bucketErr.Err, &partialErr) {
+			return ExitCodePartiallyFailed
+		}
+	}
+	return ExitCodeFailed
+}
+
 // BucketProcessorConfig contains all configuration parameters for bucket processing operations
 type BucketProcessorConfig struct {
-	TargetBuckets     []string
-	QuietMode         bool
-	ConcurrentMode    bool
-	ConcurrencyNumber int
-	ForceMode         bool
-	OldVersionsOnly   bool
-	Prefix            *string // not used for S3Tables
+	TargetBuckets              []string
+	QuietMode                  bool
+	ConcurrentMode             bool
+	ConcurrencyNumber          int
+	ForceMode                  bool
+	OldVersionsOnly            bool
+	Prefixes                   []string             // not used for S3Tables
+	Partitions                 int                  // lists each bucket in parallel key ranges, only for S3
+	SplitPoints                []string             // lists each bucket in parallel key ranges split after these keys, only for S3
+	DeleteWorkers              int                  // number of the parallel deletions for each bucket, only for S3
+	MaxRequestsPerSecond       int                  // limits the requests of all buckets if positive
+	MaxDeleteRequestsPerSecond int                  // limits the delete requests of all buckets if positive
+	MaxInFlightRequests        int                  // limits the requests in flight of all buckets if positive, and they are not limited until throttled otherwise
+	ContinueOnError            bool                 // clears all buckets even if some of them fail, and returns a BucketsError
+	Filter                     *client.ObjectFilter // narrows down the objects to be deleted, only for S3
+	DryRun                     bool
+	Recorder                   wrapper.ITargetRecorder   // records the targets in the dry-run mode (e.g. to a plan file)
+	Targets                    wrapper.ITargetSource     // deletes only these targets (e.g. from a plan file)
+	Checkpointer               wrapper.ICheckpointer     // saves the progress of each bucket to resume it (e.g. to checkpoint files)
+	Manifest                   wrapper.IDeletionRecorder // records the deleted targets (e.g. to a manifest file)
+	Reporter                   *report.Reporter          // collects the results of the buckets for the report if not nil
+	TargetsName                string                    // the name of the counted targets (objects, tables or indexes) in the status line
 }
 
 // BucketProcessor handles all bucket processing operations
 type BucketProcessor struct {
-	config    BucketProcessorConfig
-	s3Wrapper wrapper.IWrapper
-	state     IClearingState
-	display   IDisplayManager
+	config         BucketProcessorConfig
+	s3Wrapper      wrapper.IWrapper
+	state          IClearingState
+	display        IDisplayManager
+	rateController *client.RateController // shared by all buckets to slow down together on throttling
+	outputs        map[string]*wrapper.ClearBucketOutput
+	errs           map[string]error
+	outputsMtx     sync.Mutex
 }
 
 // NewBucketProcessor creates a new BucketProcessor instance
@@ -40,15 +134,26 @@ func NewBucketProcessor(
 	config BucketProcessorConfig,
 	s3Wrapper wrapper.IWrapper,
 ) *BucketProcessor {
-	state := NewClearingState(config.TargetBuckets, s3Wrapper, config.ForceMode)
+	// NOTE: The live display is not needed in the dry-run mode because nothing is deleted.
+	// Instead, the results are displayed as a summary of all buckets at the end.
+	if config.DryRun {
+		config.QuietMode = true
+	}
+
+	rateController := client.NewRateController(config.MaxInFlightRequests)
+
+	state := NewClearingState(config.TargetBuckets, s3Wrapper, config.ForceMode, rateController, config.TargetsName)
 
 	display := NewDisplayManager(state, config.QuietMode)
 
 	return &BucketProcessor{
-		config:    config,
-		s3Wrapper: s3Wrapper,
-		state:     state,
-		display:   display,
+		config:         config,
+		s3Wrapper:      s3Wrapper,
+		state:          state,
+		display:        display,
+		rateController: rateController,
+		outputs:        make(map[string]*wrapper.ClearBucketOutput, len(config.TargetBuckets)),
+		errs:           make(map[string]error, len(config.TargetBuckets)),
 	}
 }
 
@@ -57,8 +162,59 @@ func (p *BucketProcessor) Process(ctx context.Context) error {
 	concurrencyNumber := p.determineConcurrencyNumber()
 	io.Logger.Info().Msgf("Number of buckets:  %v", len(p.config.TargetBuckets))
 	io.Logger.Info().Msgf("Concurrency number: %v", concurrencyNumber)
-	if p.config.Prefix != nil {
-		io.Logger.Info().Msgf("Key prefix: %v", *p.config.Prefix)
+	if len(p.config.Prefixes) > 0 {
+		io.Logger.Info().Msgf("Key prefixes: %v", strings.Join(p.config.Prefixes, ", "))
+	}
+	if p.config.Partitions > 1 {
+		io.Logger.Info().Msgf("Partitions: %v", p.config.Partitions)
+	}
+	if len(p.config.SplitPoints) > 0 {
+		io.Logger.Info().Msgf("Split at: %v", strings.Join(p.config.SplitPoints, ", "))
+	}
+	if p.config.DeleteWorkers > 0 {
+		io.Logger.Info().Msgf("Delete workers: %v", p.config.DeleteWorkers)
+	}
+	if p.config.MaxRequestsPerSecond > 0 {
+		io.Logger.Info().Msgf("Max requests per second: %v", p.config.MaxRequestsPerSecond)
+	}
+	if p.config.MaxDeleteRequestsPerSecond > 0 {
+		io.Logger.Info().Msgf("Max delete requests per second: %v", p.config.MaxDeleteRequestsPerSecond)
+	}
+	if p.config.MaxInFlightRequests > 0 {
+		io.Logger.Info().Msgf("Max requests in flight: %v", p.config.MaxInFlightRequests)
+	}
+	if p.config.Filter != nil && p.config.Filter.OlderThan != nil {
+		io.Logger.Info().Msgf("Older than: %v", p.config.Filter.OlderThan.Format(time.RFC3339))
+	}
+	if p.config.Filter != nil && p.config.Filter.NewerThan != nil {
+		io.Logger.Info().Msgf("Newer than: %v", p.config.Filter.NewerThan.Format(time.RFC3339))
+	}
+	if p.config.Filter != nil && p.config.Filter.KeepVersions > 0 {
+		io.Logger.Info().Msgf("Keep noncurrent versions: %v", p.config.Filter.KeepVersions)
+	}
+	if p.config.Filter != nil && p.config.Filter.KeepNoncurrentSince != nil {
+		io.Logger.Info().Msgf("Keep versions noncurrent since: %v", p.config.Filter.KeepNoncurrentSince.Format(time.RFC3339))
+	}
+	if p.config.Filter != nil && len(p.config.Filter.Include) > 0 {
+		io.Logger.Info().Msgf("Include patterns: %v", p.config.Filter.Include)
+	}
+	if p.config.Filter != nil && len(p.config.Filter.Exclude) > 0 {
+		io.Logger.Info().Msgf("Exclude patterns: %v", p.config.Filter.Exclude)
+	}
+	if p.config.Filter != nil && len(p.config.Filter.StorageClasses) > 0 {
+		io.Logger.Info().Msgf("Storage classes: %v", strings.Join(p.config.Filter.StorageClasses, ", "))
+	}
+	if p.config.Filter != nil && p.config.Filter.MinSize != nil {
+		io.Logger.Info().Msgf("Min size: %v bytes", *p.config.Filter.MinSize)
+	}
+	if p.config.Filter != nil && p.config.Filter.MaxSize != nil {
+		io.Logger.Info().Msgf("Max size: %v bytes", *p.config.Filter.MaxSize)
+	}
+	if p.config.ContinueOnError {
+		io.Logger.Info().Msg("Continue on error: all buckets will be cleared even if some of them fail.")
+	}
+	if p.config.DryRun {
+		io.Logger.Info().Msg("Dry run: nothing will be deleted.")
 	}
 
 	for _, bucket := range p.config.TargetBuckets {
@@ -67,13 +223,89 @@ func (p *BucketProcessor) Process(ctx context.Context) error {
 		}
 	}
 
+	if p.rateController != nil {
+		ctx = client.WithRateController(ctx, p.rateController)
+	}
+	if limiters := p.requestRateLimiters(); len(limiters) > 0 {
+		ctx = client.WithRequestRateLimiters(ctx, limiters...)
+	}
+
 	p.display.Start(p.config.TargetBuckets)
 
 	if err := p.clearBuckets(ctx, concurrencyNumber); err != nil {
 		return err
 	}
 
-	return p.display.Finish(p.config.TargetBuckets)
+	// NOTE: The final messages are only for the cleared buckets. The others are shown in the summary.
+	if err := p.display.Finish(p.clearedBuckets()); err != nil {
+		return err
+	}
+
+	if p.config.DryRun {
+		if err := p.outputDryRunSummary(); err != nil {
+			return err
+		}
+	}
+	// NOTE: When interrupted, the summary shows how far each bucket got.
+	if p.config.ContinueOnError || interrupt.IsStopping(ctx) {
+		return p.outputResultSummary()
+	}
+	return nil
+}
+
+// outputResultSummary displays the status of all buckets as a table, and returns a BucketsError
+// if any of them failed or were partially cleared
+func (p *BucketProcessor) outputResultSummary() error {
+	var table strings.Builder
+	w := tabwriter.NewWriter(&table, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "BUCKET\tSTATUS\tDELETED\tERROR")
+
+	bucketsErr := &BucketsError{BucketsCount: len(p.config.TargetBuckets)}
+	for _, bucket := range p.config.TargetBuckets {
+		status, deleted, message := report.StatusSucceeded, p.getOutput(bucket).TotalCount(), ""
+		if err := p.getError(bucket); err != nil {
+			bucketsErr.Errors = append(bucketsErr.Errors, BucketError{Bucket: bucket, Err: err})
+			// NOTE: The error of DeleteObjects has a line for each object, so only the first line is shown.
+			message, _, _ = strings.Cut(err.Error(), "\n")
+			var partialErr *wrapper.PartiallyClearedError
+			if errors.As(err, &partialErr) {
+				status, deleted = report.StatusPartiallyCleared, partialErr.Output.TotalCount()
+			} else {
+				status, deleted = report.StatusFailed, 0
+			}
+		}
+		fmt.Fprintf(w, "%v\t%v\t%v\t%v\n", bucket, status, deleted, message)
+	}
+	w.Flush()
+
+	io.Logger.Info().Msgf("Summary of %v buckets:\n%v", len(p.config.TargetBuckets), strings.TrimSuffix(table.String(), "\n"))
+
+	if len(bucketsErr.Errors) == 0 {
+		return nil
+	}
+	return bucketsErr
+}
+
+// outputDryRunSummary displays the targets that would be deleted for all buckets
+func (p *BucketProcessor) outputDryRunSummary() error {
+	for _, bucket := range p.config.TargetBuckets {
+		if err := p.s3Wrapper.OutputDryRunMessage(bucket, p.getOutput(bucket)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// requestRateLimiters creates the limiters of the requests shared by all buckets
+func (p *BucketProcessor) requestRateLimiters() []*client.RequestRateLimiter {
+	limiters := []*client.RequestRateLimiter{}
+	if p.config.MaxRequestsPerSecond > 0 {
+		limiters = append(limiters, client.NewRequestRateLimiter(p.config.MaxRequestsPerSecond, false))
+	}
+	if p.config.MaxDeleteRequestsPerSecond > 0 {
+		limiters = append(limiters, client.NewRequestRateLimiter(p.config.MaxDeleteRequestsPerSecond, true))
+	}
+	return limiters
 }
 
 // determineConcurrencyNumber calculates the appropriate concurrency number
@@ -105,25 +337,63 @@ func (p *BucketProcessor) clearBuckets(ctx context.Context, concurrencyNumber in
 
 		clearEg.Go(func() error {
 			defer sem.Release(1)
-			return p.clearSingleBucket(ctx, bucket)
+			err := p.clearSingleBucket(ctx, bucket)
+			// NOTE: The errors are collected to be returned after all buckets are cleared.
+			if p.config.ContinueOnError {
+				return nil
+			}
+			return err
 		})
 	}
 
-	return clearEg.Wait()
+	err := clearEg.Wait()
+	// NOTE: The errors of the interrupted buckets are returned after the summary of all buckets.
+	if interrupt.IsStopping(ctx) {
+		return nil
+	}
+	return err
 }
 
 // clearSingleBucket processes a single bucket
 func (p *BucketProcessor) clearSingleBucket(ctx context.Context, bucket string) error {
 	clearingCountCh, clearingCompletedCh := p.state.GetChannelsForBucket(bucket)
 
-	err := p.s3Wrapper.ClearBucket(ctx, wrapper.ClearBucketInput{
-		TargetBucket:    bucket,
-		ForceMode:       p.config.ForceMode,
-		OldVersionsOnly: p.config.OldVersionsOnly,
-		QuietMode:       p.config.QuietMode,
-		ClearingCountCh: clearingCountCh,
-		Prefix:          p.config.Prefix,
-	})
+	startedAt := time.Now()
+	var output *wrapper.ClearBucketOutput
+	var err error
+	// NOTE: The buckets not started before the interruption are not cleared at all.
+	if interrupt.IsStopping(ctx) {
+		err = &client.ClientError{
+			ResourceName: aws.String(bucket),
+			Err:          interrupt.ErrInterrupted,
+		}
+	} else {
+		output, err = p.s3Wrapper.ClearBucket(ctx, wrapper.ClearBucketInput{
+			TargetBucket:    bucket,
+			ForceMode:       p.config.ForceMode,
+			OldVersionsOnly: p.config.OldVersionsOnly,
+			QuietMode:       p.config.QuietMode,
+			ClearingCountCh: clearingCountCh,
+			Prefixes:        p.config.Prefixes,
+			Partitions:      p.config.Partitions,
+			SplitPoints:     p.config.SplitPoints,
+			DeleteWorkers:   p.config.DeleteWorkers,
+			Filter:          p.config.Filter,
+			DryRun:          p.config.DryRun,
+			Recorder:        p.config.Recorder,
+			Targets:         p.config.Targets,
+			Checkpointer:    p.config.Checkpointer,
+			Manifest:        p.config.Manifest,
+		})
+	}
+	if err == nil {
+		p.setOutput(bucket, output)
+	} else {
+		p.setError(bucket, err)
+	}
+	if p.config.Reporter != nil {
+		p.config.Reporter.AddBucket(bucket, output, err, startedAt, time.Now())
+	}
 
 	close(clearingCountCh)
 	if !p.config.QuietMode {
@@ -133,3 +403,47 @@ func (p *BucketProcessor) clearSingleBucket(ctx context.Context, bucket string)
 
 	return err
 }
+
+// setOutput stores the result of clearing a bucket
+func (p *BucketProcessor) setOutput(bucket string, output *wrapper.ClearBucketOutput) {
+	p.outputsMtx.Lock()
+	defer p.outputsMtx.Unlock()
+	p.outputs[bucket] = output
+}
+
+// getOutput returns the result of clearing a bucket
+func (p *BucketProcessor) getOutput(bucket string) *wrapper.ClearBucketOutput {
+	p.outputsMtx.Lock()
+	defer p.outputsMtx.Unlock()
+	if output, ok := p.outputs[bucket]; ok && output != nil {
+		return output
+	}
+	return &wrapper.ClearBucketOutput{}
+}
+
+// clearedBuckets returns the target buckets cleared without errors
+func (p *BucketProcessor) clearedBuckets() []string {
+	p.outputsMtx.Lock()
+	defer p.outputsMtx.Unlock()
+	buckets := make([]string, 0, len(p.config.TargetBuckets))
+	for _, bucket := range p.config.TargetBuckets {
+		if _, ok := p.errs[bucket]; !ok {
+			buckets = append(buckets, bucket)
+		}
+	}
+	return buckets
+}
+
+// setError stores the error of clearing a bucket
+func (p *BucketProcessor) setError(bucket string, err error) {
+	p.outputsMtx.Lock()
+	defer p.outputsMtx.Unlock()
+	p.errs[bucket] = err
+}
+
+// getError returns the error of clearing a bucket, or nil if it succeeded
+func (p *BucketProcessor) getError(bucket string) error {
+	p.outputsMtx.Lock()
+	defer p.outputsMtx.Unlock()
+	return p.errs[bucket]
+}
@@ -5,7 +5,12 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/interrupt"
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/report"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -70,6 +75,42 @@ func Test_determineConcurrencyNumber(t *testing.T) {
 	}
 }
 
+func Test_requestRateLimiters(t *testing.T) {
+	tests := []struct {
+		name           string
+		config         BucketProcessorConfig
+		expectedNumber int
+	}{
+		{
+			name:           "return no limiters when no limit is specified",
+			config:         BucketProcessorConfig{},
+			expectedNumber: 0,
+		},
+		{
+			name: "return a limiter for all requests",
+			config: BucketProcessorConfig{
+				MaxRequestsPerSecond: 100,
+			},
+			expectedNumber: 1,
+		},
+		{
+			name: "return limiters for all requests and delete requests",
+			config: BucketProcessorConfig{
+				MaxRequestsPerSecond:       100,
+				MaxDeleteRequestsPerSecond: 10,
+			},
+			expectedNumber: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			processor := &BucketProcessor{config: tt.config}
+			assert.Len(t, processor.requestRateLimiters(), tt.expectedNumber)
+		})
+	}
+}
+
 func TestBucketProcessor_Process(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -102,7 +143,7 @@ func TestBucketProcessor_Process(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh1,
 					},
-				).Return(nil)
+				).Return(&wrapper.ClearBucketOutput{}, nil)
 				m.EXPECT().ClearBucket(
 					gomock.Any(),
 					wrapper.ClearBucketInput{
@@ -112,7 +153,7 @@ func TestBucketProcessor_Process(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh2,
 					},
-				).Return(nil)
+				).Return(&wrapper.ClearBucketOutput{}, nil)
 				go func() {
 					completed := <-completedCh1
 					assert.True(t, completed, "value from completedCh should be true")
@@ -132,6 +173,99 @@ func TestBucketProcessor_Process(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "successfully output dry run summary in dry run mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState, md *MockIDisplayManager) {
+				m.EXPECT().OutputCheckingMessage("bucket1").Return(nil)
+				md.EXPECT().Start([]string{"bucket1"})
+				md.EXPECT().Finish([]string{"bucket1"}).Return(nil)
+
+				countCh := make(chan int64)
+				completedCh := make(chan bool)
+				mc.EXPECT().GetChannelsForBucket("bucket1").Return(countCh, completedCh)
+
+				output := &wrapper.ClearBucketOutput{
+					ObjectsCount:       10,
+					VersionsCount:      5,
+					DeleteMarkersCount: 2,
+					BucketDeleted:      true,
+				}
+				m.EXPECT().ClearBucket(
+					gomock.Any(),
+					wrapper.ClearBucketInput{
+						TargetBucket:    "bucket1",
+						ForceMode:       true,
+						OldVersionsOnly: false,
+						QuietMode:       true,
+						ClearingCountCh: countCh,
+						DryRun:          true,
+					},
+				).Return(output, nil)
+				m.EXPECT().OutputDryRunMessage("bucket1", output).Return(nil)
+			},
+			config: BucketProcessorConfig{
+				TargetBuckets:     []string{"bucket1"},
+				QuietMode:         true,
+				ConcurrentMode:    false,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				ForceMode:         true,
+				DryRun:            true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "clear all buckets and return errors of them with continue on error",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState, md *MockIDisplayManager) {
+				buckets := []string{"bucket1", "bucket2", "bucket3"}
+				for _, bucket := range buckets {
+					m.EXPECT().OutputCheckingMessage(bucket).Return(nil)
+					mc.EXPECT().GetChannelsForBucket(bucket).Return(make(chan int64), make(chan bool))
+				}
+				md.EXPECT().Start(buckets)
+				md.EXPECT().Finish([]string{"bucket1"}).Return(nil)
+				m.EXPECT().ClearBucket(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input wrapper.ClearBucketInput) (*wrapper.ClearBucketOutput, error) {
+						switch input.TargetBucket {
+						case "bucket2":
+							return nil, &wrapper.PartiallyClearedError{
+								Output: &wrapper.ClearBucketOutput{ObjectsCount: 5},
+								Err:    fmt.Errorf("DeleteBucketError"),
+							}
+						case "bucket3":
+							return nil, fmt.Errorf("ClearBucketError")
+						}
+						return &wrapper.ClearBucketOutput{ObjectsCount: 10}, nil
+					},
+				).Times(3)
+			},
+			config: BucketProcessorConfig{
+				TargetBuckets:     []string{"bucket1", "bucket2", "bucket3"},
+				QuietMode:         true,
+				ConcurrentMode:    false,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				ContinueOnError:   true,
+			},
+			wantErr:     true,
+			expectedErr: "BucketsError: 2 of 3 buckets were not cleared.\nbucket2: DeleteBucketError\nbucket3: ClearBucketError",
+		},
+		{
+			name: "successfully process buckets with continue on error",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState, md *MockIDisplayManager) {
+				m.EXPECT().OutputCheckingMessage("bucket1").Return(nil)
+				mc.EXPECT().GetChannelsForBucket("bucket1").Return(make(chan int64), make(chan bool))
+				md.EXPECT().Start([]string{"bucket1"})
+				md.EXPECT().Finish([]string{"bucket1"}).Return(nil)
+				m.EXPECT().ClearBucket(gomock.Any(), gomock.Any()).Return(&wrapper.ClearBucketOutput{ObjectsCount: 10}, nil)
+			},
+			config: BucketProcessorConfig{
+				TargetBuckets:     []string{"bucket1"},
+				QuietMode:         true,
+				ConcurrentMode:    false,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				ContinueOnError:   true,
+			},
+			wantErr: false,
+		},
 		{
 			name: "error when output checking message fails",
 			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState, md *MockIDisplayManager) {
@@ -163,7 +297,7 @@ func TestBucketProcessor_Process(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh,
 					},
-				).Return(fmt.Errorf("ClearBucketError"))
+				).Return(nil, fmt.Errorf("ClearBucketError"))
 				go func() {
 					completed := <-completedCh
 					assert.False(t, completed, "value from completedCh should be false")
@@ -195,7 +329,7 @@ func TestBucketProcessor_Process(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh,
 					},
-				).Return(nil)
+				).Return(&wrapper.ClearBucketOutput{}, nil)
 				md.EXPECT().Finish([]string{"bucket1"}).Return(fmt.Errorf("FinishError"))
 				go func() {
 					completed := <-completedCh
@@ -228,6 +362,8 @@ func TestBucketProcessor_Process(t *testing.T) {
 				s3Wrapper: mockWrapper,
 				state:     mockClearingState,
 				display:   mockDisplayManager,
+				outputs:   map[string]*wrapper.ClearBucketOutput{},
+				errs:      map[string]error{},
 			}
 
 			err := processor.Process(context.Background())
@@ -244,12 +380,13 @@ func TestBucketProcessor_Process(t *testing.T) {
 
 func TestBucketProcessor_clearBuckets(t *testing.T) {
 	tests := []struct {
-		name              string
-		prepareMockFn     func(m *wrapper.MockIWrapper, mc *MockIClearingState)
-		config            BucketProcessorConfig
-		concurrencyNumber int
-		wantErr           bool
-		expectedErr       string
+		name                   string
+		prepareMockFn          func(m *wrapper.MockIWrapper, mc *MockIClearingState)
+		config                 BucketProcessorConfig
+		concurrencyNumber      int
+		wantErr                bool
+		expectedErr            string
+		expectedReportStatuses map[string]report.Status // checked only if the reporter is set
 	}{
 		{
 			name: "successfully clear single bucket",
@@ -266,7 +403,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh,
 					},
-				).Return(nil)
+				).Return(&wrapper.ClearBucketOutput{}, nil)
 				go func() {
 					completed := <-completedCh
 					assert.True(t, completed, "value from completedCh should be true")
@@ -297,7 +434,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 							QuietMode:       false,
 							ClearingCountCh: countCh,
 						},
-					).Return(nil)
+					).Return(&wrapper.ClearBucketOutput{}, nil)
 					go func() {
 						completed := <-completedCh
 						assert.True(t, completed, "value from completedCh should be true")
@@ -329,7 +466,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 							QuietMode:       false,
 							ClearingCountCh: countCh,
 						},
-					).Return(nil)
+					).Return(&wrapper.ClearBucketOutput{}, nil)
 					go func() {
 						completed := <-completedCh
 						assert.True(t, completed, "value from completedCh should be true")
@@ -361,7 +498,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 							QuietMode:       false,
 							ClearingCountCh: countCh,
 						},
-					).Return(nil)
+					).Return(&wrapper.ClearBucketOutput{}, nil)
 					go func() {
 						completed := <-completedCh
 						assert.True(t, completed, "value from completedCh should be true")
@@ -392,7 +529,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 						QuietMode:       true,
 						ClearingCountCh: countCh,
 					},
-				).Return(nil)
+				).Return(&wrapper.ClearBucketOutput{}, nil)
 			},
 			config: BucketProcessorConfig{
 				TargetBuckets:     []string{"bucket1"},
@@ -418,7 +555,7 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 						QuietMode:       false,
 						ClearingCountCh: countCh,
 					},
-				).Return(fmt.Errorf("ClearBucketError"))
+				).Return(nil, fmt.Errorf("ClearBucketError"))
 				go func() {
 					completed := <-completedCh
 					assert.False(t, completed, "value from completedCh should be false")
@@ -434,6 +571,71 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 			wantErr:           true,
 			expectedErr:       "ClearBucketError",
 		},
+		{
+			name: "report the results of all buckets even if a bucket fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState) {
+				for _, bucket := range []string{"bucket1", "bucket2"} {
+					countCh := make(chan int64)
+					completedCh := make(chan bool)
+					mc.EXPECT().GetChannelsForBucket(bucket).Return(countCh, completedCh)
+				}
+				m.EXPECT().ClearBucket(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input wrapper.ClearBucketInput) (*wrapper.ClearBucketOutput, error) {
+						if input.TargetBucket == "bucket2" {
+							return nil, fmt.Errorf("ClearBucketError")
+						}
+						return &wrapper.ClearBucketOutput{ObjectsCount: 1}, nil
+					},
+				).Times(2)
+			},
+			config: BucketProcessorConfig{
+				TargetBuckets:     []string{"bucket1", "bucket2"},
+				QuietMode:         true,
+				ConcurrentMode:    false,
+				ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+				Reporter:          report.NewReporter(),
+			},
+			concurrencyNumber: 1,
+			wantErr:           true,
+			expectedErr:       "ClearBucketError",
+			expectedReportStatuses: map[string]report.Status{
+				"bucket1": report.StatusSucceeded,
+				"bucket2": report.StatusFailed,
+			},
+		},
+		{
+			name: "clear all buckets without returning errors with continue on error",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mc *MockIClearingState) {
+				for _, bucket := range []string{"bucket1", "bucket2", "bucket3"} {
+					countCh := make(chan int64)
+					completedCh := make(chan bool)
+					mc.EXPECT().GetChannelsForBucket(bucket).Return(countCh, completedCh)
+				}
+				m.EXPECT().ClearBucket(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(ctx context.Context, input wrapper.ClearBucketInput) (*wrapper.ClearBucketOutput, error) {
+						if input.TargetBucket != "bucket3" {
+							return nil, fmt.Errorf("ClearBucketError")
+						}
+						return &wrapper.ClearBucketOutput{ObjectsCount: 1}, nil
+					},
+				).Times(3)
+			},
+			config: BucketProcessorConfig{
+				TargetBuckets:     []string{"bucket1", "bucket2", "bucket3"},
+				QuietMode:         true,
+				ConcurrentMode:    true,
+				ConcurrencyNumber: 2,
+				ContinueOnError:   true,
+				Reporter:          report.NewReporter(),
+			},
+			concurrencyNumber: 2,
+			wantErr:           false,
+			expectedReportStatuses: map[string]report.Status{
+				"bucket1": report.StatusFailed,
+				"bucket2": report.StatusFailed,
+				"bucket3": report.StatusSucceeded,
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -447,6 +649,8 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 				config:    tt.config,
 				s3Wrapper: mockWrapper,
 				state:     mockClearingState,
+				outputs:   map[string]*wrapper.ClearBucketOutput{},
+				errs:      map[string]error{},
 			}
 
 			err := processor.clearBuckets(context.Background(), tt.concurrencyNumber)
@@ -457,6 +661,143 @@ func TestBucketProcessor_clearBuckets(t *testing.T) {
 			if tt.wantErr {
 				assert.EqualError(t, err, tt.expectedErr)
 			}
+
+			if tt.config.Reporter != nil {
+				statuses := map[string]report.Status{}
+				for _, bucket := range tt.config.Reporter.Report(report.Options{}, err).Buckets {
+					statuses[bucket.Target] = bucket.Status
+				}
+				assert.Equal(t, tt.expectedReportStatuses, statuses)
+			}
 		})
 	}
 }
+
+func TestExitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{
+			name: "succeeded",
+			err:  nil,
+			want: ExitCodeSucceeded,
+		},
+		{
+			name: "failed without continue on error",
+			err:  fmt.Errorf("ClearBucketError"),
+			want: ExitCodeFailed,
+		},
+		{
+			name: "some buckets failed",
+			err: &BucketsError{
+				BucketsCount: 2,
+				Errors:       []BucketError{{Bucket: "bucket1", Err: fmt.Errorf("ClearBucketError")}},
+			},
+			want: ExitCodePartiallyFailed,
+		},
+		{
+			name: "all buckets failed but some were partially cleared",
+			err: &BucketsError{
+				BucketsCount: 2,
+				Errors: []BucketError{
+					{Bucket: "bucket1", Err: fmt.Errorf("ClearBucketError")},
+					{Bucket: "bucket2", Err: &wrapper.PartiallyClearedError{
+						Output: &wrapper.ClearBucketOutput{ObjectsCount: 1},
+						Err:    fmt.Errorf("DeleteBucketError"),
+					}},
+				},
+			},
+			want: ExitCodePartiallyFailed,
+		},
+		{
+			name: "all buckets failed",
+			err: &BucketsError{
+				BucketsCount: 2,
+				Errors: []BucketError{
+					{Bucket: "bucket1", Err: fmt.Errorf("ClearBucketError")},
+					{Bucket: "bucket2", Err: fmt.Errorf("ClearBucketError")},
+				},
+			},
+			want: ExitCodeFailed,
+		},
+		{
+			name: "interrupted",
+			err: &BucketsError{
+				BucketsCount: 2,
+				Errors: []BucketError{
+					{Bucket: "bucket1", Err: &client.ClientError{ResourceName: aws.String("bucket1"), Err: interrupt.ErrInterrupted}},
+				},
+			},
+			want: interrupt.ExitCode,
+		},
+		{
+			name: "wrapped buckets error",
+			err: fmt.Errorf("RunError: %w", &BucketsError{
+				BucketsCount: 2,
+				Errors:       []BucketError{{Bucket: "bucket1", Err: fmt.Errorf("ClearBucketError")}},
+			}),
+			want: ExitCodePartiallyFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ExitCode(tt.err))
+		})
+	}
+}
+
+func TestBucketProcessor_Process_Interrupted(t *testing.T) {
+	io.NewLogger(false)
+
+	ctrl := gomock.NewController(t)
+	mockWrapper := wrapper.NewMockIWrapper(ctrl)
+	mockClearingState := NewMockIClearingState(ctrl)
+	mockDisplayManager := NewMockIDisplayManager(ctrl)
+
+	ctx, stop := interrupt.WithStopping(context.Background())
+	defer stop()
+
+	buckets := []string{"bucket1", "bucket2"}
+	for _, bucket := range buckets {
+		mockWrapper.EXPECT().OutputCheckingMessage(bucket).Return(nil)
+		mockClearingState.EXPECT().GetChannelsForBucket(bucket).Return(make(chan int64), make(chan bool))
+	}
+	mockDisplayManager.EXPECT().Start(buckets)
+	mockDisplayManager.EXPECT().Finish([]string{}).Return(nil)
+	// NOTE: The signal is received while bucket1 is being cleared, so bucket2 is not cleared at all.
+	mockWrapper.EXPECT().ClearBucket(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, input wrapper.ClearBucketInput) (*wrapper.ClearBucketOutput, error) {
+			stop()
+			return nil, &wrapper.PartiallyClearedError{
+				Output: &wrapper.ClearBucketOutput{ObjectsCount: 1000},
+				Err: &client.ClientError{
+					ResourceName: aws.String(input.TargetBucket),
+					Err:          interrupt.ErrInterrupted,
+				},
+			}
+		},
+	).Times(1)
+
+	processor := &BucketProcessor{
+		config: BucketProcessorConfig{
+			TargetBuckets:     buckets,
+			QuietMode:         true,
+			ConcurrentMode:    false,
+			ConcurrencyNumber: UnspecifiedConcurrencyNumber,
+		},
+		s3Wrapper: mockWrapper,
+		state:     mockClearingState,
+		display:   mockDisplayManager,
+		outputs:   map[string]*wrapper.ClearBucketOutput{},
+		errs:      map[string]error{},
+	}
+
+	err := processor.Process(ctx)
+	assert.EqualError(t, err, "BucketsError: 2 of 2 buckets were not cleared."+
+		"\nbucket1: [resource bucket1] InterruptedError: stopped by a signal"+
+		"\nbucket2: [resource bucket2] InterruptedError: stopped by a signal")
+	assert.Equal(t, interrupt.ExitCode, ExitCode(err))
+}
@@ -0,0 +1,173 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/protection"
+	"github.com/go-to-k/cls3/internal/report"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"golang.org/x/sync/errgroup"
+)
+
+var accountIdPattern = regexp.MustCompile(`^[0-9]{12}$`)
+
+// BucketProtection makes the buckets matching any of the rules undeletable by cls3.
+// The buckets are protected by their names, their tags or the account they are in.
+type BucketProtection struct {
+	bucketPatterns []string
+	compiled       []*regexp.Regexp
+	tags           []TagCondition
+	accountIds     []string
+}
+
+// NewBucketProtection validates the rules of the config. Negated tags are not allowed
+// because a bucket without a tag would be protected by accident.
+func NewBucketProtection(config protection.Config) (*BucketProtection, error) {
+	compiled, err := compileBucketPatterns(config.BucketPatterns)
+	if err != nil {
+		return nil, err
+	}
+
+	tags := make([]TagCondition, 0, len(config.Tags))
+	for _, tag := range config.Tags {
+		condition, err := ParseTagCondition(tag)
+		if err != nil || condition.Negated {
+			errMsg := fmt.Sprintf("The protected tag %q must be in the form of key=value or key.\n", tag)
+			return nil, fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		tags = append(tags, condition)
+	}
+
+	for _, accountId := range config.AccountIds {
+		if !accountIdPattern.MatchString(accountId) {
+			errMsg := fmt.Sprintf("The protected account ID %q must be 12 digits.\n", accountId)
+			return nil, fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+	}
+
+	return &BucketProtection{
+		bucketPatterns: config.BucketPatterns,
+		compiled:       compiled,
+		tags:           tags,
+		accountIds:     config.AccountIds,
+	}, nil
+}
+
+// HasAccountIds returns true if any account is protected
+func (p *BucketProtection) HasAccountIds() bool {
+	return len(p.accountIds) != 0
+}
+
+// CheckAccount returns an error if the account is protected. An empty account ID (e.g. for S3-compatible storage) is not checked.
+func (p *BucketProtection) CheckAccount(accountId string) error {
+	if accountId != "" && slices.Contains(p.accountIds, accountId) {
+		return fmt.Errorf("ProtectedAccountError: The account %v is protected, so no buckets in it can be cleared.", accountId)
+	}
+	return nil
+}
+
+// ProtectedReasons returns the reason why each bucket is protected, or an empty string if it is not.
+// The tags are read in parallel only for the buckets not protected by their names.
+func (p *BucketProtection) ProtectedReasons(
+	ctx context.Context,
+	s3Wrapper wrapper.IWrapper,
+	buckets []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) ([]string, error) {
+	reasons := make([]string, len(buckets))
+	for i, bucket := range buckets {
+		for j, re := range p.compiled {
+			if re.MatchString(bucket.BucketName) {
+				reasons[i] = fmt.Sprintf("It matches the protected bucket pattern %q.", p.bucketPatterns[j])
+				break
+			}
+		}
+	}
+	if len(p.tags) == 0 {
+		return reasons, nil
+	}
+
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(BucketFilterConcurrency)
+	for i, bucket := range buckets {
+		if reasons[i] != "" {
+			continue
+		}
+		eg.Go(func() error {
+			tags, err := s3Wrapper.GetBucketTags(egCtx, bucket)
+			if err != nil {
+				return err
+			}
+			for _, condition := range p.tags {
+				if condition.matches(tags) {
+					reasons[i] = fmt.Sprintf("It has the protected tag %v.", condition)
+					break
+				}
+			}
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, err
+	}
+	return reasons, nil
+}
+
+// Verify returns an error with all protected buckets if any of the buckets is protected.
+// It is for the buckets specified explicitly, which must not be dropped silently.
+func (p *BucketProtection) Verify(
+	ctx context.Context,
+	s3Wrapper wrapper.IWrapper,
+	buckets []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) error {
+	reasons, err := p.ProtectedReasons(ctx, s3Wrapper, buckets)
+	if err != nil {
+		return err
+	}
+
+	protectedMessages := []string{}
+	for i, bucket := range buckets {
+		if reasons[i] != "" {
+			protectedMessages = append(protectedMessages, fmt.Sprintf("%v: %v", bucket.BucketName, reasons[i]))
+		}
+	}
+	if len(protectedMessages) != 0 {
+		return fmt.Errorf("ProtectedBucketError: The following buckets are protected, so nothing has been cleared:\n%v", strings.Join(protectedMessages, "\n"))
+	}
+	return nil
+}
+
+// Drop returns the buckets that are not protected in the same order. The protected buckets are output
+// and recorded in the report as skipped. It is for the buckets selected by patterns or tags.
+func (p *BucketProtection) Drop(
+	ctx context.Context,
+	s3Wrapper wrapper.IWrapper,
+	buckets []wrapper.ListBucketNamesFilteredByKeywordOutput,
+	reporter *report.Reporter,
+) ([]wrapper.ListBucketNamesFilteredByKeywordOutput, error) {
+	reasons, err := p.ProtectedReasons(ctx, s3Wrapper, buckets)
+	if err != nil {
+		return nil, err
+	}
+
+	unprotected := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
+	protectedMessages := []string{}
+	for i, bucket := range buckets {
+		if reasons[i] == "" {
+			unprotected = append(unprotected, bucket)
+			continue
+		}
+		protectedMessages = append(protectedMessages, fmt.Sprintf("%v: %v", bucket.BucketName, reasons[i]))
+		if reporter != nil {
+			reporter.AddSkippedBucket(bucket.TargetBucket, reasons[i])
+		}
+	}
+	if len(protectedMessages) != 0 {
+		io.Logger.Warn().Msgf("%v buckets are protected and skipped:\n%v", len(protectedMessages), strings.Join(protectedMessages, "\n"))
+	}
+	return unprotected, nil
+}
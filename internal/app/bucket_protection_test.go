@@ -0,0 +1,189 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/protection"
+	"github.com/go-to-k/cls3/internal/report"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func TestNewBucketProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  protection.Config
+		wantErr string
+	}{
+		{
+			name: "valid rules",
+			config: protection.Config{
+				BucketPatterns: []string{"prod-*", "regex:.*-prd"},
+				Tags:           []string{"cls3:protected=true", "aws:cloudformation:stack-name"},
+				AccountIds:     []string{"123456789012"},
+			},
+		},
+		{
+			name:    "invalid bucket pattern",
+			config:  protection.Config{BucketPatterns: []string{"regex:("}},
+			wantErr: "InvalidOptionError: The bucket pattern \"regex:(\" is invalid: error parsing regexp: missing closing ): `(`",
+		},
+		{
+			name:    "negated tag",
+			config:  protection.Config{Tags: []string{"!env=dev"}},
+			wantErr: "InvalidOptionError: The protected tag \"!env=dev\" must be in the form of key=value or key.\n",
+		},
+		{
+			name:    "empty tag key",
+			config:  protection.Config{Tags: []string{"=true"}},
+			wantErr: "InvalidOptionError: The protected tag \"=true\" must be in the form of key=value or key.\n",
+		},
+		{
+			name:    "invalid account id",
+			config:  protection.Config{AccountIds: []string{"1234-5678-9012"}},
+			wantErr: "InvalidOptionError: The protected account ID \"1234-5678-9012\" must be 12 digits.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewBucketProtection(tt.config)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.NotNil(t, got)
+		})
+	}
+}
+
+func TestBucketProtection_CheckAccount(t *testing.T) {
+	bucketProtection, err := NewBucketProtection(protection.Config{AccountIds: []string{"123456789012"}})
+	require.NoError(t, err)
+
+	assert.True(t, bucketProtection.HasAccountIds())
+	assert.EqualError(t, bucketProtection.CheckAccount("123456789012"), "ProtectedAccountError: The account 123456789012 is protected, so no buckets in it can be cleared.")
+	assert.NoError(t, bucketProtection.CheckAccount("210987654321"))
+	assert.NoError(t, bucketProtection.CheckAccount(""), "the account of S3-compatible storage is not checked")
+}
+
+func TestBucketProtection_ProtectedReasons(t *testing.T) {
+	prodBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "prod-data", TargetBucket: "prod-data"}
+	stackBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "stack-bucket", TargetBucket: "stack-bucket"}
+	taggedBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "tagged-bucket", TargetBucket: "tagged-bucket"}
+	testBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "test-bucket", TargetBucket: "test-bucket"}
+	buckets := []wrapper.ListBucketNamesFilteredByKeywordOutput{prodBucket, stackBucket, taggedBucket, testBucket}
+
+	tests := []struct {
+		name          string
+		config        protection.Config
+		prepareMockFn func(m *wrapper.MockIWrapper)
+		want          []string
+		wantErr       string
+	}{
+		{
+			name:          "protected by bucket patterns without reading tags",
+			config:        protection.Config{BucketPatterns: []string{"prod-*", "regex:stack-.*"}},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {},
+			want: []string{
+				`It matches the protected bucket pattern "prod-*".`,
+				`It matches the protected bucket pattern "regex:stack-.*".`,
+				"",
+				"",
+			},
+		},
+		{
+			name: "protected by tags only for the buckets not protected by the patterns",
+			config: protection.Config{
+				BucketPatterns: []string{"prod-*"},
+				Tags:           []string{"cls3:protected=true", "aws:cloudformation:stack-name"},
+			},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().GetBucketTags(gomock.Any(), stackBucket).Return(map[string]string{"aws:cloudformation:stack-name": "MyStack"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), taggedBucket).Return(map[string]string{"cls3:protected": "true"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), testBucket).Return(map[string]string{"cls3:protected": "false"}, nil)
+			},
+			want: []string{
+				`It matches the protected bucket pattern "prod-*".`,
+				"It has the protected tag aws:cloudformation:stack-name.",
+				"It has the protected tag cls3:protected=true.",
+				"",
+			},
+		},
+		{
+			name:   "error when getting tags fails",
+			config: protection.Config{Tags: []string{"cls3:protected=true"}},
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().GetBucketTags(gomock.Any(), gomock.Any()).Return(nil, fmt.Errorf("GetBucketTagsError")).AnyTimes()
+			},
+			wantErr: "GetBucketTagsError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			tt.prepareMockFn(mockWrapper)
+
+			bucketProtection, err := NewBucketProtection(tt.config)
+			require.NoError(t, err)
+
+			got, err := bucketProtection.ProtectedReasons(context.Background(), mockWrapper, buckets)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestBucketProtection_Verify(t *testing.T) {
+	bucketProtection, err := NewBucketProtection(protection.Config{BucketPatterns: []string{"prod-*"}})
+	require.NoError(t, err)
+
+	err = bucketProtection.Verify(context.Background(), nil, []wrapper.ListBucketNamesFilteredByKeywordOutput{
+		{BucketName: "test-bucket", TargetBucket: "test-bucket"},
+		{BucketName: "prod-data", TargetBucket: "prod-data"},
+		{BucketName: "prod-logs", TargetBucket: "prod-logs"},
+	})
+	assert.EqualError(t, err, "ProtectedBucketError: The following buckets are protected, so nothing has been cleared:\n"+
+		"prod-data: It matches the protected bucket pattern \"prod-*\".\n"+
+		"prod-logs: It matches the protected bucket pattern \"prod-*\".")
+
+	err = bucketProtection.Verify(context.Background(), nil, []wrapper.ListBucketNamesFilteredByKeywordOutput{
+		{BucketName: "test-bucket", TargetBucket: "test-bucket"},
+	})
+	assert.NoError(t, err)
+}
+
+func TestBucketProtection_Drop(t *testing.T) {
+	io.NewLogger(false)
+
+	bucketProtection, err := NewBucketProtection(protection.Config{BucketPatterns: []string{"prod-*"}})
+	require.NoError(t, err)
+
+	testBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "test-bucket", TargetBucket: "test-bucket"}
+	prodBucket := wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "prod-data", TargetBucket: "prod-data"}
+	reporter := report.NewReporter()
+
+	got, err := bucketProtection.Drop(
+		context.Background(),
+		nil,
+		[]wrapper.ListBucketNamesFilteredByKeywordOutput{testBucket, prodBucket},
+		reporter,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, []wrapper.ListBucketNamesFilteredByKeywordOutput{testBucket}, got)
+	assert.Equal(t, []report.SkippedBucket{
+		{Target: "prod-data", Reason: `It matches the protected bucket pattern "prod-*".`},
+	}, reporter.Report(report.Options{}, nil).SkippedBuckets)
+}
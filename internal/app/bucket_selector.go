@@ -3,10 +3,16 @@ package app
 
 import (
 	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/report"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"github.com/urfave/cli/v2"
 )
 
@@ -16,30 +22,56 @@ type IBucketSelector interface {
 
 var _ IBucketSelector = (*BucketSelector)(nil)
 
-// BucketSelector handles the selection of buckets through either interactive mode or command line arguments
+// BucketSelectorConfig holds the options of how the buckets are selected
+type BucketSelectorConfig struct {
+	InteractiveMode       bool
+	BucketNames           *cli.StringSlice
+	BucketPatterns        []string // glob or regular expression with the "regex:" prefix
+	ExcludeBucketPatterns []string
+	Filter                *BucketFilter     // narrows down the selected buckets in any mode if not nil
+	Protection            *BucketProtection // aborts or skips the protected buckets if not nil
+	Reporter              *report.Reporter  // records the buckets skipped by the protection if not nil
+}
+
+// BucketSelector handles the selection of buckets through interactive mode, command line arguments or patterns
 type BucketSelector struct {
-	interactiveMode bool
-	bucketNames     *cli.StringSlice
-	s3Wrapper       wrapper.IWrapper
-	inputManager    io.IInputManager
+	interactiveMode       bool
+	bucketNames           *cli.StringSlice
+	bucketPatterns        []string
+	excludeBucketPatterns []string
+	filter                *BucketFilter
+	protection            *BucketProtection
+	reporter              *report.Reporter
+	s3Wrapper             wrapper.IWrapper
+	inputManager          io.IInputManager
 }
 
 // NewBucketSelector creates a new BucketSelector instance
-func NewBucketSelector(interactiveMode bool, bucketNames *cli.StringSlice, s3Wrapper wrapper.IWrapper) *BucketSelector {
+func NewBucketSelector(config BucketSelectorConfig, s3Wrapper wrapper.IWrapper) *BucketSelector {
 	return &BucketSelector{
-		interactiveMode: interactiveMode,
-		bucketNames:     bucketNames,
-		s3Wrapper:       s3Wrapper,
-		inputManager:    io.NewInputManager(),
+		interactiveMode:       config.InteractiveMode,
+		bucketNames:           config.BucketNames,
+		bucketPatterns:        config.BucketPatterns,
+		excludeBucketPatterns: config.ExcludeBucketPatterns,
+		filter:                config.Filter,
+		protection:            config.Protection,
+		reporter:              config.Reporter,
+		s3Wrapper:             s3Wrapper,
+		inputManager:          io.NewInputManager(),
 	}
 }
 
-// SelectBuckets selects buckets based on the mode (interactive or command line)
+// SelectBuckets selects buckets based on the mode (interactive, patterns or command line)
+// The protected buckets abort the selection if they are selected interactively or in the command line,
+// and are skipped if they match the patterns.
 // Returns the selected buckets, a continuation flag, and any error that occurred
 func (s *BucketSelector) SelectBuckets(ctx context.Context) ([]string, bool, error) {
 	if s.interactiveMode {
 		return s.selectInteractively(ctx)
 	}
+	if len(s.bucketPatterns) != 0 {
+		return s.selectByPatterns(ctx)
+	}
 	return s.selectFromCommandLine(ctx)
 }
 
@@ -51,6 +83,14 @@ func (s *BucketSelector) selectInteractively(ctx context.Context) ([]string, boo
 	if err != nil {
 		return nil, false, err
 	}
+	outputs, err = s.filterBuckets(ctx, outputs)
+	if err != nil {
+		return nil, false, err
+	}
+	if len(outputs) == 0 {
+		io.Logger.Info().Msg("No buckets match the filters.")
+		return nil, false, nil
+	}
 
 	bucketNames := []string{}
 	for _, output := range outputs {
@@ -67,13 +107,18 @@ func (s *BucketSelector) selectInteractively(ctx context.Context) ([]string, boo
 	}
 
 	selectedBuckets := []string{}
+	selectedOutputs := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
 	for _, bucket := range checkboxes {
 		for _, output := range outputs {
 			if output.BucketName == bucket {
 				selectedBuckets = append(selectedBuckets, output.TargetBucket)
+				selectedOutputs = append(selectedOutputs, output)
 			}
 		}
 	}
+	if err := s.verifyProtection(ctx, selectedOutputs); err != nil {
+		return nil, false, err
+	}
 	return selectedBuckets, true, nil
 }
 
@@ -84,5 +129,168 @@ func (s *BucketSelector) selectFromCommandLine(ctx context.Context) ([]string, b
 	if err != nil {
 		return nil, false, err
 	}
-	return outputBuckets, true, nil
+	if s.filter == nil && s.protection == nil {
+		return outputBuckets, true, nil
+	}
+
+	specifiedOutputs, err := lookupBuckets(ctx, s.s3Wrapper, outputBuckets)
+	if err != nil {
+		return nil, false, err
+	}
+	filteredOutputs, err := s.filterBuckets(ctx, specifiedOutputs)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := s.verifyProtection(ctx, filteredOutputs); err != nil {
+		return nil, false, err
+	}
+
+	selectedBuckets := []string{}
+	skippedBuckets := []string{}
+	for _, output := range specifiedOutputs {
+		if slices.Contains(filteredOutputs, output) {
+			selectedBuckets = append(selectedBuckets, output.TargetBucket)
+		} else {
+			skippedBuckets = append(skippedBuckets, output.BucketName)
+		}
+	}
+	if len(skippedBuckets) != 0 {
+		io.Logger.Info().Msgf("The following buckets do not match the filters and are skipped: %v", strings.Join(skippedBuckets, ", "))
+	}
+	if len(selectedBuckets) == 0 {
+		io.Logger.Info().Msg("No buckets match the filters.")
+		return nil, false, nil
+	}
+	return selectedBuckets, true, nil
+}
+
+// selectByPatterns handles bucket selection by the bucket name patterns
+// Prints the matched buckets, which are confirmed before the deletion unless --yes is specified
+func (s *BucketSelector) selectByPatterns(ctx context.Context) ([]string, bool, error) {
+	includes, err := compileBucketPatterns(s.bucketPatterns)
+	if err != nil {
+		return nil, false, err
+	}
+	excludes, err := compileBucketPatterns(s.excludeBucketPatterns)
+	if err != nil {
+		return nil, false, err
+	}
+
+	// NOTE: An empty keyword lists all buckets.
+	outputs, err := s.s3Wrapper.ListBucketNamesFilteredByKeyword(ctx, aws.String(""))
+	if err != nil {
+		return nil, false, err
+	}
+
+	matchedOutputs := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
+	for _, output := range outputs {
+		if matchesAnyPattern(includes, output.BucketName) && !matchesAnyPattern(excludes, output.BucketName) {
+			matchedOutputs = append(matchedOutputs, output)
+		}
+	}
+	matchedOutputs, err = s.filterBuckets(ctx, matchedOutputs)
+	if err != nil {
+		return nil, false, err
+	}
+	if s.protection != nil {
+		matchedOutputs, err = s.protection.Drop(ctx, s.s3Wrapper, matchedOutputs, s.reporter)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	bucketNames := []string{}
+	selectedBuckets := []string{}
+	for _, output := range matchedOutputs {
+		bucketNames = append(bucketNames, output.BucketName)
+		selectedBuckets = append(selectedBuckets, output.TargetBucket)
+	}
+
+	if len(selectedBuckets) == 0 {
+		io.Logger.Info().Msgf("No buckets match the patterns: %v", strings.Join(s.bucketPatterns, ", "))
+		return nil, false, nil
+	}
+	io.Logger.Info().Msgf("%v buckets match the patterns:\n%v", len(bucketNames), strings.Join(bucketNames, "\n"))
+	return selectedBuckets, true, nil
+}
+
+// filterBuckets narrows down the buckets by the filter if it is specified
+func (s *BucketSelector) filterBuckets(
+	ctx context.Context,
+	outputs []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) ([]wrapper.ListBucketNamesFilteredByKeywordOutput, error) {
+	if s.filter == nil {
+		return outputs, nil
+	}
+	return s.filter.Filter(ctx, s.s3Wrapper, outputs)
+}
+
+// verifyProtection returns an error if any of the buckets is protected
+func (s *BucketSelector) verifyProtection(
+	ctx context.Context,
+	outputs []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) error {
+	if s.protection == nil {
+		return nil
+	}
+	return s.protection.Verify(ctx, s.s3Wrapper, outputs)
+}
+
+// lookupBuckets returns the attributes of the target buckets in the listing in the same order.
+// NOTE: A bucket not in the listing (e.g. a bucket in another account) has only the name.
+func lookupBuckets(
+	ctx context.Context,
+	s3Wrapper wrapper.IWrapper,
+	targetBuckets []string,
+) ([]wrapper.ListBucketNamesFilteredByKeywordOutput, error) {
+	// NOTE: An empty keyword lists all buckets.
+	outputs, err := s3Wrapper.ListBucketNamesFilteredByKeyword(ctx, aws.String(""))
+	if err != nil {
+		return nil, err
+	}
+
+	targetOutputs := make([]wrapper.ListBucketNamesFilteredByKeywordOutput, 0, len(targetBuckets))
+	for _, bucket := range targetBuckets {
+		index := slices.IndexFunc(outputs, func(output wrapper.ListBucketNamesFilteredByKeywordOutput) bool {
+			return output.TargetBucket == bucket
+		})
+		if index >= 0 {
+			targetOutputs = append(targetOutputs, outputs[index])
+		} else {
+			targetOutputs = append(targetOutputs, wrapper.ListBucketNamesFilteredByKeywordOutput{
+				BucketName:   bucket,
+				TargetBucket: bucket,
+			})
+		}
+	}
+	return targetOutputs, nil
+}
+
+// compileBucketPatterns compiles the patterns of the bucket names. Unlike the key patterns,
+// a regular expression must match the whole name like a glob, so that e.g. 'regex:prod' does not
+// select every bucket whose name contains "prod".
+func compileBucketPatterns(patterns []string) ([]*regexp.Regexp, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := client.CompileKeyPattern(pattern)
+		if err != nil {
+			errMsg := fmt.Sprintf("The bucket pattern %q is invalid: %v", pattern, err)
+			return nil, fmt.Errorf("InvalidOptionError: %v", errMsg)
+		}
+		// NOTE: The expression is validated as is above so that the error shows the specified one.
+		if expr, ok := strings.CutPrefix(pattern, client.RegexPatternPrefix); ok {
+			re = regexp.MustCompile("^(?:" + expr + ")$")
+		}
+		compiled = append(compiled, re)
+	}
+	return compiled, nil
+}
+
+func matchesAnyPattern(patterns []*regexp.Regexp, bucketName string) bool {
+	for _, re := range patterns {
+		if re.MatchString(bucketName) {
+			return true
+		}
+	}
+	return false
 }
@@ -4,9 +4,11 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/protection"
 	"github.com/go-to-k/cls3/internal/wrapper"
 	"github.com/stretchr/testify/assert"
 	"github.com/urfave/cli/v2"
@@ -14,6 +16,16 @@ import (
 )
 
 func Test_SelectBuckets(t *testing.T) {
+	io.NewLogger(false)
+
+	bucketProtection, err := NewBucketProtection(protection.Config{
+		BucketPatterns: []string{"prod-*"},
+		Tags:           []string{"cls3:protected=true"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	tests := []struct {
 		name          string
 		prepareMockFn func(m *wrapper.MockIWrapper, mi *io.MockIInputManager)
@@ -114,6 +126,392 @@ func Test_SelectBuckets(t *testing.T) {
 			wantErr:      true,
 			expectedErr:  "ListBucketNamesFilteredByKeywordError",
 		},
+		{
+			name: "successfully select buckets by patterns",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "pr-1234-assets", TargetBucket: "pr-1234-assets"},
+						{BucketName: "pr-1234-logs", TargetBucket: "pr-1234-logs"},
+						{BucketName: "pr-5678-assets", TargetBucket: "pr-5678-assets"},
+						{BucketName: "production", TargetBucket: "production"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:           cli.NewStringSlice(),
+				bucketPatterns:        []string{"pr-1234-*", "regex:pr-5678-.*"},
+				excludeBucketPatterns: []string{"*-logs"},
+			},
+			want:         []string{"pr-1234-assets", "pr-5678-assets"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "successfully select buckets by regular expressions matching the whole names",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "prod", TargetBucket: "prod"},
+						{BucketName: "my-prod-bucket", TargetBucket: "my-prod-bucket"},
+						{BucketName: "prod-logs", TargetBucket: "prod-logs"},
+						{BucketName: "test-logs", TargetBucket: "test-logs"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:           cli.NewStringSlice(),
+				bucketPatterns:        []string{"regex:prod", "regex:.*logs"},
+				excludeBucketPatterns: []string{"regex:logs"},
+			},
+			want:         []string{"prod", "prod-logs", "test-logs"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "successfully select table buckets by patterns with their arns",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "pr-1234-tables", TargetBucket: "arn:aws:s3tables:us-east-1:123456789012:bucket/pr-1234-tables"},
+						{BucketName: "main-tables", TargetBucket: "arn:aws:s3tables:us-east-1:123456789012:bucket/main-tables"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:    cli.NewStringSlice(),
+				bucketPatterns: []string{"pr-*"},
+			},
+			want:         []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/pr-1234-tables"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "finish when no buckets match the patterns",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "production", TargetBucket: "production"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:    cli.NewStringSlice(),
+				bucketPatterns: []string{"pr-*"},
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      false,
+		},
+		{
+			name: "error when listing buckets fails with patterns",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					nil,
+					fmt.Errorf("ListBucketNamesFilteredByKeywordError"),
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:    cli.NewStringSlice(),
+				bucketPatterns: []string{"pr-*"},
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      true,
+			expectedErr:  "ListBucketNamesFilteredByKeywordError",
+		},
+		{
+			name: "successfully select buckets from command line with filter",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().CheckAllBucketsExist(
+					gomock.Any(),
+					[]string{"bucket2", "bucket1", "bucket3"},
+				).Return(
+					[]string{"bucket2", "bucket1", "bucket3"},
+					nil,
+				)
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1", Region: "us-east-1"},
+						{BucketName: "bucket2", TargetBucket: "bucket2", Region: "ap-northeast-1"},
+						{BucketName: "bucket3", TargetBucket: "bucket3", Region: "us-east-1"},
+						{BucketName: "bucket4", TargetBucket: "bucket4", Region: "us-east-1"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames: cli.NewStringSlice("bucket2", "bucket1", "bucket3"),
+				filter:      &BucketFilter{Regions: []string{"us-east-1"}},
+			},
+			want:         []string{"bucket1", "bucket3"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "finish when no buckets from command line match the filter",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().CheckAllBucketsExist(
+					gomock.Any(),
+					[]string{"bucket1"},
+				).Return(
+					[]string{"bucket1"},
+					nil,
+				)
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1", Region: "us-east-1"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames: cli.NewStringSlice("bucket1"),
+				filter:      &BucketFilter{Regions: []string{"eu-west-1"}},
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      false,
+		},
+		{
+			name: "successfully select buckets in interactive mode with filter",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				mi.EXPECT().InputKeywordForFilter("Filter a keyword of bucket names: ").Return("bucket")
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String("bucket"),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+						{BucketName: "bucket2", TargetBucket: "bucket2"},
+					},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "bucket1", TargetBucket: "bucket1"},
+				).Return(map[string]string{"env": "pr"}, nil)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "bucket2", TargetBucket: "bucket2"},
+				).Return(map[string]string{"env": "prod"}, nil)
+				mi.EXPECT().GetCheckboxes(
+					[]string{"Select buckets."},
+					[]string{"bucket1"},
+				).Return(
+					[]string{"bucket1"},
+					true,
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				interactiveMode: true,
+				bucketNames:     cli.NewStringSlice(),
+				filter:          &BucketFilter{Tags: []TagCondition{{Key: "env", Value: aws.String("pr")}}},
+			},
+			want:         []string{"bucket1"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "finish when no buckets match the filter in interactive mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				mi.EXPECT().InputKeywordForFilter("Filter a keyword of bucket names: ").Return("bucket")
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String("bucket"),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				interactiveMode: true,
+				bucketNames:     cli.NewStringSlice(),
+				filter:          &BucketFilter{Regions: []string{"us-east-1"}},
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      false,
+		},
+		{
+			name: "successfully select buckets by patterns with filter",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "pr-1234-assets", TargetBucket: "pr-1234-assets", CreationDate: aws.Time(time.Now().AddDate(0, 0, -10))},
+						{BucketName: "pr-5678-assets", TargetBucket: "pr-5678-assets", CreationDate: aws.Time(time.Now())},
+					},
+					nil,
+				)
+			},
+			selector: &BucketSelector{
+				bucketNames:    cli.NewStringSlice(),
+				bucketPatterns: []string{"pr-*"},
+				filter:         &BucketFilter{CreatedBefore: aws.Time(time.Now().AddDate(0, 0, -7))},
+			},
+			want:         []string{"pr-1234-assets"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "error when a protected bucket is specified from command line",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().CheckAllBucketsExist(
+					gomock.Any(),
+					[]string{"bucket1", "prod-bucket", "other-account-bucket"},
+				).Return(
+					[]string{"bucket1", "prod-bucket", "other-account-bucket"},
+					nil,
+				)
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+						{BucketName: "prod-bucket", TargetBucket: "prod-bucket"},
+					},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "bucket1", TargetBucket: "bucket1"},
+				).Return(map[string]string{}, nil)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "other-account-bucket", TargetBucket: "other-account-bucket"},
+				).Return(map[string]string{"cls3:protected": "true"}, nil)
+			},
+			selector: &BucketSelector{
+				bucketNames: cli.NewStringSlice("bucket1", "prod-bucket", "other-account-bucket"),
+				protection:  bucketProtection,
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      true,
+			expectedErr: "ProtectedBucketError: The following buckets are protected, so nothing has been cleared:\n" +
+				"prod-bucket: It matches the protected bucket pattern \"prod-*\".\n" +
+				"other-account-bucket: It has the protected tag cls3:protected=true.",
+		},
+		{
+			name: "successfully select unprotected buckets from command line with protection",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().CheckAllBucketsExist(
+					gomock.Any(),
+					[]string{"bucket1"},
+				).Return(
+					[]string{"bucket1"},
+					nil,
+				)
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+					},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), gomock.Any()).Return(map[string]string{"cls3:protected": "false"}, nil)
+			},
+			selector: &BucketSelector{
+				bucketNames: cli.NewStringSlice("bucket1"),
+				protection:  bucketProtection,
+			},
+			want:         []string{"bucket1"},
+			wantContinue: true,
+			wantErr:      false,
+		},
+		{
+			name: "error when a protected bucket is selected in interactive mode",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				mi.EXPECT().InputKeywordForFilter("Filter a keyword of bucket names: ").Return("bucket")
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String("bucket"),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "bucket1", TargetBucket: "bucket1"},
+						{BucketName: "prod-bucket", TargetBucket: "prod-bucket"},
+					},
+					nil,
+				)
+				mi.EXPECT().GetCheckboxes([]string{"Select buckets."}, []string{"bucket1", "prod-bucket"}).Return([]string{"bucket1", "prod-bucket"}, true, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), gomock.Any()).Return(map[string]string{}, nil)
+			},
+			selector: &BucketSelector{
+				interactiveMode: true,
+				bucketNames:     cli.NewStringSlice(),
+				protection:      bucketProtection,
+			},
+			want:         nil,
+			wantContinue: false,
+			wantErr:      true,
+			expectedErr:  "ProtectedBucketError: The following buckets are protected, so nothing has been cleared:\nprod-bucket: It matches the protected bucket pattern \"prod-*\".",
+		},
+		{
+			name: "skip protected buckets matching the patterns",
+			prepareMockFn: func(m *wrapper.MockIWrapper, mi *io.MockIInputManager) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(
+					gomock.Any(),
+					aws.String(""),
+				).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{
+						{BucketName: "prod-bucket", TargetBucket: "prod-bucket"},
+						{BucketName: "pr-bucket", TargetBucket: "pr-bucket"},
+						{BucketName: "pr-tagged-bucket", TargetBucket: "pr-tagged-bucket"},
+					},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "pr-bucket", TargetBucket: "pr-bucket"},
+				).Return(map[string]string{}, nil)
+				m.EXPECT().GetBucketTags(
+					gomock.Any(),
+					wrapper.ListBucketNamesFilteredByKeywordOutput{BucketName: "pr-tagged-bucket", TargetBucket: "pr-tagged-bucket"},
+				).Return(map[string]string{"cls3:protected": "true"}, nil)
+			},
+			selector: &BucketSelector{
+				bucketNames:    cli.NewStringSlice(),
+				bucketPatterns: []string{"pr*"},
+				protection:     bucketProtection,
+			},
+			want:         []string{"pr-bucket"},
+			wantContinue: true,
+			wantErr:      false,
+		},
 	}
 
 	for _, tt := range tests {
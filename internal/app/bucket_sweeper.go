@@ -0,0 +1,168 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/report"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	DefaultExpiresAtTagKey = "cls3:expires-at"
+	DefaultTTLTagKey       = "ttl"
+)
+
+var _ IBucketSelector = (*BucketSweeper)(nil)
+
+// BucketSweeperConfig holds the options of how the expired buckets are found
+type BucketSweeperConfig struct {
+	ExpiresAtTagKey string            // the tag with the RFC3339 time when the bucket expires
+	TTLTagKey       string            // the tag with the duration after the creation of the bucket when it expires
+	Reporter        *report.Reporter  // records the skipped buckets if not nil
+	Protection      *BucketProtection // skips the protected buckets if not nil
+}
+
+// BucketSweeper selects the buckets whose expiry tag has passed for the sweep command
+type BucketSweeper struct {
+	expiresAtTagKey string
+	ttlTagKey       string
+	reporter        *report.Reporter
+	protection      *BucketProtection
+	now             time.Time
+	s3Wrapper       wrapper.IWrapper
+}
+
+// NewBucketSweeper creates a new BucketSweeper instance that sweeps the buckets expired by now
+func NewBucketSweeper(config BucketSweeperConfig, s3Wrapper wrapper.IWrapper) *BucketSweeper {
+	return &BucketSweeper{
+		expiresAtTagKey: config.ExpiresAtTagKey,
+		ttlTagKey:       config.TTLTagKey,
+		reporter:        config.Reporter,
+		protection:      config.Protection,
+		now:             time.Now(),
+		s3Wrapper:       s3Wrapper,
+	}
+}
+
+// SelectBuckets selects the expired buckets after printing them. They are confirmed before the deletion unless --yes is specified.
+// The buckets with an expiry tag that are not selected are output and recorded in the report with the reasons.
+// The protected buckets are skipped in the same way even if they have expired.
+func (s *BucketSweeper) SelectBuckets(ctx context.Context) ([]string, bool, error) {
+	// NOTE: An empty keyword lists all buckets.
+	outputs, err := s.s3Wrapper.ListBucketNamesFilteredByKeyword(ctx, aws.String(""))
+	if err != nil {
+		return nil, false, err
+	}
+
+	hasTags, reasons, err := s.checkExpiries(ctx, outputs)
+	if err != nil {
+		return nil, false, err
+	}
+
+	expiredOutputs := []wrapper.ListBucketNamesFilteredByKeywordOutput{}
+	skippedMessages := []string{}
+	for i, output := range outputs {
+		if !hasTags[i] {
+			continue
+		}
+		if reasons[i] != "" {
+			skippedMessages = append(skippedMessages, fmt.Sprintf("%v: %v", output.BucketName, reasons[i]))
+			if s.reporter != nil {
+				s.reporter.AddSkippedBucket(output.TargetBucket, reasons[i])
+			}
+			continue
+		}
+		expiredOutputs = append(expiredOutputs, output)
+	}
+
+	if len(skippedMessages) != 0 {
+		io.Logger.Info().Msgf("%v buckets are skipped:\n%v", len(skippedMessages), strings.Join(skippedMessages, "\n"))
+	}
+	if s.protection != nil {
+		expiredOutputs, err = s.protection.Drop(ctx, s.s3Wrapper, expiredOutputs, s.reporter)
+		if err != nil {
+			return nil, false, err
+		}
+	}
+
+	bucketNames := []string{}
+	selectedBuckets := []string{}
+	for _, output := range expiredOutputs {
+		bucketNames = append(bucketNames, output.BucketName)
+		selectedBuckets = append(selectedBuckets, output.TargetBucket)
+	}
+	if len(selectedBuckets) == 0 {
+		io.Logger.Info().Msg("No buckets have expired.")
+		return nil, false, nil
+	}
+	io.Logger.Info().Msgf("%v buckets have expired:\n%v", len(bucketNames), strings.Join(bucketNames, "\n"))
+	return selectedBuckets, true, nil
+}
+
+// checkExpiries reads the tags of the buckets in parallel and checks the expiry of each bucket.
+func (s *BucketSweeper) checkExpiries(
+	ctx context.Context,
+	buckets []wrapper.ListBucketNamesFilteredByKeywordOutput,
+) ([]bool, []string, error) {
+	hasTags := make([]bool, len(buckets))
+	reasons := make([]string, len(buckets)) // empty for the expired buckets
+	eg, egCtx := errgroup.WithContext(ctx)
+	eg.SetLimit(BucketFilterConcurrency)
+	for i, bucket := range buckets {
+		eg.Go(func() error {
+			tags, err := s.s3Wrapper.GetBucketTags(egCtx, bucket)
+			if err != nil {
+				return err
+			}
+			hasTags[i], reasons[i] = s.checkExpiry(bucket, tags)
+			return nil
+		})
+	}
+	if err := eg.Wait(); err != nil {
+		return nil, nil, err
+	}
+	return hasTags, reasons, nil
+}
+
+// checkExpiry returns whether the bucket has an expiry tag, and the reason why it is skipped (empty if expired).
+// If the bucket has both tags, the earlier expiry is used.
+func (s *BucketSweeper) checkExpiry(bucket wrapper.ListBucketNamesFilteredByKeywordOutput, tags map[string]string) (bool, string) {
+	expiresAtValue, hasExpiresAt := tags[s.expiresAtTagKey]
+	ttlValue, hasTTL := tags[s.ttlTagKey]
+	if !hasExpiresAt && !hasTTL {
+		return false, ""
+	}
+
+	var expiresAt *time.Time
+	if hasExpiresAt {
+		parsed, err := time.Parse(time.RFC3339, expiresAtValue)
+		if err != nil {
+			return true, fmt.Sprintf("The %v tag must be an RFC3339 timestamp, but got %q.", s.expiresAtTagKey, expiresAtValue)
+		}
+		expiresAt = &parsed
+	}
+	if hasTTL {
+		days, duration, err := parseDurationOption(ttlValue)
+		if err != nil {
+			return true, fmt.Sprintf("The %v tag must be a duration (e.g. 7d), but got %q.", s.ttlTagKey, ttlValue)
+		}
+		if bucket.CreationDate == nil {
+			return true, fmt.Sprintf("The %v tag cannot be used because the creation date of the bucket is unknown.", s.ttlTagKey)
+		}
+		expiresAtByTTL := bucket.CreationDate.AddDate(0, 0, days).Add(duration)
+		if expiresAt == nil || expiresAtByTTL.Before(*expiresAt) {
+			expiresAt = &expiresAtByTTL
+		}
+	}
+
+	if s.now.Before(*expiresAt) {
+		return true, fmt.Sprintf("It expires at %v.", expiresAt.UTC().Format(time.RFC3339))
+	}
+	return true, ""
+}
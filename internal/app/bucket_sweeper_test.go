@@ -0,0 +1,176 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/protection"
+	"github.com/go-to-k/cls3/internal/report"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestBucketSweeper_SelectBuckets(t *testing.T) {
+	io.NewLogger(false)
+
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	newBucket := func(name string, creationDate *time.Time) wrapper.ListBucketNamesFilteredByKeywordOutput {
+		return wrapper.ListBucketNamesFilteredByKeywordOutput{
+			BucketName:   name,
+			TargetBucket: name,
+			CreationDate: creationDate,
+		}
+	}
+	expiredByTime := newBucket("expired-by-time", aws.Time(now.AddDate(0, 0, -30)))
+	expiredByTTL := newBucket("expired-by-ttl", aws.Time(now.AddDate(0, 0, -8)))
+	notExpired := newBucket("not-expired", aws.Time(now.AddDate(0, 0, -1)))
+	untagged := newBucket("untagged", aws.Time(now.AddDate(0, 0, -30)))
+	unknownCreationDate := newBucket("unknown-creation-date", nil)
+	protectedBucket := newBucket("prod-bucket", aws.Time(now.AddDate(0, 0, -30)))
+
+	bucketProtection, err := NewBucketProtection(protection.Config{BucketPatterns: []string{"prod-*"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name            string
+		protection      *BucketProtection
+		prepareMockFn   func(m *wrapper.MockIWrapper)
+		want            []string
+		wantContinue    bool
+		wantErr         string
+		expectedSkipped []report.SkippedBucket
+	}{
+		{
+			name: "select expired buckets by the expiry time and the ttl",
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{expiredByTime, expiredByTTL, notExpired, untagged},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTime).Return(map[string]string{"cls3:expires-at": "2025-05-31T00:00:00Z"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTTL).Return(map[string]string{"ttl": "7d"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), notExpired).Return(map[string]string{"ttl": "1d12h"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), untagged).Return(map[string]string{"env": "pr"}, nil)
+			},
+			want:         []string{"expired-by-time", "expired-by-ttl"},
+			wantContinue: true,
+			expectedSkipped: []report.SkippedBucket{
+				{Target: "not-expired", Reason: "It expires at 2025-06-01T12:00:00Z."},
+			},
+		},
+		{
+			name: "the earlier expiry is used when a bucket has both tags",
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{expiredByTTL, notExpired},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTTL).Return(
+					map[string]string{"cls3:expires-at": "2026-01-01T00:00:00Z", "ttl": "7d"}, nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), notExpired).Return(
+					map[string]string{"cls3:expires-at": "2025-06-02T00:00:00+09:00", "ttl": "30d"}, nil,
+				)
+			},
+			want:         []string{"expired-by-ttl"},
+			wantContinue: true,
+			expectedSkipped: []report.SkippedBucket{
+				{Target: "not-expired", Reason: "It expires at 2025-06-01T15:00:00Z."},
+			},
+		},
+		{
+			name: "skip buckets with invalid tags",
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{expiredByTime, expiredByTTL, unknownCreationDate},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTime).Return(map[string]string{"cls3:expires-at": "2025-05-31"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTTL).Return(map[string]string{"ttl": "a week"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), unknownCreationDate).Return(map[string]string{"ttl": "7d"}, nil)
+			},
+			want:         nil,
+			wantContinue: false,
+			expectedSkipped: []report.SkippedBucket{
+				{Target: "expired-by-time", Reason: `The cls3:expires-at tag must be an RFC3339 timestamp, but got "2025-05-31".`},
+				{Target: "expired-by-ttl", Reason: `The ttl tag must be a duration (e.g. 7d), but got "a week".`},
+				{Target: "unknown-creation-date", Reason: "The ttl tag cannot be used because the creation date of the bucket is unknown."},
+			},
+		},
+		{
+			name:       "skip protected buckets even if they have expired",
+			protection: bucketProtection,
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{expiredByTime, protectedBucket},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTime).Return(map[string]string{"ttl": "7d"}, nil)
+				m.EXPECT().GetBucketTags(gomock.Any(), protectedBucket).Return(map[string]string{"ttl": "7d"}, nil)
+			},
+			want:         []string{"expired-by-time"},
+			wantContinue: true,
+			expectedSkipped: []report.SkippedBucket{
+				{Target: "prod-bucket", Reason: `It matches the protected bucket pattern "prod-*".`},
+			},
+		},
+		{
+			name: "error when listing buckets fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(nil, fmt.Errorf("ListBucketsError"))
+			},
+			want:            nil,
+			wantContinue:    false,
+			wantErr:         "ListBucketsError",
+			expectedSkipped: nil,
+		},
+		{
+			name: "error when getting tags fails",
+			prepareMockFn: func(m *wrapper.MockIWrapper) {
+				m.EXPECT().ListBucketNamesFilteredByKeyword(gomock.Any(), aws.String("")).Return(
+					[]wrapper.ListBucketNamesFilteredByKeywordOutput{expiredByTTL},
+					nil,
+				)
+				m.EXPECT().GetBucketTags(gomock.Any(), expiredByTTL).Return(nil, fmt.Errorf("GetBucketTagsError"))
+			},
+			want:            nil,
+			wantContinue:    false,
+			wantErr:         "GetBucketTagsError",
+			expectedSkipped: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+			tt.prepareMockFn(mockWrapper)
+
+			reporter := report.NewReporter()
+			sweeper := NewBucketSweeper(BucketSweeperConfig{
+				ExpiresAtTagKey: DefaultExpiresAtTagKey,
+				TTLTagKey:       DefaultTTLTagKey,
+				Reporter:        reporter,
+				Protection:      tt.protection,
+			}, mockWrapper)
+			sweeper.now = now
+
+			got, cont, err := sweeper.SelectBuckets(context.Background())
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+			assert.Equal(t, tt.wantContinue, cont)
+			assert.Equal(t, tt.expectedSkipped, reporter.Report(report.Options{}, nil).SkippedBuckets)
+		})
+	}
+}
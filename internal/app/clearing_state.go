@@ -3,12 +3,15 @@ package app
 
 import (
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
+	"time"
 
 	"github.com/go-to-k/cls3/internal/io"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -30,10 +33,22 @@ type ClearingState struct {
 	countsMutex       sync.Mutex
 	s3Wrapper         wrapper.IWrapper
 	forceMode         bool
+	rateController    *client.RateController // shows the throughput and the throttles if not nil
+	running           atomic.Int64           // the number of the buckets being cleared
+	sampledAt         time.Time              // the time of the last sample of the throughput
+	sampledCount      int64                  // the total count at the last sample of the throughput
+	throughput        float64                // targets per second
+	targetsName       string                 // the name of the counted targets (e.g. objects) in the throughput
 }
 
 // NewClearingState initializes a new ClearingState instance
-func NewClearingState(targetBuckets []string, s3Wrapper wrapper.IWrapper, forceMode bool) *ClearingState {
+func NewClearingState(
+	targetBuckets []string,
+	s3Wrapper wrapper.IWrapper,
+	forceMode bool,
+	rateController *client.RateController,
+	targetsName string,
+) *ClearingState {
 	state := &ClearingState{
 		lines:             make([]string, len(targetBuckets)),
 		countChannels:     make(map[string]chan int64, len(targetBuckets)),
@@ -41,7 +56,10 @@ func NewClearingState(targetBuckets []string, s3Wrapper wrapper.IWrapper, forceM
 		counts:            make(map[string]*atomic.Int64, len(targetBuckets)),
 		s3Wrapper:         s3Wrapper,
 		forceMode:         forceMode,
+		rateController:    rateController,
+		targetsName:       targetsName,
 	}
+	state.running.Store(int64(len(targetBuckets)))
 
 	for _, bucket := range targetBuckets {
 		state.countChannels[bucket] = make(chan int64)
@@ -94,10 +112,14 @@ func (s *ClearingState) monitorBucketProgress(writer *io.Writer, index int, buck
 		s.linesMutex.Lock()
 		s.lines[index] = message
 		nonEmptyLines := getNonEmptyLines(s.lines)
+		if statusLine := s.getStatusLine(time.Now()); statusLine != "" {
+			nonEmptyLines = append(nonEmptyLines, statusLine)
+		}
 		fmt.Fprintln(writer, strings.Join(nonEmptyLines, "\n"))
 		s.linesMutex.Unlock()
 	}
 
+	s.running.Add(-1)
 	isCompleted := <-clearingCompletedCh
 	count := counter.Load()
 	message, err := s.s3Wrapper.GetLiveClearedMessage(bucket, count, isCompleted)
@@ -107,11 +129,44 @@ func (s *ClearingState) monitorBucketProgress(writer *io.Writer, index int, buck
 	s.linesMutex.Lock()
 	s.lines[index] = message
 	nonEmptyLines := getNonEmptyLines(s.lines)
+	if statusLine := s.getStatusLine(time.Now()); statusLine != "" {
+		nonEmptyLines = append(nonEmptyLines, statusLine)
+	}
 	fmt.Fprintln(writer, strings.Join(nonEmptyLines, "\n"))
 	s.linesMutex.Unlock()
 	return nil
 }
 
+// getStatusLine returns the line of the throughput and the throttles of all buckets while any of them is being cleared.
+// It must be called with linesMutex locked.
+func (s *ClearingState) getStatusLine(now time.Time) string {
+	if s.rateController == nil || s.running.Load() <= 0 {
+		return ""
+	}
+
+	total := s.getTotalCount()
+	// NOTE: The throughput is measured over a second or more so that it does not jump at each update.
+	if s.sampledAt.IsZero() {
+		s.sampledAt, s.sampledCount = now, total
+	} else if elapsed := now.Sub(s.sampledAt); elapsed >= time.Second {
+		s.throughput = float64(total-s.sampledCount) / elapsed.Seconds()
+		s.sampledAt, s.sampledCount = now, total
+	}
+
+	stats := s.rateController.Stats()
+	limit := "unlimited"
+	if stats.Limit > 0 {
+		limit = strconv.Itoa(stats.Limit)
+	}
+	return fmt.Sprintf(
+		"Throughput: %d %s/s, Throttled: %d requests, Concurrent requests limit: %s",
+		int64(s.throughput),
+		s.targetsName,
+		stats.Throttles,
+		limit,
+	)
+}
+
 // GetChannelsForBucket returns the channels associated with a specific bucket
 func (s *ClearingState) GetChannelsForBucket(bucket string) (chan int64, chan bool) {
 	// Lock to access to slices safely
@@ -136,6 +191,18 @@ func (s *ClearingState) OutputFinalMessages(targetBuckets []string) error {
 	return nil
 }
 
+// getTotalCount returns the current count of all buckets
+func (s *ClearingState) getTotalCount() int64 {
+	// Lock to access to slices safely
+	s.countsMutex.Lock()
+	defer s.countsMutex.Unlock()
+	var total int64
+	for _, counter := range s.counts {
+		total += counter.Load()
+	}
+	return total
+}
+
 // getCount returns the current count for a specific bucket
 func (s *ClearingState) getCount(bucket string) int64 {
 	// Lock to access to slices safely
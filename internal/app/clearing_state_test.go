@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/go-to-k/cls3/internal/io"
 	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"go.uber.org/mock/gomock"
 )
@@ -29,7 +31,7 @@ func TestClearingState_NewClearingState(t *testing.T) {
 			ctrl := gomock.NewController(t)
 			mockWrapper := wrapper.NewMockIWrapper(ctrl)
 
-			state := NewClearingState(tt.targetBuckets, mockWrapper, tt.forceMode)
+			state := NewClearingState(tt.targetBuckets, mockWrapper, tt.forceMode, nil, "objects")
 			assert.NotNil(t, state)
 
 			assert.Equal(t, len(tt.targetBuckets), len(state.lines))
@@ -37,6 +39,7 @@ func TestClearingState_NewClearingState(t *testing.T) {
 			assert.Equal(t, len(tt.targetBuckets), len(state.completedChannels))
 			assert.Equal(t, len(tt.targetBuckets), len(state.counts))
 			assert.Equal(t, tt.forceMode, state.forceMode)
+			assert.Equal(t, int64(len(tt.targetBuckets)), state.running.Load())
 		})
 	}
 }
@@ -189,3 +192,98 @@ func TestClearingState_OutputFinalMessages(t *testing.T) {
 		})
 	}
 }
+
+func TestClearingState_getStatusLine(t *testing.T) {
+	startedAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name           string
+		rateController *client.RateController
+		targetsName    string
+		running        int64
+		counts         []int64 // the counts of the buckets at each update
+		elapsed        []time.Duration
+		expected       string
+	}{
+		{
+			name:           "show the status while the buckets are being cleared",
+			targetsName:    "objects",
+			rateController: client.NewRateController(8),
+			running:        1,
+			counts:         []int64{0},
+			elapsed:        []time.Duration{0},
+			expected:       "Throughput: 0 objects/s, Throttled: 0 requests, Concurrent requests limit: 8",
+		},
+		{
+			name:           "show the unlimited requests until the first throttle",
+			targetsName:    "objects",
+			rateController: client.NewRateController(0),
+			running:        1,
+			counts:         []int64{0},
+			elapsed:        []time.Duration{0},
+			expected:       "Throughput: 0 objects/s, Throttled: 0 requests, Concurrent requests limit: unlimited",
+		},
+		{
+			name:           "show the throughput measured over a second",
+			targetsName:    "objects",
+			rateController: client.NewRateController(8),
+			running:        1,
+			counts:         []int64{0, 1000, 3000},
+			elapsed:        []time.Duration{0, 500 * time.Millisecond, 2 * time.Second},
+			expected:       "Throughput: 1500 objects/s, Throttled: 0 requests, Concurrent requests limit: 8",
+		},
+		{
+			name:           "keep the throughput until a second passes",
+			targetsName:    "objects",
+			rateController: client.NewRateController(8),
+			running:        1,
+			counts:         []int64{0, 1000, 1500},
+			elapsed:        []time.Duration{0, time.Second, 1500 * time.Millisecond},
+			expected:       "Throughput: 1000 objects/s, Throttled: 0 requests, Concurrent requests limit: 8",
+		},
+		{
+			name:           "show the throughput of the tables for table buckets",
+			targetsName:    "tables",
+			rateController: client.NewRateController(8),
+			running:        1,
+			counts:         []int64{0, 20},
+			elapsed:        []time.Duration{0, 2 * time.Second},
+			expected:       "Throughput: 10 tables/s, Throttled: 0 requests, Concurrent requests limit: 8",
+		},
+		{
+			name:           "hide the status after all buckets are cleared",
+			targetsName:    "objects",
+			rateController: client.NewRateController(8),
+			running:        0,
+			counts:         []int64{0},
+			elapsed:        []time.Duration{0},
+			expected:       "",
+		},
+		{
+			name:           "hide the status without the rate controller",
+			targetsName:    "objects",
+			rateController: nil,
+			running:        1,
+			counts:         []int64{0},
+			elapsed:        []time.Duration{0},
+			expected:       "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockWrapper := wrapper.NewMockIWrapper(ctrl)
+
+			state := NewClearingState([]string{"bucket1"}, mockWrapper, false, tt.rateController, tt.targetsName)
+			state.running.Store(tt.running)
+
+			var statusLine string
+			for i, count := range tt.counts {
+				state.counts["bucket1"].Store(count)
+				statusLine = state.getStatusLine(startedAt.Add(tt.elapsed[i]))
+			}
+			assert.Equal(t, tt.expected, statusLine)
+		})
+	}
+}
@@ -0,0 +1,106 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOFILE -package=$GOPACKAGE -write_package_comment=false
+package app
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-to-k/cls3/internal/io"
+)
+
+// DefaultTypedConfirmationThreshold is the default number of buckets above which the number of them must be typed to confirm the deletion.
+const DefaultTypedConfirmationThreshold = 3
+
+type IDeletionConfirmer interface {
+	Confirm(summary DeletionSummary) (bool, error)
+}
+
+var _ IDeletionConfirmer = (*DeletionConfirmer)(nil)
+
+// DeletionSummary is what is shown before anything is deleted
+type DeletionSummary struct {
+	Buckets   []string // bucket names for S3 and S3Vectors, bucket arns for S3Tables
+	AccountId string   // empty for S3-compatible storage
+	Region    string
+	Mode      string
+	ForceMode bool
+}
+
+// DeletionConfirmer asks a user to confirm the deletion before anything is deleted
+type DeletionConfirmer struct {
+	inputManager   io.IInputManager
+	typedThreshold int
+}
+
+// NewDeletionConfirmer creates a new DeletionConfirmer instance.
+// For more buckets than typedThreshold, the number of them must be typed.
+func NewDeletionConfirmer(typedThreshold int) *DeletionConfirmer {
+	return &DeletionConfirmer{
+		inputManager:   io.NewInputManager(),
+		typedThreshold: typedThreshold,
+	}
+}
+
+// Confirm shows the summary and returns false if the user cancels the deletion.
+// With -f or more buckets than the threshold, the bucket name (or the number of the buckets) must be typed.
+// Otherwise, y or yes must be answered, and an empty answer cancels it.
+func (c *DeletionConfirmer) Confirm(summary DeletionSummary) (bool, error) {
+	// NOTE: Nobody can confirm the deletion in CI or scripts, so it fails closed unless --yes is specified explicitly.
+	if !c.inputManager.IsInteractive() {
+		errMsg := fmt.Sprintln("The deletion cannot be confirmed in a non-interactive environment. Specify the --yes option to clear the buckets without the confirmation.")
+		return false, fmt.Errorf("InvalidOptionError: %v", errMsg)
+	}
+
+	bucketNames := make([]string, 0, len(summary.Buckets))
+	for _, bucket := range summary.Buckets {
+		bucketNames = append(bucketNames, bucketDisplayName(bucket))
+	}
+
+	action := "cleared"
+	if summary.ForceMode {
+		action = "cleared and DELETED"
+	}
+	account := summary.AccountId
+	if account == "" {
+		account = "-"
+	}
+	io.Logger.Info().Msgf(
+		"The following %v buckets will be %v:\n%v\nAccount: %v, Region: %v, Mode: %v",
+		len(bucketNames),
+		action,
+		strings.Join(bucketNames, "\n"),
+		account,
+		summary.Region,
+		summary.Mode,
+	)
+
+	if !summary.ForceMode && len(bucketNames) <= c.typedThreshold {
+		answer := strings.ToLower(c.inputManager.InputText("Do you want to continue? (y/N) "))
+		if answer == "y" || answer == "yes" {
+			return true, nil
+		}
+		io.Logger.Info().Msg("Canceled.")
+		return false, nil
+	}
+
+	expected := strconv.Itoa(len(bucketNames))
+	label := fmt.Sprintf("Type the number of the buckets (%v) to continue: ", expected)
+	if len(bucketNames) == 1 {
+		expected = bucketNames[0]
+		label = fmt.Sprintf("Type the bucket name (%v) to continue: ", expected)
+	}
+	if answer := c.inputManager.InputText(label); answer != expected {
+		io.Logger.Info().Msgf("%q does not match %q. Canceled.", answer, expected)
+		return false, nil
+	}
+	return true, nil
+}
+
+// bucketDisplayName returns the bucket name of a bucket arn for S3Tables, otherwise the bucket name as it is
+func bucketDisplayName(bucket string) string {
+	if _, name, found := strings.Cut(bucket, "/"); found {
+		return name
+	}
+	return bucket
+}
@@ -0,0 +1,136 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/mock/gomock"
+)
+
+func TestDeletionConfirmer_Confirm(t *testing.T) {
+	io.NewLogger(false)
+
+	tests := []struct {
+		name           string
+		summary        DeletionSummary
+		typedThreshold int
+		prepareMockFn  func(mi *io.MockIInputManager)
+		want           bool
+		wantErr        string
+	}{
+		{
+			name:           "continue when yes is answered",
+			summary:        DeletionSummary{Buckets: []string{"bucket1", "bucket2"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general"},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Do you want to continue? (y/N) ").Return("Y")
+			},
+			want: true,
+		},
+		{
+			name:           "cancel when the answer is empty",
+			summary:        DeletionSummary{Buckets: []string{"bucket1"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general"},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Do you want to continue? (y/N) ").Return("")
+			},
+			want: false,
+		},
+		{
+			name:           "continue when the bucket name is typed in the force mode",
+			summary:        DeletionSummary{Buckets: []string{"bucket1"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general", ForceMode: true},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the bucket name (bucket1) to continue: ").Return("bucket1")
+			},
+			want: true,
+		},
+		{
+			name: "continue when the table bucket name is typed instead of the arn",
+			summary: DeletionSummary{
+				Buckets:   []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/tables"},
+				AccountId: "123456789012",
+				Region:    "us-east-1",
+				Mode:      "table",
+				ForceMode: true,
+			},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the bucket name (tables) to continue: ").Return("tables")
+			},
+			want: true,
+		},
+		{
+			name:           "continue when the number of the buckets is typed for more buckets than the threshold",
+			summary:        DeletionSummary{Buckets: []string{"bucket1", "bucket2", "bucket3", "bucket4"}, Region: "us-east-1", Mode: "general"},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the number of the buckets (4) to continue: ").Return("4")
+			},
+			want: true,
+		},
+		{
+			name:           "continue when the number of the buckets is typed for more buckets than the specified threshold",
+			summary:        DeletionSummary{Buckets: []string{"bucket1", "bucket2"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general"},
+			typedThreshold: 1,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the number of the buckets (2) to continue: ").Return("2")
+			},
+			want: true,
+		},
+		{
+			name:           "continue when the bucket name is typed with the threshold of 0",
+			summary:        DeletionSummary{Buckets: []string{"bucket1"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general"},
+			typedThreshold: 0,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the bucket name (bucket1) to continue: ").Return("bucket1")
+			},
+			want: true,
+		},
+		{
+			name:           "cancel when the typed text does not match",
+			summary:        DeletionSummary{Buckets: []string{"bucket1", "bucket2"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general", ForceMode: true},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(true)
+				mi.EXPECT().InputText("Type the number of the buckets (2) to continue: ").Return("y")
+			},
+			want: false,
+		},
+		{
+			name:           "error in a non-interactive environment",
+			summary:        DeletionSummary{Buckets: []string{"bucket1"}, AccountId: "123456789012", Region: "us-east-1", Mode: "general"},
+			typedThreshold: DefaultTypedConfirmationThreshold,
+			prepareMockFn: func(mi *io.MockIInputManager) {
+				mi.EXPECT().IsInteractive().Return(false)
+			},
+			want:    false,
+			wantErr: "InvalidOptionError: The deletion cannot be confirmed in a non-interactive environment. Specify the --yes option to clear the buckets without the confirmation.\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			mockInputManager := io.NewMockIInputManager(ctrl)
+			tt.prepareMockFn(mockInputManager)
+
+			confirmer := &DeletionConfirmer{inputManager: mockInputManager, typedThreshold: tt.typedThreshold}
+			got, err := confirmer.Confirm(tt.summary)
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -0,0 +1,168 @@
+package checkpoint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/wrapper"
+)
+
+// Version is the format version of checkpoint files written by this cls3.
+const Version = 3
+
+// Options are the options a checkpoint was saved with. A checkpoint is only used to resume
+// the clearing with the same options because the markers depend on them. The relative times
+// (e.g. 7d) are kept as specified, and the times resolved from them are saved in the checkpoint.
+// The account, the region, the endpoint and the mode identify the bucket together with its name.
+type Options struct {
+	AccountId          string   `json:"accountId"`
+	Region             string   `json:"region"`
+	EndpointUrl        string   `json:"endpointUrl"`
+	Mode               string   `json:"mode"`
+	OldVersionsOnly    bool     `json:"oldVersionsOnly"`
+	KeyPrefixes        []string `json:"keyPrefixes,omitempty"`
+	IncludePatterns    []string `json:"includePatterns,omitempty"`
+	ExcludePatterns    []string `json:"excludePatterns,omitempty"`
+	OlderThan          string   `json:"olderThan,omitempty"`
+	NewerThan          string   `json:"newerThan,omitempty"`
+	KeepVersions       int      `json:"keepVersions,omitempty"`
+	KeepNoncurrentDays int      `json:"keepNoncurrentDays,omitempty"`
+	StorageClasses     string   `json:"storageClasses,omitempty"`
+	MinSize            string   `json:"minSize,omitempty"`
+	MaxSize            string   `json:"maxSize,omitempty"`
+	Partitions         int      `json:"partitions,omitempty"`
+	SplitPoints        []string `json:"splitPoints,omitempty"`
+}
+
+// equal compares the options as saved in a checkpoint file, where nil and empty lists are the same.
+func (o Options) equal(other Options) bool {
+	a, errA := json.Marshal(o)
+	b, errB := json.Marshal(other)
+	return errA == nil && errB == nil && string(a) == string(b)
+}
+
+// file is the content of a checkpoint file. Bucket is the key of the checkpoint as it is,
+// because the file name is its hash.
+type file struct {
+	Version    int                `json:"version"`
+	UpdatedAt  time.Time          `json:"updatedAt"`
+	Bucket     string             `json:"bucket"`
+	Options    Options            `json:"options"`
+	Checkpoint wrapper.Checkpoint `json:"checkpoint"`
+}
+
+var _ wrapper.ICheckpointer = (*Store)(nil)
+
+// Store saves the checkpoints of buckets as files in a directory, one file for each bucket.
+// It is safe for concurrent use for different buckets.
+type Store struct {
+	dir     string
+	options Options
+}
+
+// NewStore creates the directory for checkpoint files if it does not exist
+func NewStore(dir string, options Options) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("CheckpointFileError: %w", err)
+	}
+	return &Store{
+		dir:     dir,
+		options: options,
+	}, nil
+}
+
+// DefaultDir returns the directory for checkpoint files in the user cache directory
+func DefaultDir() (string, error) {
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("CheckpointFileError: %w", err)
+	}
+	return filepath.Join(cacheDir, "cls3", "checkpoints"), nil
+}
+
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// Load returns false if there is no checkpoint for the bucket. A checkpoint saved with
+// other options is ignored, so the clearing starts from the beginning and overwrites it.
+func (s *Store) Load(bucket string) (wrapper.Checkpoint, bool, error) {
+	data, err := os.ReadFile(s.path(bucket))
+	if errors.Is(err, os.ErrNotExist) {
+		return wrapper.Checkpoint{}, false, nil
+	}
+	if err != nil {
+		return wrapper.Checkpoint{}, false, fmt.Errorf("CheckpointFileError: %w", err)
+	}
+
+	f := file{}
+	if err := json.Unmarshal(data, &f); err != nil {
+		return wrapper.Checkpoint{}, false, fmt.Errorf("CheckpointFileError: invalid checkpoint for %v: %w", bucket, err)
+	}
+	if f.Version != Version || f.Bucket != bucket || !f.Options.equal(s.options) {
+		io.Logger.Warn().Msgf("%v The checkpoint was saved with other options, so the clearing starts from the beginning.", bucket)
+		return wrapper.Checkpoint{}, false, nil
+	}
+
+	return f.Checkpoint, true, nil
+}
+
+// Save writes the checkpoint to a temporary file and renames it so that a checkpoint file
+// is never left half-written.
+func (s *Store) Save(bucket string, checkpoint wrapper.Checkpoint) error {
+	data, err := json.Marshal(file{
+		Version:    Version,
+		UpdatedAt:  time.Now().UTC(),
+		Bucket:     bucket,
+		Options:    s.options,
+		Checkpoint: checkpoint,
+	})
+	if err != nil {
+		return fmt.Errorf("CheckpointFileError: %w", err)
+	}
+
+	// NOTE: The temporary file is unique so that the saves of different buckets never write the same file.
+	tmpFile, err := os.CreateTemp(s.dir, "*.json.tmp")
+	if err != nil {
+		return fmt.Errorf("CheckpointFileError: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	_, err = tmpFile.Write(data)
+	if closeErr := tmpFile.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, s.path(bucket))
+	}
+	if err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("CheckpointFileError: %w", err)
+	}
+	return nil
+}
+
+// Delete does nothing if there is no checkpoint for the bucket
+func (s *Store) Delete(bucket string) error {
+	if err := os.Remove(s.path(bucket)); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("CheckpointFileError: %w", err)
+	}
+	return nil
+}
+
+// path returns the file of a checkpoint named by the hash of the key, because bucket arns for S3Tables
+// and key prefixes have characters that cannot be used in file names, and replacing them is lossy.
+// The key includes where the bucket is, so that a bucket with the same name in another account, region,
+// endpoint or mode never shares the checkpoint.
+func (s *Store) path(bucket string) string {
+	key := strings.Join([]string{s.options.AccountId, s.options.Region, s.options.EndpointUrl, s.options.Mode, bucket}, "\n")
+	hash := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(hash[:])+".json")
+}
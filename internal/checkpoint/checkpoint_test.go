@@ -0,0 +1,188 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestStore(t *testing.T) {
+	io.NewLogger(false)
+
+	checkpoint := wrapper.Checkpoint{
+		KeyMarker:       aws.String("key"),
+		VersionIdMarker: aws.String("version"),
+		Counts:          wrapper.ClearBucketOutput{VersionsCount: 1000, DeleteMarkersCount: 10},
+	}
+
+	tests := []struct {
+		name        string
+		bucket      string
+		saveOptions Options
+		loadOptions Options
+		wantFound   bool
+	}{
+		{
+			name:        "load the saved checkpoint",
+			bucket:      "bucket",
+			saveOptions: Options{Mode: "general", KeyPrefixes: []string{"prefix/"}},
+			loadOptions: Options{Mode: "general", KeyPrefixes: []string{"prefix/"}},
+			wantFound:   true,
+		},
+		{
+			name:        "load the saved checkpoint for a bucket arn",
+			bucket:      "arn:aws:s3tables:us-east-1:123456789012:bucket/test",
+			saveOptions: Options{Mode: "table"},
+			loadOptions: Options{Mode: "table"},
+			wantFound:   true,
+		},
+		{
+			name:        "load the saved checkpoint with empty lists",
+			bucket:      "bucket",
+			saveOptions: Options{Mode: "general", IncludePatterns: []string{}},
+			loadOptions: Options{Mode: "general"},
+			wantFound:   true,
+		},
+		{
+			name:        "ignore a checkpoint saved with other options",
+			bucket:      "bucket",
+			saveOptions: Options{Mode: "general", KeyPrefixes: []string{"prefix/"}},
+			loadOptions: Options{Mode: "general", KeyPrefixes: []string{"other/"}},
+			wantFound:   false,
+		},
+		{
+			name:        "ignore a checkpoint saved with other patterns",
+			bucket:      "bucket",
+			saveOptions: Options{Mode: "general", IncludePatterns: []string{"*.log"}},
+			loadOptions: Options{Mode: "general", IncludePatterns: []string{"*.log"}, ExcludePatterns: []string{"tmp/*"}},
+			wantFound:   false,
+		},
+		{
+			name:        "ignore a checkpoint of a bucket with the same name in another account",
+			bucket:      "vectors",
+			saveOptions: Options{AccountId: "123456789012", Region: "us-east-1", Mode: "vector"},
+			loadOptions: Options{AccountId: "210987654321", Region: "us-east-1", Mode: "vector"},
+			wantFound:   false,
+		},
+		{
+			name:        "ignore a checkpoint of a bucket with the same name in another region",
+			bucket:      "vectors",
+			saveOptions: Options{AccountId: "123456789012", Region: "us-east-1", Mode: "vector"},
+			loadOptions: Options{AccountId: "123456789012", Region: "us-west-2", Mode: "vector"},
+			wantFound:   false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "checkpoints")
+
+			saveStore, err := NewStore(dir, tt.saveOptions)
+			require.NoError(t, err)
+			require.NoError(t, saveStore.Save(tt.bucket, checkpoint))
+
+			loadStore, err := NewStore(dir, tt.loadOptions)
+			require.NoError(t, err)
+			got, found, err := loadStore.Load(tt.bucket)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.wantFound, found)
+			if tt.wantFound {
+				assert.Equal(t, checkpoint, got)
+			} else {
+				assert.Equal(t, wrapper.Checkpoint{}, got)
+			}
+		})
+	}
+}
+
+func TestStore_Load(t *testing.T) {
+	dir := t.TempDir()
+	store, err := NewStore(dir, Options{Mode: "general"})
+	require.NoError(t, err)
+
+	t.Run("not found for a bucket without a checkpoint", func(t *testing.T) {
+		_, found, err := store.Load("bucket")
+		require.NoError(t, err)
+		assert.False(t, found)
+	})
+
+	t.Run("error for an invalid checkpoint", func(t *testing.T) {
+		require.NoError(t, os.WriteFile(store.path("invalid"), []byte("not json"), 0o600))
+		_, _, err := store.Load("invalid")
+		assert.EqualError(t, err, "CheckpointFileError: invalid checkpoint for invalid: invalid character 'o' in literal null (expecting 'u')")
+	})
+}
+
+func TestStore_DistinctKeys(t *testing.T) {
+	store, err := NewStore(t.TempDir(), Options{Mode: "general"})
+	require.NoError(t, err)
+
+	// keys that differ only in the characters that cannot be used in file names
+	keys := []string{"bucket/a/b@x", "bucket/a_b@x", "bucket/a:b@x", "bucket/a*b@x"}
+	for i, key := range keys {
+		require.NoError(t, store.Save(key, wrapper.Checkpoint{KeyMarker: aws.String(key), Counts: wrapper.ClearBucketOutput{ObjectsCount: int64(i)}}))
+	}
+
+	for i, key := range keys {
+		got, found, err := store.Load(key)
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, wrapper.Checkpoint{KeyMarker: aws.String(key), Counts: wrapper.ClearBucketOutput{ObjectsCount: int64(i)}}, got)
+	}
+
+	// no temporary files are left
+	entries, err := os.ReadDir(store.Dir())
+	require.NoError(t, err)
+	assert.Len(t, entries, len(keys))
+}
+
+func TestStore_DistinctLocations(t *testing.T) {
+	dir := t.TempDir()
+
+	// buckets with the same name in different accounts and regions
+	optionsList := []Options{
+		{AccountId: "123456789012", Region: "us-east-1", Mode: "vector"},
+		{AccountId: "210987654321", Region: "us-east-1", Mode: "vector"},
+		{AccountId: "123456789012", Region: "us-west-2", Mode: "vector"},
+	}
+	for i, options := range optionsList {
+		store, err := NewStore(dir, options)
+		require.NoError(t, err)
+		require.NoError(t, store.Save("vectors", wrapper.Checkpoint{Counts: wrapper.ClearBucketOutput{IndexesCount: int64(i)}}))
+	}
+
+	for i, options := range optionsList {
+		store, err := NewStore(dir, options)
+		require.NoError(t, err)
+		got, found, err := store.Load("vectors")
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, wrapper.Checkpoint{Counts: wrapper.ClearBucketOutput{IndexesCount: int64(i)}}, got)
+	}
+}
+
+func TestStore_Delete(t *testing.T) {
+	store, err := NewStore(t.TempDir(), Options{Mode: "general"})
+	require.NoError(t, err)
+
+	require.NoError(t, store.Save("bucket", wrapper.Checkpoint{KeyMarker: aws.String("key")}))
+	require.NoError(t, store.Delete("bucket"))
+
+	_, found, err := store.Load("bucket")
+	require.NoError(t, err)
+	assert.False(t, found)
+
+	// deleting a checkpoint that does not exist is not an error
+	assert.NoError(t, store.Delete("bucket"))
+}
@@ -0,0 +1,16 @@
+package checkpoint
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	fmt.Println()
+	fmt.Println("==========================================")
+	fmt.Println("========= Start Test: checkpoint =========")
+	fmt.Println("==========================================")
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,79 @@
+package interrupt
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"sync"
+
+	"github.com/go-to-k/cls3/internal/io"
+)
+
+// ExitCode is the exit code of a run interrupted by a signal, as the shells do for SIGINT.
+const ExitCode = 130
+
+// ErrInterrupted is the error of the work stopped by a signal.
+var ErrInterrupted = errors.New("InterruptedError: stopped by a signal")
+
+type stoppingKey struct{}
+
+// WithStopping returns a context that stops (see Stopping) when the stop function is called.
+// Unlike canceling a context, stopping it does not abort the requests in flight.
+func WithStopping(parent context.Context) (context.Context, func()) {
+	stopping := make(chan struct{})
+	var once sync.Once
+	stop := func() {
+		once.Do(func() { close(stopping) })
+	}
+	return context.WithValue(parent, stoppingKey{}, (<-chan struct{})(stopping)), stop
+}
+
+// NotifyContext returns a context that stops on the first signal, so that the work in progress can finish,
+// and exits with ExitCode on the second signal. The returned function stops relaying the signals.
+func NotifyContext(parent context.Context, signals ...os.Signal) (context.Context, func()) {
+	ctx, stop := WithStopping(parent)
+
+	signalCh := make(chan os.Signal, 1)
+	signal.Notify(signalCh, signals...)
+	done := make(chan struct{})
+
+	go func() {
+		select {
+		case <-signalCh:
+		case <-done:
+			return
+		}
+		io.Logger.Warn().Msg("Interrupted. Stopping after the deletions in progress finish... Send the signal again to force exit.")
+		stop()
+
+		select {
+		case <-signalCh:
+		case <-done:
+			return
+		}
+		io.Logger.Warn().Msg("Forced to exit.")
+		os.Exit(ExitCode)
+	}()
+
+	return ctx, func() {
+		signal.Stop(signalCh)
+		close(done)
+	}
+}
+
+// Stopping returns a channel closed when the context stops, or nil if it never stops.
+func Stopping(ctx context.Context) <-chan struct{} {
+	stopping, _ := ctx.Value(stoppingKey{}).(<-chan struct{})
+	return stopping
+}
+
+// IsStopping returns true if the context has stopped.
+func IsStopping(ctx context.Context) bool {
+	select {
+	case <-Stopping(ctx):
+		return true
+	default:
+		return false
+	}
+}
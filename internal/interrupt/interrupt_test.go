@@ -0,0 +1,45 @@
+package interrupt
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/stretchr/testify/assert"
+)
+
+/*
+	Test Cases
+*/
+
+func TestWithStopping(t *testing.T) {
+	ctx, stop := WithStopping(context.Background())
+	assert.False(t, IsStopping(ctx))
+
+	stop()
+	stop() // stopping twice does not panic
+	assert.True(t, IsStopping(ctx))
+	assert.NoError(t, ctx.Err(), "stopping must not cancel the context")
+}
+
+func TestIsStopping_WithoutStopping(t *testing.T) {
+	assert.Nil(t, Stopping(context.Background()))
+	assert.False(t, IsStopping(context.Background()))
+}
+
+func TestNotifyContext(t *testing.T) {
+	io.NewLogger(false)
+
+	ctx, stop := NotifyContext(context.Background(), syscall.SIGUSR1)
+	defer stop()
+
+	assert.NoError(t, syscall.Kill(syscall.Getpid(), syscall.SIGUSR1))
+	select {
+	case <-Stopping(ctx):
+	case <-time.After(5 * time.Second):
+		t.Fatal("the context did not stop on the signal")
+	}
+	assert.NoError(t, ctx.Err(), "the first signal must not cancel the context")
+}
@@ -0,0 +1,16 @@
+package interrupt
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	fmt.Println()
+	fmt.Println("==========================================")
+	fmt.Println("========= Start Test: interrupt ==========")
+	fmt.Println("==========================================")
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,373 @@
+package inventory
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	goio "io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// S3URIPrefix is the prefix of a manifest location in S3 (e.g. s3://bucket/path/manifest.json).
+const S3URIPrefix = "s3://"
+
+const (
+	columnKey            = "Key"
+	columnVersionId      = "VersionId"
+	columnIsLatest       = "IsLatest"
+	columnIsDeleteMarker = "IsDeleteMarker"
+	columnSize           = "Size"
+	columnLastModified   = "LastModifiedDate"
+	columnStorageClass   = "StorageClass"
+)
+
+// Manifest is the manifest.json of an S3 Inventory report.
+type Manifest struct {
+	SourceBucket      string `json:"sourceBucket"`
+	DestinationBucket string `json:"destinationBucket"` // bucket arn
+	FileFormat        string `json:"fileFormat"`        // CSV, ORC or Parquet
+	FileSchema        string `json:"fileSchema"`        // comma-separated field names for CSV
+	Files             []File `json:"files"`
+}
+
+// File is an inventory file in the destination bucket.
+type File struct {
+	Key  string `json:"key"`
+	Size int64  `json:"size"`
+}
+
+// Options determine the targets in an inventory report in the same way as listing.
+type Options struct {
+	OldVersionsOnly bool
+	Prefixes        []string             // all keys if empty
+	Filter          *client.ObjectFilter // the retention of noncurrent versions is not supported
+}
+
+var _ wrapper.ITargetSource = (*Reader)(nil)
+
+// Reader reads the targets from the CSV files of an S3 Inventory report, in a local directory
+// (e.g. synced with `aws s3 sync`) or in the destination bucket.
+type Reader struct {
+	// NOTE: ITargetSource does not take a context, so the one to read the files from S3 is held.
+	ctx               context.Context
+	client            client.IS3 // nil for a local manifest
+	manifestPath      string     // local path of the manifest, or the key in the destination bucket
+	destinationBucket string
+	destinationRegion string
+	manifest          *Manifest
+	columns           map[string]int
+	options           Options
+}
+
+// NewReader reads and validates the manifest of an S3 Inventory report from a local path or an S3 URI.
+// The client is only used for an S3 URI.
+func NewReader(ctx context.Context, location string, s3Client client.IS3, options Options) (*Reader, error) {
+	r := &Reader{
+		ctx:          ctx,
+		manifestPath: location,
+		options:      options,
+	}
+
+	var data []byte
+	if strings.HasPrefix(location, S3URIPrefix) {
+		bucket, key, ok := strings.Cut(strings.TrimPrefix(location, S3URIPrefix), "/")
+		if !ok || bucket == "" || key == "" {
+			return nil, fmt.Errorf("InventoryError: invalid S3 URI of the manifest: %v", location)
+		}
+		region, err := s3Client.GetBucketLocation(ctx, aws.String(bucket))
+		if err != nil {
+			return nil, err
+		}
+		r.client = s3Client
+		r.manifestPath = key
+		r.destinationBucket = bucket
+		r.destinationRegion = region
+
+		body, err := s3Client.GetObject(ctx, aws.String(bucket), aws.String(key), region)
+		if err != nil {
+			return nil, err
+		}
+		defer body.Close()
+		data, err = goio.ReadAll(body)
+		if err != nil {
+			return nil, fmt.Errorf("InventoryError: %w", err)
+		}
+	} else {
+		var err error
+		data, err = os.ReadFile(location)
+		if err != nil {
+			return nil, fmt.Errorf("InventoryError: %w", err)
+		}
+	}
+
+	manifest := &Manifest{}
+	if err := json.Unmarshal(data, manifest); err != nil {
+		return nil, fmt.Errorf("InventoryError: invalid manifest: %w", err)
+	}
+	r.manifest = manifest
+
+	if err := r.validate(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *Reader) Manifest() *Manifest {
+	return r.manifest
+}
+
+// validate returns an error if the targets cannot be determined with the fields in the inventory report
+func (r *Reader) validate() error {
+	if !strings.EqualFold(r.manifest.FileFormat, "CSV") {
+		return fmt.Errorf("InventoryError: the %v format is not supported, only the inventory reports in the CSV format are supported", r.manifest.FileFormat)
+	}
+
+	r.columns = map[string]int{}
+	for i, name := range strings.Split(r.manifest.FileSchema, ",") {
+		r.columns[strings.TrimSpace(name)] = i
+	}
+
+	requiredColumns := []string{columnKey}
+	if r.options.OldVersionsOnly {
+		requiredColumns = append(requiredColumns, columnVersionId, columnIsLatest, columnIsDeleteMarker)
+	}
+	if filter := r.options.Filter; filter != nil {
+		if filter.KeepVersions > 0 || filter.KeepNoncurrentSince != nil {
+			return fmt.Errorf("InventoryError: keeping noncurrent versions is not supported with an inventory report")
+		}
+		if filter.OlderThan != nil || filter.NewerThan != nil {
+			requiredColumns = append(requiredColumns, columnLastModified)
+		}
+		if len(filter.StorageClasses) > 0 {
+			requiredColumns = append(requiredColumns, columnStorageClass)
+		}
+		if filter.MinSize != nil || filter.MaxSize != nil {
+			requiredColumns = append(requiredColumns, columnSize)
+		}
+	}
+	for _, column := range requiredColumns {
+		if _, ok := r.columns[column]; !ok {
+			return fmt.Errorf("InventoryError: the inventory report has no %v field, which is required for the options", column)
+		}
+	}
+
+	if r.client != nil {
+		// NOTE: The destination bucket in the manifest is an arn like arn:aws:s3:::bucket.
+		_, bucket, ok := strings.Cut(r.manifest.DestinationBucket, ":::")
+		if !ok || bucket == "" {
+			return fmt.Errorf("InventoryError: invalid destination bucket in the manifest: %v", r.manifest.DestinationBucket)
+		}
+		if bucket != r.destinationBucket {
+			region, err := r.client.GetBucketLocation(r.ctx, aws.String(bucket))
+			if err != nil {
+				return err
+			}
+			r.destinationBucket = bucket
+			r.destinationRegion = region
+		}
+	}
+	return nil
+}
+
+// ForEachTargets calls fn with the targets in the inventory files in order, up to 1000 targets at a time.
+func (r *Reader) ForEachTargets(bucket string, fn func(targets []wrapper.Target) error) error {
+	if bucket != r.manifest.SourceBucket {
+		return fmt.Errorf("InventoryError: the inventory report is for the bucket %v, not %v", r.manifest.SourceBucket, bucket)
+	}
+
+	targets := make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+	add := func(target wrapper.Target) error {
+		targets = append(targets, target)
+		if len(targets) < client.MaxDeleteObjectsCount {
+			return nil
+		}
+		if err := fn(targets); err != nil {
+			return err
+		}
+		targets = make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+		return nil
+	}
+
+	for _, file := range r.manifest.Files {
+		if err := r.readFile(file, add); err != nil {
+			return err
+		}
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+	return fn(targets)
+}
+
+func (r *Reader) readFile(file File, add func(target wrapper.Target) error) error {
+	body, err := r.openFile(file)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	var reader goio.Reader = body
+	if strings.HasSuffix(file.Key, ".gz") {
+		gzipReader, err := gzip.NewReader(body)
+		if err != nil {
+			return fmt.Errorf("InventoryError: %v: %w", file.Key, err)
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	}
+
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, goio.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("InventoryError: %v: %w", file.Key, err)
+		}
+
+		target, ok, err := r.toTarget(record)
+		if err != nil {
+			line, _ := csvReader.FieldPos(0)
+			return fmt.Errorf("InventoryError: %v at line %d: %w", file.Key, line, err)
+		}
+		if !ok {
+			continue
+		}
+		if err := add(target); err != nil {
+			return err
+		}
+	}
+}
+
+// openFile opens an inventory file in the destination bucket, or in the local directory of the manifest.
+func (r *Reader) openFile(file File) (goio.ReadCloser, error) {
+	if r.client != nil {
+		return r.client.GetObject(r.ctx, aws.String(r.destinationBucket), aws.String(file.Key), r.destinationRegion)
+	}
+
+	path, err := r.localPath(file)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("InventoryError: %w", err)
+	}
+	return f, nil
+}
+
+// localPath returns the local path of an inventory file. The key of the file is looked up from the
+// directory of the manifest up to the root, so that a local copy of the destination bucket (or a part
+// of it) can be used. A file next to the manifest is also found.
+func (r *Reader) localPath(file File) (string, error) {
+	manifestDir, err := filepath.Abs(filepath.Dir(r.manifestPath))
+	if err != nil {
+		return "", fmt.Errorf("InventoryError: %w", err)
+	}
+
+	candidates := []string{}
+	for dir := manifestDir; ; dir = filepath.Dir(dir) {
+		candidates = append(candidates, filepath.Join(dir, filepath.FromSlash(file.Key)))
+		if filepath.Dir(dir) == dir {
+			break
+		}
+	}
+	candidates = append(candidates, filepath.Join(manifestDir, filepath.Base(filepath.FromSlash(file.Key))))
+
+	for _, candidate := range candidates {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("InventoryError: the inventory file %v is not found around the manifest %v", file.Key, r.manifestPath)
+}
+
+// toTarget returns false if the record is not a deletion target with the options
+func (r *Reader) toTarget(record []string) (wrapper.Target, bool, error) {
+	field := func(name string) string {
+		if i, ok := r.columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	// NOTE: The keys in the CSV inventory reports are URL-encoded.
+	key, err := url.QueryUnescape(field(columnKey))
+	if err != nil {
+		return wrapper.Target{}, false, fmt.Errorf("invalid key %q: %w", field(columnKey), err)
+	}
+	if key == "" {
+		return wrapper.Target{}, false, fmt.Errorf("no key")
+	}
+
+	if len(r.options.Prefixes) > 0 && !slices.ContainsFunc(r.options.Prefixes, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	}) {
+		return wrapper.Target{}, false, nil
+	}
+
+	isLatest := field(columnIsLatest) != "false"
+	isDeleteMarker := field(columnIsDeleteMarker) == "true"
+	// NOTE: In the same way as listing, old versions only means the noncurrent versions and all delete markers.
+	if r.options.OldVersionsOnly && isLatest && !isDeleteMarker {
+		return wrapper.Target{}, false, nil
+	}
+
+	var lastModified *time.Time
+	if value := field(columnLastModified); value != "" {
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return wrapper.Target{}, false, fmt.Errorf("invalid %v %q: %w", columnLastModified, value, err)
+		}
+		lastModified = &t
+	}
+	var size *int64
+	if value := field(columnSize); value != "" {
+		s, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return wrapper.Target{}, false, fmt.Errorf("invalid %v %q: %w", columnSize, value, err)
+		}
+		size = &s
+	}
+
+	// NOTE: Delete markers have neither the storage class nor the size, so they are kept if the filter has them.
+	var match bool
+	if isDeleteMarker {
+		match = r.options.Filter.MatchObject(key, lastModified, "", nil)
+	} else {
+		match = r.options.Filter.MatchObject(key, lastModified, field(columnStorageClass), size)
+	}
+	if !match {
+		return wrapper.Target{}, false, nil
+	}
+
+	target := wrapper.Target{
+		Key:          key,
+		DeleteMarker: isDeleteMarker,
+	}
+	if _, ok := r.columns[columnVersionId]; ok {
+		// NOTE: The version id of the objects put before the versioning was enabled is empty in the
+		// inventory reports, but it must be "null" to delete the version rather than put a delete marker.
+		target.VersionId = field(columnVersionId)
+		if target.VersionId == "" {
+			target.VersionId = "null"
+		}
+	}
+	return target, true, nil
+}
@@ -0,0 +1,372 @@
+package inventory
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	goio "io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const testSchema = "Bucket, Key, VersionId, IsLatest, IsDeleteMarker, Size, LastModifiedDate, StorageClass"
+
+// testRecords are the records of an inventory file with all versions of the bucket.
+var testRecords = []string{
+	`"bucket","logs/a.log","v3","true","false","10","2025-06-01T00:00:00.000Z","STANDARD"`,
+	`"bucket","logs/a.log","v2","false","false","2000","2025-05-01T00:00:00.000Z","GLACIER"`,
+	`"bucket","logs/a.log","v1","false","true","","2025-04-01T00:00:00.000Z",""`,
+	`"bucket","logs/my+file%2B1.log","","true","false","30","2025-03-01T00:00:00.000Z","STANDARD"`,
+	`"bucket","tmp/b","v5","true","true","","2025-06-01T00:00:00.000Z",""`,
+}
+
+func gzipped(t *testing.T, content string) []byte {
+	t.Helper()
+	buf := bytes.Buffer{}
+	w := gzip.NewWriter(&buf)
+	_, err := w.Write([]byte(content))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+	return buf.Bytes()
+}
+
+// writeReport writes a report in the same layout as the destination bucket synced to a local directory,
+// and returns the path of the manifest.
+func writeReport(t *testing.T, manifest Manifest, files map[string][]byte) string {
+	t.Helper()
+	root := t.TempDir()
+	for key, data := range files {
+		path := filepath.Join(root, filepath.FromSlash(key))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+		require.NoError(t, os.WriteFile(path, data, 0o600))
+	}
+
+	data, err := json.Marshal(manifest)
+	require.NoError(t, err)
+	path := filepath.Join(root, "bucket", "config", "2025-06-15T00-00Z", "manifest.json")
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+/*
+	Test Cases
+*/
+
+func TestReader_ForEachTargets(t *testing.T) {
+	manifest := Manifest{
+		SourceBucket:      "bucket",
+		DestinationBucket: "arn:aws:s3:::inventory",
+		FileFormat:        "CSV",
+		FileSchema:        testSchema,
+		Files: []File{
+			{Key: "bucket/config/data/1.csv.gz"},
+			{Key: "bucket/config/data/2.csv"},
+		},
+	}
+	path := writeReport(t, manifest, map[string][]byte{
+		"bucket/config/data/1.csv.gz": gzipped(t, strings.Join(testRecords[:3], "\n")+"\n"),
+		"bucket/config/data/2.csv":    []byte(strings.Join(testRecords[3:], "\n") + "\n"),
+	})
+	include, err := client.CompileKeyPattern("*.log")
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		options Options
+		want    []wrapper.Target
+	}{
+		{
+			name:    "read all versions and delete markers",
+			options: Options{},
+			want: []wrapper.Target{
+				{Key: "logs/a.log", VersionId: "v3"},
+				{Key: "logs/a.log", VersionId: "v2"},
+				{Key: "logs/a.log", VersionId: "v1", DeleteMarker: true},
+				{Key: "logs/my file+1.log", VersionId: "null"},
+				{Key: "tmp/b", VersionId: "v5", DeleteMarker: true},
+			},
+		},
+		{
+			name:    "read old versions and all delete markers only",
+			options: Options{OldVersionsOnly: true},
+			want: []wrapper.Target{
+				{Key: "logs/a.log", VersionId: "v2"},
+				{Key: "logs/a.log", VersionId: "v1", DeleteMarker: true},
+				{Key: "tmp/b", VersionId: "v5", DeleteMarker: true},
+			},
+		},
+		{
+			name:    "read the objects under the key prefixes",
+			options: Options{Prefixes: []string{"tmp/", "other/"}},
+			want: []wrapper.Target{
+				{Key: "tmp/b", VersionId: "v5", DeleteMarker: true},
+			},
+		},
+		{
+			name: "read the objects matching the filter",
+			options: Options{Filter: &client.ObjectFilter{
+				OlderThan: aws.Time(time.Date(2025, 5, 15, 0, 0, 0, 0, time.UTC)),
+				Include:   []*regexp.Regexp{include},
+			}},
+			want: []wrapper.Target{
+				{Key: "logs/a.log", VersionId: "v2"},
+				{Key: "logs/a.log", VersionId: "v1", DeleteMarker: true},
+				{Key: "logs/my file+1.log", VersionId: "null"},
+			},
+		},
+		{
+			name: "read the objects matching the attributes without delete markers",
+			options: Options{Filter: &client.ObjectFilter{
+				StorageClasses: []string{"GLACIER", "STANDARD"},
+				MinSize:        aws.Int64(20),
+			}},
+			want: []wrapper.Target{
+				{Key: "logs/a.log", VersionId: "v2"},
+				{Key: "logs/my file+1.log", VersionId: "null"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(context.Background(), path, nil, tt.options)
+			require.NoError(t, err)
+
+			got := []wrapper.Target{}
+			err = reader.ForEachTargets("bucket", func(targets []wrapper.Target) error {
+				got = append(got, targets...)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReader_ForEachTargets_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		bucket  string
+		files   map[string][]byte
+		wantErr string
+	}{
+		{
+			name:    "error for another bucket",
+			bucket:  "other",
+			files:   map[string][]byte{"data/1.csv": []byte(testRecords[0] + "\n")},
+			wantErr: "InventoryError: the inventory report is for the bucket bucket, not other",
+		},
+		{
+			name:    "error for a file not found",
+			bucket:  "bucket",
+			files:   map[string][]byte{},
+			wantErr: "InventoryError: the inventory file data/1.csv is not found around the manifest <path>",
+		},
+		{
+			name:    "error for an invalid size",
+			bucket:  "bucket",
+			files:   map[string][]byte{"data/1.csv": []byte(testRecords[0] + "\n" + `"bucket","key","v1","true","false","large","2025-06-01T00:00:00.000Z","STANDARD"` + "\n")},
+			wantErr: `InventoryError: data/1.csv at line 2: invalid Size "large": strconv.ParseInt: parsing "large": invalid syntax`,
+		},
+		{
+			name:    "error for an invalid gzip file",
+			bucket:  "bucket",
+			files:   map[string][]byte{"data/1.csv": []byte(testRecords[0] + "\n"), "data/2.csv.gz": []byte("not gzip")},
+			wantErr: "InventoryError: data/2.csv.gz: unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			files := []File{{Key: "data/1.csv"}}
+			if _, ok := tt.files["data/2.csv.gz"]; ok {
+				files = append(files, File{Key: "data/2.csv.gz"})
+			}
+			path := writeReport(t, Manifest{
+				SourceBucket: "bucket",
+				FileFormat:   "CSV",
+				FileSchema:   testSchema,
+				Files:        files,
+			}, tt.files)
+
+			reader, err := NewReader(context.Background(), path, nil, Options{})
+			require.NoError(t, err)
+
+			err = reader.ForEachTargets(tt.bucket, func(targets []wrapper.Target) error {
+				return nil
+			})
+			assert.EqualError(t, err, strings.ReplaceAll(tt.wantErr, "<path>", path))
+		})
+	}
+}
+
+func TestReader_ForEachTargets_Batches(t *testing.T) {
+	records := make([]string, 0, 2001)
+	for i := 0; i < 2001; i++ {
+		records = append(records, fmt.Sprintf(`"bucket","key%d"`, i))
+	}
+	path := writeReport(t, Manifest{
+		SourceBucket: "bucket",
+		FileFormat:   "CSV",
+		FileSchema:   "Bucket, Key",
+		Files:        []File{{Key: "1.csv"}},
+	}, map[string][]byte{
+		// NOTE: A file next to the manifest is also found.
+		"bucket/config/2025-06-15T00-00Z/1.csv": []byte(strings.Join(records, "\n")),
+	})
+
+	reader, err := NewReader(context.Background(), path, nil, Options{})
+	require.NoError(t, err)
+
+	batchLen := []int{}
+	err = reader.ForEachTargets("bucket", func(targets []wrapper.Target) error {
+		batchLen = append(batchLen, len(targets))
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1000, 1000, 1}, batchLen)
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name     string
+		manifest Manifest
+		options  Options
+		wantErr  string
+	}{
+		{
+			name:     "read a valid manifest",
+			manifest: Manifest{SourceBucket: "bucket", FileFormat: "CSV", FileSchema: testSchema},
+			options: Options{OldVersionsOnly: true, Filter: &client.ObjectFilter{
+				NewerThan:      aws.Time(time.Now()),
+				StorageClasses: []string{"STANDARD"},
+				MaxSize:        aws.Int64(1),
+			}},
+			wantErr: "",
+		},
+		{
+			name:     "error for the Parquet format",
+			manifest: Manifest{SourceBucket: "bucket", FileFormat: "Parquet"},
+			wantErr:  "InventoryError: the Parquet format is not supported, only the inventory reports in the CSV format are supported",
+		},
+		{
+			name:     "error for the inventory report without versions with old versions only",
+			manifest: Manifest{SourceBucket: "bucket", FileFormat: "CSV", FileSchema: "Bucket, Key, Size"},
+			options:  Options{OldVersionsOnly: true},
+			wantErr:  "InventoryError: the inventory report has no VersionId field, which is required for the options",
+		},
+		{
+			name:     "error for the inventory report without the last modified date with the time filter",
+			manifest: Manifest{SourceBucket: "bucket", FileFormat: "CSV", FileSchema: "Bucket, Key, Size"},
+			options:  Options{Filter: &client.ObjectFilter{OlderThan: aws.Time(time.Now())}},
+			wantErr:  "InventoryError: the inventory report has no LastModifiedDate field, which is required for the options",
+		},
+		{
+			name:     "error for the retention of noncurrent versions",
+			manifest: Manifest{SourceBucket: "bucket", FileFormat: "CSV", FileSchema: testSchema},
+			options:  Options{OldVersionsOnly: true, Filter: &client.ObjectFilter{KeepVersions: 1}},
+			wantErr:  "InventoryError: keeping noncurrent versions is not supported with an inventory report",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeReport(t, tt.manifest, nil)
+
+			_, err := NewReader(context.Background(), path, nil, tt.options)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewReader_S3(t *testing.T) {
+	manifest := Manifest{
+		SourceBucket:      "bucket",
+		DestinationBucket: "arn:aws:s3:::inventory",
+		FileFormat:        "CSV",
+		FileSchema:        testSchema,
+		Files:             []File{{Key: "bucket/config/data/1.csv.gz"}},
+	}
+	manifestData, err := json.Marshal(manifest)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name          string
+		location      string
+		prepareMockFn func(m *client.MockIS3)
+		want          []wrapper.Target
+		wantErr       string
+	}{
+		{
+			name:     "read the manifest and the files in the destination bucket",
+			location: "s3://inventory/bucket/config/2025-06-15T00-00Z/manifest.json",
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("inventory")).Return("ap-northeast-1", nil)
+				m.EXPECT().GetObject(gomock.Any(), aws.String("inventory"), aws.String("bucket/config/2025-06-15T00-00Z/manifest.json"), "ap-northeast-1").Return(
+					goio.NopCloser(bytes.NewReader(manifestData)), nil)
+				m.EXPECT().GetObject(gomock.Any(), aws.String("inventory"), aws.String("bucket/config/data/1.csv.gz"), "ap-northeast-1").Return(
+					goio.NopCloser(bytes.NewReader(gzipped(t, testRecords[0]+"\n"))), nil)
+			},
+			want: []wrapper.Target{
+				{Key: "logs/a.log", VersionId: "v3"},
+			},
+		},
+		{
+			name:          "error for an invalid S3 URI",
+			location:      "s3://inventory",
+			prepareMockFn: func(m *client.MockIS3) {},
+			wantErr:       "InventoryError: invalid S3 URI of the manifest: s3://inventory",
+		},
+		{
+			name:     "error for get object errors",
+			location: "s3://inventory/manifest.json",
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("inventory")).Return("us-east-1", nil)
+				m.EXPECT().GetObject(gomock.Any(), aws.String("inventory"), aws.String("manifest.json"), "us-east-1").Return(nil, fmt.Errorf("GetObjectError"))
+			},
+			wantErr: "GetObjectError",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s3Mock := client.NewMockIS3(ctrl)
+			tt.prepareMockFn(s3Mock)
+
+			reader, err := NewReader(context.Background(), tt.location, s3Mock, Options{})
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			got := []wrapper.Target{}
+			err = reader.ForEachTargets("bucket", func(targets []wrapper.Target) error {
+				got = append(got, targets...)
+				return nil
+			})
+			assert.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
@@ -14,8 +14,10 @@ import (
 // IInputManager defines the interface for handling user input operations
 type IInputManager interface {
 	InputKeywordForFilter(label string) string
+	InputText(label string) string
 	GetCheckboxes(headers []string, opts []string) ([]string, bool, error)
 	GetYesNo(label string) bool
+	IsInteractive() bool
 }
 
 type InputManager struct{}
@@ -56,16 +58,19 @@ func (im *InputManager) GetCheckboxes(headers []string, opts []string) ([]string
 			continue
 		}
 
+		// NOTE: The selected buckets are confirmed with the summary of the deletion afterward, and selected again
+		// if it is declined, so they are not asked for here.
 		fmt.Fprintf(os.Stderr, " %s\n", color.CyanString(strings.Join(checkboxes, ", ")))
-
-		ok := im.GetYesNo("OK?")
-		if ok {
-			return checkboxes, true, nil
-		}
+		return checkboxes, true, nil
 	}
 }
 
 func (im *InputManager) InputKeywordForFilter(label string) string {
+	return im.InputText(label)
+}
+
+// InputText returns a line typed by a user without the surrounding spaces
+func (im *InputManager) InputText(label string) string {
 	reader := bufio.NewReader(os.Stdin)
 
 	fmt.Fprintf(os.Stderr, "%s", label)
@@ -100,3 +105,15 @@ func (im *InputManager) GetYesNo(label string) bool {
 		}
 	}
 }
+
+// IsInteractive returns true if a user can answer the prompts, i.e. stdin is a terminal and it is not run in CI.
+func (im *InputManager) IsInteractive() bool {
+	if os.Getenv("CI") != "" {
+		return false
+	}
+	stat, err := os.Stdin.Stat()
+	if err != nil {
+		return false
+	}
+	return stat.Mode()&os.ModeCharDevice != 0
+}
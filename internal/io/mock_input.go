@@ -82,3 +82,31 @@ func (mr *MockIInputManagerMockRecorder) InputKeywordForFilter(label any) *gomoc
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InputKeywordForFilter", reflect.TypeOf((*MockIInputManager)(nil).InputKeywordForFilter), label)
 }
+
+// InputText mocks base method.
+func (m *MockIInputManager) InputText(label string) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InputText", label)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// InputText indicates an expected call of InputText.
+func (mr *MockIInputManagerMockRecorder) InputText(label any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InputText", reflect.TypeOf((*MockIInputManager)(nil).InputText), label)
+}
+
+// IsInteractive mocks base method.
+func (m *MockIInputManager) IsInteractive() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsInteractive")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsInteractive indicates an expected call of IsInteractive.
+func (mr *MockIInputManagerMockRecorder) IsInteractive() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsInteractive", reflect.TypeOf((*MockIInputManager)(nil).IsInteractive))
+}
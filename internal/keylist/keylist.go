@@ -0,0 +1,220 @@
+package keylist
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	goio "io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// StdinPath is the path to read the keys from stdin.
+const StdinPath = "-"
+
+type Format string
+
+const (
+	FormatText  Format = "text"
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+// entry is each line of a key list in the JSONL format, which is compatible with plan and manifest files.
+type entry struct {
+	Bucket string `json:"bucket"`
+	wrapper.Target
+}
+
+// FormatOf returns the format of a key list file by its extension: CSV for .csv, JSONL for .jsonl,
+// .ndjson and .json, otherwise lines of `key` or `key<TAB>versionId`.
+func FormatOf(path string) Format {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV
+	case ".jsonl", ".ndjson", ".json":
+		return FormatJSONL
+	default:
+		return FormatText
+	}
+}
+
+var _ wrapper.ITargetSource = (*Reader)(nil)
+
+// Reader reads the keys of the objects to be deleted from a file or stdin.
+// NOTE: Stdin can be read only once, so a key list is only for one bucket.
+type Reader struct {
+	path  string
+	stdin goio.Reader
+}
+
+// NewReader returns an error if the key list file does not exist. The keys are read from stdin if the path is "-".
+func NewReader(path string, stdin goio.Reader) (*Reader, error) {
+	if path != StdinPath {
+		if _, err := os.Stat(path); err != nil {
+			return nil, fmt.Errorf("KeyListError: %w", err)
+		}
+	}
+	return &Reader{
+		path:  path,
+		stdin: stdin,
+	}, nil
+}
+
+// ForEachTargets calls fn with the targets in the key list in order, up to 1000 targets at a time.
+// The entries for other buckets are skipped if the key list has the bucket names (e.g. a manifest file).
+func (r *Reader) ForEachTargets(bucket string, fn func(targets []wrapper.Target) error) error {
+	var reader goio.Reader = r.stdin
+	if r.path != StdinPath {
+		file, err := os.Open(r.path)
+		if err != nil {
+			return fmt.Errorf("KeyListError: %w", err)
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	bufReader := bufio.NewReader(reader)
+	format := FormatOf(r.path)
+	if r.path == StdinPath {
+		format = sniffFormat(bufReader)
+	}
+
+	targets := make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+	add := func(e entry) error {
+		if e.Bucket != "" && e.Bucket != bucket {
+			return nil
+		}
+		targets = append(targets, e.Target)
+		if len(targets) < client.MaxDeleteObjectsCount {
+			return nil
+		}
+		if err := fn(targets); err != nil {
+			return err
+		}
+		targets = make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+		return nil
+	}
+
+	var err error
+	if format == FormatCSV {
+		err = readCSV(bufReader, add)
+	} else {
+		err = readLines(bufReader, format, add)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(targets) == 0 {
+		return nil
+	}
+	return fn(targets)
+}
+
+// sniffFormat returns JSONL if the first line from stdin is a JSON object, otherwise the text format.
+// CSV is not detected because a line of a key cannot be distinguished from a CSV header.
+func sniffFormat(reader *bufio.Reader) Format {
+	peeked, _ := reader.Peek(1)
+	if bytes.Equal(peeked, []byte("{")) {
+		return FormatJSONL
+	}
+	return FormatText
+}
+
+func readLines(reader goio.Reader, format Format, add func(e entry) error) error {
+	scanner := bufio.NewScanner(reader)
+	lineNumber := 0
+	for scanner.Scan() {
+		lineNumber++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		e := entry{}
+		if format == FormatJSONL {
+			if err := json.Unmarshal([]byte(line), &e); err != nil {
+				return fmt.Errorf("KeyListError: invalid entry at line %d: %w", lineNumber, err)
+			}
+		} else {
+			// NOTE: Keys can have tabs, but version ids cannot. So the last tab separates the version id.
+			e.Key = line
+			if i := strings.LastIndex(line, "\t"); i >= 0 {
+				e.Key, e.VersionId = line[:i], line[i+1:]
+			}
+		}
+		if e.Key == "" {
+			return fmt.Errorf("KeyListError: no key at line %d", lineNumber)
+		}
+
+		if err := add(e); err != nil {
+			return err
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("KeyListError: %w", err)
+	}
+	return nil
+}
+
+// readCSV reads a CSV with a header that has the `key` column, and optionally the `versionId` and `bucket` columns.
+func readCSV(reader goio.Reader, add func(e entry) error) error {
+	csvReader := csv.NewReader(reader)
+	csvReader.FieldsPerRecord = -1
+
+	header, err := csvReader.Read()
+	if errors.Is(err, goio.EOF) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("KeyListError: %w", err)
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		// NOTE: Accept the column names like `version_id` and `VersionId` from queries and other tools.
+		columns[strings.ReplaceAll(strings.ToLower(strings.TrimSpace(name)), "_", "")] = i
+	}
+	keyColumn, ok := columns["key"]
+	if !ok {
+		return fmt.Errorf("KeyListError: the CSV header has no key column: %v", strings.Join(header, ","))
+	}
+	field := func(record []string, name string) string {
+		if i, ok := columns[name]; ok && i < len(record) {
+			return record[i]
+		}
+		return ""
+	}
+
+	for {
+		record, err := csvReader.Read()
+		if errors.Is(err, goio.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("KeyListError: %w", err)
+		}
+		if keyColumn >= len(record) || record[keyColumn] == "" {
+			line, _ := csvReader.FieldPos(0)
+			return fmt.Errorf("KeyListError: no key at line %d", line)
+		}
+
+		e := entry{
+			Bucket: field(record, "bucket"),
+			Target: wrapper.Target{
+				Key:       record[keyColumn],
+				VersionId: field(record, "versionid"),
+			},
+		}
+		if err := add(e); err != nil {
+			return err
+		}
+	}
+}
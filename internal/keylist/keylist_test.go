@@ -0,0 +1,238 @@
+package keylist
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{path: "keys.txt", want: FormatText},
+		{path: "keys", want: FormatText},
+		{path: "keys.jsonl", want: FormatJSONL},
+		{path: "keys.NDJSON", want: FormatJSONL},
+		{path: "keys.json", want: FormatJSONL},
+		{path: "keys.CSV", want: FormatCSV},
+		{path: StdinPath, want: FormatText},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatOf(tt.path))
+		})
+	}
+}
+
+func TestReader_ForEachTargets(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string // read from stdin if empty
+		content string
+		bucket  string
+		want    []wrapper.Target
+		wantErr string
+	}{
+		{
+			name:    "read keys and version ids separated by tabs",
+			file:    "keys.txt",
+			content: "key1\nkey2\tversion2\r\n\nkey\twith\ttabs\tversion3\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1"},
+				{Key: "key2", VersionId: "version2"},
+				{Key: "key\twith\ttabs", VersionId: "version3"},
+			},
+		},
+		{
+			name:    "read keys with spaces as they are",
+			file:    "keys.txt",
+			content: " key with spaces \n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: " key with spaces "},
+			},
+		},
+		{
+			name:    "read JSON Lines skipping the entries for other buckets",
+			file:    "keys.jsonl",
+			content: `{"key":"key1"}` + "\n" + `{"bucket":"bucket","key":"key2","versionId":"version2","deleteMarker":true,"deletedAt":"2025-01-01T00:00:00Z"}` + "\n" + `{"bucket":"other","key":"key3"}` + "\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1"},
+				{Key: "key2", VersionId: "version2", DeleteMarker: true},
+			},
+		},
+		{
+			name:    "read CSV with a header in any order and case",
+			file:    "keys.csv",
+			content: "version_id,Key,size\nversion1,key1,10\n,\"key,2\",20\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1", VersionId: "version1"},
+				{Key: "key,2"},
+			},
+		},
+		{
+			name:    "read CSV skipping the entries for other buckets",
+			file:    "keys.csv",
+			content: "bucket,key,versionId,deleteMarker,namespace,table,index,deletedAt\nbucket,key1,version1,false,,,,2025-01-01T00:00:00Z\nother,key2,version2,false,,,,2025-01-01T00:00:00Z\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1", VersionId: "version1"},
+			},
+		},
+		{
+			name:    "read nothing from an empty CSV",
+			file:    "keys.csv",
+			content: "",
+			bucket:  "bucket",
+			want:    []wrapper.Target{},
+		},
+		{
+			name:    "read keys from stdin",
+			content: "key1\nkey2\tversion2\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1"},
+				{Key: "key2", VersionId: "version2"},
+			},
+		},
+		{
+			name:    "read JSON Lines from stdin",
+			content: `{"key":"key1","versionId":"version1"}` + "\n",
+			bucket:  "bucket",
+			want: []wrapper.Target{
+				{Key: "key1", VersionId: "version1"},
+			},
+		},
+		{
+			name:    "error for an invalid JSON line",
+			file:    "keys.jsonl",
+			content: `{"key":"key1"}` + "\nnot json\n",
+			bucket:  "bucket",
+			want:    []wrapper.Target{},
+			wantErr: "KeyListError: invalid entry at line 2: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name:    "error for a line without a key",
+			file:    "keys.txt",
+			content: "key1\n\tversion2\n",
+			bucket:  "bucket",
+			want:    []wrapper.Target{},
+			wantErr: "KeyListError: no key at line 2",
+		},
+		{
+			name:    "error for CSV without a key column",
+			file:    "keys.csv",
+			content: "name,versionId\nkey1,version1\n",
+			bucket:  "bucket",
+			want:    []wrapper.Target{},
+			wantErr: "KeyListError: the CSV header has no key column: name,versionId",
+		},
+		{
+			name:    "error for a CSV record without a key",
+			file:    "keys.csv",
+			content: "key,versionId\nkey1,version1\n,version2\n",
+			bucket:  "bucket",
+			want:    []wrapper.Target{},
+			wantErr: "KeyListError: no key at line 3",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := StdinPath
+			if tt.file != "" {
+				path = filepath.Join(t.TempDir(), tt.file)
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+
+			reader, err := NewReader(path, strings.NewReader(tt.content))
+			require.NoError(t, err)
+
+			got := []wrapper.Target{}
+			err = reader.ForEachTargets(tt.bucket, func(targets []wrapper.Target) error {
+				got = append(got, targets...)
+				return nil
+			})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestReader_ForEachTargets_Batches(t *testing.T) {
+	lines := make([]string, 0, 2001)
+	for i := 0; i < 2001; i++ {
+		lines = append(lines, fmt.Sprintf("key%d", i))
+	}
+	content := strings.Join(lines, "\n")
+
+	tests := []struct {
+		name         string
+		fnErr        error
+		wantErr      string
+		wantBatchLen []int
+	}{
+		{
+			name:         "call fn with up to 1000 targets at a time",
+			wantBatchLen: []int{1000, 1000, 1},
+		},
+		{
+			name:         "return an error from fn as is",
+			fnErr:        fmt.Errorf("DeleteError"),
+			wantErr:      "DeleteError",
+			wantBatchLen: []int{1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reader, err := NewReader(StdinPath, strings.NewReader(content))
+			require.NoError(t, err)
+
+			batchLen := []int{}
+			err = reader.ForEachTargets("bucket", func(targets []wrapper.Target) error {
+				batchLen = append(batchLen, len(targets))
+				return tt.fnErr
+			})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantBatchLen, batchLen)
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "keys.txt")
+
+	_, err := NewReader(path, nil)
+	assert.ErrorContains(t, err, "KeyListError: ")
+
+	require.NoError(t, os.WriteFile(path, []byte("key\n"), 0o600))
+	_, err = NewReader(path, nil)
+	assert.NoError(t, err)
+}
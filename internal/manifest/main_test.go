@@ -0,0 +1,16 @@
+package manifest
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	fmt.Println()
+	fmt.Println("==========================================")
+	fmt.Println("========== Start Test: manifest ==========")
+	fmt.Println("==========================================")
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,233 @@
+package manifest
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+)
+
+type Format string
+
+const (
+	FormatJSONL Format = "jsonl"
+	FormatCSV   Format = "csv"
+)
+
+var (
+	deletedHeader = []string{"bucket", "key", "versionId", "deleteMarker", "namespace", "table", "index", "deletedAt"}
+	failedHeader  = []string{"bucket", "key", "versionId", "code", "message", "failedAt"}
+)
+
+// deletedEntry is each line of a manifest file in the JSONL format.
+type deletedEntry struct {
+	Bucket string `json:"bucket"`
+	wrapper.Target
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// failedEntry is each line of a failures file in the JSONL format.
+type failedEntry struct {
+	Bucket string `json:"bucket"`
+	wrapper.FailedTarget
+	FailedAt time.Time `json:"failedAt"`
+}
+
+// FormatOf returns the format of a manifest file by its extension: CSV for .csv, otherwise JSONL.
+func FormatOf(path string) Format {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return FormatCSV
+	}
+	return FormatJSONL
+}
+
+// FailuresPath returns the path of the failures file for a manifest file,
+// e.g. deleted.failures.jsonl for deleted.jsonl.
+func FailuresPath(path string) string {
+	ext := filepath.Ext(path)
+	return strings.TrimSuffix(path, ext) + ".failures" + ext
+}
+
+var _ wrapper.IDeletionRecorder = (*Writer)(nil)
+
+// Writer writes the deleted targets to a manifest file, and the ones that could not be deleted
+// to a failures file. It is safe for concurrent use.
+type Writer struct {
+	format  Format
+	deleted *file
+	failed  *file
+	mtx     sync.Mutex
+}
+
+type file struct {
+	file   *os.File
+	writer *bufio.Writer
+	csv    *csv.Writer
+}
+
+// NewWriter opens a manifest file and its failures file, creating them if they do not exist
+func NewWriter(path string) (*Writer, error) {
+	w := &Writer{
+		format: FormatOf(path),
+	}
+
+	deleted, err := w.open(path, deletedHeader)
+	if err != nil {
+		return nil, err
+	}
+	failed, err := w.open(FailuresPath(path), failedHeader)
+	if err != nil {
+		deleted.file.Close()
+		return nil, err
+	}
+	w.deleted = deleted
+	w.failed = failed
+
+	return w, nil
+}
+
+func (w *Writer) open(path string, header []string) (*file, error) {
+	// NOTE: The records are appended to an existing file so that the records of a stopped run are
+	// not lost when it is resumed.
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, fmt.Errorf("ManifestFileError: %w", err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("ManifestFileError: %w", err)
+	}
+
+	writer := bufio.NewWriter(f)
+	opened := &file{
+		file:   f,
+		writer: writer,
+	}
+	if w.format == FormatCSV {
+		opened.csv = csv.NewWriter(writer)
+	}
+	if opened.csv != nil && info.Size() == 0 {
+		if err := opened.csv.Write(header); err != nil {
+			f.Close()
+			return nil, fmt.Errorf("ManifestFileError: %w", err)
+		}
+	}
+
+	if err := opened.flush(); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return opened, nil
+}
+
+func (w *Writer) RecordDeleted(bucket string, targets []wrapper.Target) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	deletedAt := time.Now().UTC()
+	for _, target := range targets {
+		var err error
+		if w.format == FormatCSV {
+			err = w.deleted.csv.Write([]string{
+				bucket,
+				target.Key,
+				target.VersionId,
+				strconv.FormatBool(target.DeleteMarker),
+				target.Namespace,
+				target.Table,
+				target.Index,
+				deletedAt.Format(time.RFC3339Nano),
+			})
+		} else {
+			err = json.NewEncoder(w.deleted.writer).Encode(deletedEntry{Bucket: bucket, Target: target, DeletedAt: deletedAt})
+		}
+		if err != nil {
+			return fmt.Errorf("ManifestFileError: %w", err)
+		}
+	}
+
+	// NOTE: The records are flushed for each call so that the manifest has all the deleted targets
+	// even if the run is stopped.
+	return w.deleted.flush()
+}
+
+func (w *Writer) RecordFailed(bucket string, targets []wrapper.FailedTarget) error {
+	if len(targets) == 0 {
+		return nil
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	failedAt := time.Now().UTC()
+	for _, target := range targets {
+		var err error
+		if w.format == FormatCSV {
+			err = w.failed.csv.Write([]string{
+				bucket,
+				target.Key,
+				target.VersionId,
+				target.Code,
+				target.Message,
+				failedAt.Format(time.RFC3339Nano),
+			})
+		} else {
+			err = json.NewEncoder(w.failed.writer).Encode(failedEntry{Bucket: bucket, FailedTarget: target, FailedAt: failedAt})
+		}
+		if err != nil {
+			return fmt.Errorf("ManifestFileError: %w", err)
+		}
+	}
+
+	return w.failed.flush()
+}
+
+// Close closes the manifest file and the failures file
+func (w *Writer) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	deletedErr := w.deleted.close()
+	failedErr := w.failed.close()
+	if deletedErr != nil {
+		return deletedErr
+	}
+	return failedErr
+}
+
+func (f *file) flush() error {
+	if f.csv != nil {
+		f.csv.Flush()
+		if err := f.csv.Error(); err != nil {
+			return fmt.Errorf("ManifestFileError: %w", err)
+		}
+	}
+	if err := f.writer.Flush(); err != nil {
+		return fmt.Errorf("ManifestFileError: %w", err)
+	}
+	return nil
+}
+
+func (f *file) close() error {
+	if err := f.flush(); err != nil {
+		f.file.Close()
+		return err
+	}
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("ManifestFileError: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package manifest
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		path string
+		want Format
+	}{
+		{path: "deleted.csv", want: FormatCSV},
+		{path: "deleted.CSV", want: FormatCSV},
+		{path: "deleted.jsonl", want: FormatJSONL},
+		{path: "deleted", want: FormatJSONL},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FormatOf(tt.path))
+		})
+	}
+}
+
+func TestFailuresPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "deleted.jsonl", want: "deleted.failures.jsonl"},
+		{path: "audit/deleted.csv", want: "audit/deleted.failures.csv"},
+		{path: "deleted", want: "deleted.failures"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, FailuresPath(tt.path))
+		})
+	}
+}
+
+func TestWriter_JSONL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.jsonl")
+
+	writer, err := NewWriter(path)
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for _, bucket := range []string{"bucket1", "bucket2"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, writer.RecordDeleted(bucket, []wrapper.Target{
+				{Key: "key1", VersionId: "version1"},
+				{Key: "key1", VersionId: "version2", DeleteMarker: true},
+			}))
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, writer.RecordFailed("bucket1", []wrapper.FailedTarget{
+		{Target: wrapper.Target{Key: "key2", VersionId: "version3"}, Code: "AccessDenied", Message: "Access Denied"},
+	}))
+	require.NoError(t, writer.Close())
+
+	deleted := readJSONL[deletedEntry](t, path)
+	for i := range deleted {
+		assert.False(t, deleted[i].DeletedAt.IsZero())
+		deleted[i].DeletedAt = time.Time{}
+	}
+	assert.ElementsMatch(t, []deletedEntry{
+		{Bucket: "bucket1", Target: wrapper.Target{Key: "key1", VersionId: "version1"}},
+		{Bucket: "bucket1", Target: wrapper.Target{Key: "key1", VersionId: "version2", DeleteMarker: true}},
+		{Bucket: "bucket2", Target: wrapper.Target{Key: "key1", VersionId: "version1"}},
+		{Bucket: "bucket2", Target: wrapper.Target{Key: "key1", VersionId: "version2", DeleteMarker: true}},
+	}, deleted)
+
+	failed := readJSONL[failedEntry](t, FailuresPath(path))
+	require.Len(t, failed, 1)
+	assert.Equal(t, "bucket1", failed[0].Bucket)
+	assert.Equal(t, wrapper.FailedTarget{
+		Target:  wrapper.Target{Key: "key2", VersionId: "version3"},
+		Code:    "AccessDenied",
+		Message: "Access Denied",
+	}, failed[0].FailedTarget)
+}
+
+func TestWriter_CSV(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.csv")
+
+	writer, err := NewWriter(path)
+	require.NoError(t, err)
+
+	require.NoError(t, writer.RecordDeleted("arn:aws:s3tables:us-east-1:123456789012:bucket/test", []wrapper.Target{
+		{Namespace: "namespace1", Table: "table1"},
+		{Namespace: "namespace1"},
+	}))
+	require.NoError(t, writer.RecordDeleted("bucket1", []wrapper.Target{
+		{Key: "key,with,commas", VersionId: "version1", DeleteMarker: true},
+	}))
+	require.NoError(t, writer.RecordDeleted("bucket1", []wrapper.Target{}))
+	require.NoError(t, writer.RecordFailed("bucket1", []wrapper.FailedTarget{
+		{Target: wrapper.Target{Key: "key2"}, Code: "InternalError", Message: "We encountered an internal error."},
+	}))
+	require.NoError(t, writer.Close())
+
+	deleted := readCSV(t, path)
+	require.Len(t, deleted, 4)
+	assert.Equal(t, deletedHeader, deleted[0])
+	assert.Equal(t, []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test", "", "", "false", "namespace1", "table1", ""}, deleted[1][:7])
+	assert.Equal(t, []string{"arn:aws:s3tables:us-east-1:123456789012:bucket/test", "", "", "false", "namespace1", "", ""}, deleted[2][:7])
+	assert.Equal(t, []string{"bucket1", "key,with,commas", "version1", "true", "", "", ""}, deleted[3][:7])
+	_, err = time.Parse(time.RFC3339Nano, deleted[3][7])
+	assert.NoError(t, err)
+
+	failed := readCSV(t, FailuresPath(path))
+	require.Len(t, failed, 2)
+	assert.Equal(t, failedHeader, failed[0])
+	assert.Equal(t, []string{"bucket1", "key2", "", "InternalError", "We encountered an internal error."}, failed[1][:5])
+}
+
+func TestWriter_empty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.jsonl")
+
+	writer, err := NewWriter(path)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	// both files are created even if nothing is deleted
+	for _, p := range []string{path, FailuresPath(path)} {
+		info, statErr := os.Stat(p)
+		require.NoError(t, statErr)
+		assert.Zero(t, info.Size())
+	}
+
+	_, err = NewWriter(filepath.Join(t.TempDir(), "not-exist", "deleted.jsonl"))
+	assert.Error(t, err)
+}
+
+func readJSONL[T any](t *testing.T, path string) []T {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+
+	entries := []T{}
+	for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+		var entry T
+		require.NoError(t, json.Unmarshal([]byte(line), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func readCSV(t *testing.T, path string) [][]string {
+	t.Helper()
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	records, err := csv.NewReader(file).ReadAll()
+	require.NoError(t, err)
+	return records
+}
+
+func TestWriter_append(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deleted.csv")
+
+	for _, key := range []string{"key1", "key2"} {
+		writer, err := NewWriter(path)
+		require.NoError(t, err)
+		require.NoError(t, writer.RecordDeleted("bucket1", []wrapper.Target{{Key: key}}))
+		require.NoError(t, writer.Close())
+	}
+
+	// the header is written only once
+	deleted := readCSV(t, path)
+	require.Len(t, deleted, 3)
+	assert.Equal(t, deletedHeader, deleted[0])
+	assert.Equal(t, "key1", deleted[1][1])
+	assert.Equal(t, "key2", deleted[2][1])
+}
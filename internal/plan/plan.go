@@ -0,0 +1,288 @@
+package plan
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// Version is the format version of plan files written by this cls3.
+const Version = 1
+
+// Plan lines other than the header are short, but the header has all bucket names in one line.
+const maxLineSize = 64 * 1024 * 1024
+
+type Mode string
+
+const (
+	ModeGeneral   Mode = "general"
+	ModeDirectory Mode = "directory"
+	ModeTable     Mode = "table"
+	ModeVector    Mode = "vector"
+)
+
+// Header is the first line of a plan file.
+type Header struct {
+	Version         int       `json:"version"`
+	CreatedAt       time.Time `json:"createdAt"`
+	AccountId       string    `json:"accountId"`
+	Region          string    `json:"region"`
+	EndpointUrl     string    `json:"endpointUrl"`
+	Mode            Mode      `json:"mode"`
+	ForceMode       bool      `json:"forceMode"`
+	OldVersionsOnly bool      `json:"oldVersionsOnly"`
+	KeyPrefixes     []string  `json:"keyPrefixes,omitempty"`
+	Buckets         []string  `json:"buckets"` // bucket names for S3 and S3Vectors, bucket arns for S3Tables
+}
+
+// entry is each line after the header in a plan file.
+type entry struct {
+	Bucket string `json:"bucket"`
+	wrapper.Target
+}
+
+// CheckEnvironment returns an error if the plan was created for another account, region or endpoint.
+func (h *Header) CheckEnvironment(environment *wrapper.TargetEnvironment) error {
+	mismatches := []string{}
+	if h.AccountId != environment.AccountId {
+		mismatches = append(mismatches, fmt.Sprintf("account: %q in the plan, but %q now", h.AccountId, environment.AccountId))
+	}
+	if h.Region != environment.Region {
+		mismatches = append(mismatches, fmt.Sprintf("region: %q in the plan, but %q now", h.Region, environment.Region))
+	}
+	if h.EndpointUrl != environment.EndpointUrl {
+		mismatches = append(mismatches, fmt.Sprintf("endpoint: %q in the plan, but %q now", h.EndpointUrl, environment.EndpointUrl))
+	}
+
+	if len(mismatches) > 0 {
+		return fmt.Errorf("PlanMismatchError: the plan was created for a different environment. %v", strings.Join(mismatches, ", "))
+	}
+	return nil
+}
+
+var _ wrapper.ITargetRecorder = (*Writer)(nil)
+
+// Writer writes a plan file. It is safe for concurrent use.
+type Writer struct {
+	file    *os.File
+	writer  *bufio.Writer
+	encoder *json.Encoder
+	mtx     sync.Mutex
+}
+
+// NewWriter creates a plan file and writes the header to it
+func NewWriter(path string, header Header) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("PlanFileError: %w", err)
+	}
+
+	writer := bufio.NewWriter(file)
+	w := &Writer{
+		file:    file,
+		writer:  writer,
+		encoder: json.NewEncoder(writer),
+	}
+
+	header.Version = Version
+	if err := w.encoder.Encode(header); err != nil {
+		file.Close()
+		return nil, fmt.Errorf("PlanFileError: %w", err)
+	}
+
+	return w, nil
+}
+
+func (w *Writer) RecordTargets(bucket string, targets []wrapper.Target) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	for _, target := range targets {
+		if err := w.encoder.Encode(entry{Bucket: bucket, Target: target}); err != nil {
+			return fmt.Errorf("PlanFileError: %w", err)
+		}
+	}
+	return nil
+}
+
+// Close flushes the buffered entries and closes the plan file
+func (w *Writer) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+
+	if err := w.writer.Flush(); err != nil {
+		w.file.Close()
+		return fmt.Errorf("PlanFileError: %w", err)
+	}
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("PlanFileError: %w", err)
+	}
+	return nil
+}
+
+var _ wrapper.ITargetSource = (*Reader)(nil)
+
+// Reader reads the targets from a plan file. It is safe for concurrent use.
+type Reader struct {
+	path      string
+	header    *Header
+	segments  map[string][]segment // the lines of the targets of each bucket, indexed on the first read
+	indexOnce sync.Once
+	indexErr  error
+}
+
+// segment is a range of contiguous lines of the targets of a bucket in a plan file.
+// The targets of a bucket are written in batches, so the lines of buckets cleared in parallel are interleaved.
+type segment struct {
+	offset int64
+	size   int64
+}
+
+// NewReader reads and validates the header of a plan file
+func NewReader(path string) (*Reader, error) {
+	r := &Reader{
+		path: path,
+	}
+
+	err := r.scan(func(file *os.File) error {
+		scanner := newScanner(file)
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("PlanFileError: %w", err)
+			}
+			return fmt.Errorf("PlanFileError: the plan file %v is empty", r.path)
+		}
+		header := &Header{}
+		if err := json.Unmarshal(scanner.Bytes(), header); err != nil {
+			return fmt.Errorf("PlanFileError: invalid header: %w", err)
+		}
+		r.header = header
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if r.header.Version != Version {
+		return nil, fmt.Errorf("PlanFileError: unsupported plan version %v, only version %v is supported", r.header.Version, Version)
+	}
+	switch r.header.Mode {
+	case ModeGeneral, ModeDirectory, ModeTable, ModeVector:
+	default:
+		return nil, fmt.Errorf("PlanFileError: unknown mode %q in the plan", r.header.Mode)
+	}
+
+	return r, nil
+}
+
+func (r *Reader) Header() *Header {
+	return r.header
+}
+
+// ForEachTargets calls fn with the targets of a bucket in order, up to 1000 targets at a time.
+// NOTE: Only the lines of the bucket are read with the index of the plan file, so that the whole plan
+// is neither read again for each bucket nor held in memory.
+func (r *Reader) ForEachTargets(bucket string, fn func(targets []wrapper.Target) error) error {
+	if err := r.index(); err != nil {
+		return err
+	}
+
+	return r.scan(func(file *os.File) error {
+		targets := make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+		for _, seg := range r.segments[bucket] {
+			scanner := newScanner(io.NewSectionReader(file, seg.offset, seg.size))
+			for scanner.Scan() {
+				e := entry{}
+				if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+					return fmt.Errorf("PlanFileError: invalid entry: %w", err)
+				}
+
+				targets = append(targets, e.Target)
+				if len(targets) == client.MaxDeleteObjectsCount {
+					if err := fn(targets); err != nil {
+						return err
+					}
+					targets = make([]wrapper.Target, 0, client.MaxDeleteObjectsCount)
+				}
+			}
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("PlanFileError: %w", err)
+			}
+		}
+
+		if len(targets) == 0 {
+			return nil
+		}
+		return fn(targets)
+	})
+}
+
+// index reads the plan file once and records the segments of the lines of each bucket
+func (r *Reader) index() error {
+	r.indexOnce.Do(func() {
+		segments := map[string][]segment{}
+		r.indexErr = r.scan(func(file *os.File) error {
+			var offset, lineOffset int64
+			scanner := newScanner(file)
+			scanner.Split(func(data []byte, atEOF bool) (int, []byte, error) {
+				advance, token, err := bufio.ScanLines(data, atEOF)
+				lineOffset = offset
+				offset += int64(advance)
+				return advance, token, err
+			})
+
+			// skip the header
+			scanner.Scan()
+
+			lastBucket := ""
+			for scanner.Scan() {
+				e := struct {
+					Bucket string `json:"bucket"`
+				}{}
+				if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+					return fmt.Errorf("PlanFileError: invalid entry: %w", err)
+				}
+
+				bucketSegments := segments[e.Bucket]
+				if len(bucketSegments) > 0 && e.Bucket == lastBucket {
+					last := &bucketSegments[len(bucketSegments)-1]
+					last.size = offset - last.offset
+				} else {
+					bucketSegments = append(bucketSegments, segment{offset: lineOffset, size: offset - lineOffset})
+				}
+				segments[e.Bucket] = bucketSegments
+				lastBucket = e.Bucket
+			}
+			if err := scanner.Err(); err != nil {
+				return fmt.Errorf("PlanFileError: %w", err)
+			}
+			return nil
+		})
+		r.segments = segments
+	})
+	return r.indexErr
+}
+
+func (r *Reader) scan(fn func(file *os.File) error) error {
+	file, err := os.Open(r.path)
+	if err != nil {
+		return fmt.Errorf("PlanFileError: %w", err)
+	}
+	defer file.Close()
+
+	return fn(file)
+}
+
+func newScanner(reader io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	return scanner
+}
@@ -0,0 +1,278 @@
+package plan
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestWriterAndReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.jsonl")
+	header := Header{
+		CreatedAt:   time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		AccountId:   "123456789012",
+		Region:      "us-east-1",
+		EndpointUrl: "",
+		Mode:        ModeGeneral,
+		ForceMode:   true,
+		Buckets:     []string{"bucket1", "bucket2"},
+	}
+
+	writer, err := NewWriter(path, header)
+	require.NoError(t, err)
+
+	wg := sync.WaitGroup{}
+	for _, bucket := range header.Buckets {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 3; i++ {
+				assert.NoError(t, writer.RecordTargets(bucket, []wrapper.Target{
+					{Key: fmt.Sprintf("key%d", i), VersionId: fmt.Sprintf("version%d", i)},
+				}))
+			}
+		}()
+	}
+	wg.Wait()
+	require.NoError(t, writer.Close())
+
+	reader, err := NewReader(path)
+	require.NoError(t, err)
+
+	header.Version = Version
+	assert.Equal(t, &header, reader.Header())
+
+	got := []wrapper.Target{}
+	err = reader.ForEachTargets("bucket2", func(targets []wrapper.Target) error {
+		got = append(got, targets...)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []wrapper.Target{
+		{Key: "key0", VersionId: "version0"},
+		{Key: "key1", VersionId: "version1"},
+		{Key: "key2", VersionId: "version2"},
+	}, got)
+}
+
+func TestReader_ForEachTargets(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.jsonl")
+	writer, err := NewWriter(path, Header{Mode: ModeGeneral, Buckets: []string{"bucket"}})
+	require.NoError(t, err)
+
+	targets := make([]wrapper.Target, 0, 2001)
+	for i := 0; i < 2001; i++ {
+		targets = append(targets, wrapper.Target{Key: fmt.Sprintf("key%d", i)})
+	}
+	require.NoError(t, writer.RecordTargets("bucket", targets))
+	require.NoError(t, writer.Close())
+
+	reader, err := NewReader(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name         string
+		bucket       string
+		fnErr        error
+		wantErr      string
+		wantBatchLen []int
+	}{
+		{
+			name:         "call fn with up to 1000 targets at a time",
+			bucket:       "bucket",
+			wantBatchLen: []int{1000, 1000, 1},
+		},
+		{
+			name:         "not call fn for a bucket without targets",
+			bucket:       "other",
+			wantBatchLen: []int{},
+		},
+		{
+			name:         "return an error from fn as is",
+			bucket:       "bucket",
+			fnErr:        fmt.Errorf("DeleteError"),
+			wantErr:      "DeleteError",
+			wantBatchLen: []int{1000},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			batchLen := []int{}
+			err := reader.ForEachTargets(tt.bucket, func(targets []wrapper.Target) error {
+				batchLen = append(batchLen, len(targets))
+				return tt.fnErr
+			})
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, tt.wantBatchLen, batchLen)
+		})
+	}
+}
+
+func TestReader_ForEachTargets_Interleaved(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "plan.jsonl")
+	writer, err := NewWriter(path, Header{Mode: ModeGeneral, Buckets: []string{"bucket1", "bucket2"}})
+	require.NoError(t, err)
+
+	keys := func(prefix string, from, to int) []wrapper.Target {
+		targets := []wrapper.Target{}
+		for i := from; i < to; i++ {
+			targets = append(targets, wrapper.Target{Key: fmt.Sprintf("%s%d", prefix, i)})
+		}
+		return targets
+	}
+	// the batches of the buckets cleared in parallel are interleaved in the plan
+	require.NoError(t, writer.RecordTargets("bucket1", keys("a", 0, 500)))
+	require.NoError(t, writer.RecordTargets("bucket2", keys("b", 0, 300)))
+	require.NoError(t, writer.RecordTargets("bucket1", keys("a", 500, 1200)))
+	require.NoError(t, writer.RecordTargets("bucket2", keys("b", 300, 400)))
+	require.NoError(t, writer.Close())
+
+	reader, err := NewReader(path)
+	require.NoError(t, err)
+
+	tests := []struct {
+		bucket       string
+		wantTargets  []wrapper.Target
+		wantBatchLen []int
+	}{
+		{bucket: "bucket1", wantTargets: keys("a", 0, 1200), wantBatchLen: []int{1000, 200}},
+		{bucket: "bucket2", wantTargets: keys("b", 0, 400), wantBatchLen: []int{400}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.bucket, func(t *testing.T) {
+			got := []wrapper.Target{}
+			batchLen := []int{}
+			err := reader.ForEachTargets(tt.bucket, func(targets []wrapper.Target) error {
+				got = append(got, targets...)
+				batchLen = append(batchLen, len(targets))
+				return nil
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantTargets, got)
+			assert.Equal(t, tt.wantBatchLen, batchLen)
+		})
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "read a valid header",
+			content: `{"version":1,"mode":"table","buckets":["arn:aws:s3tables:us-east-1:123456789012:bucket/test"]}` + "\n",
+			wantErr: "",
+		},
+		{
+			name:    "error for an empty plan file",
+			content: "",
+			wantErr: "PlanFileError: the plan file <path> is empty",
+		},
+		{
+			name:    "error for an invalid header",
+			content: "not json\n",
+			wantErr: "PlanFileError: invalid header: invalid character 'o' in literal null (expecting 'u')",
+		},
+		{
+			name:    "error for an unsupported version",
+			content: `{"version":2,"mode":"general"}` + "\n",
+			wantErr: "PlanFileError: unsupported plan version 2, only version 1 is supported",
+		},
+		{
+			name:    "error for an unknown mode",
+			content: `{"version":1,"mode":"unknown"}` + "\n",
+			wantErr: `PlanFileError: unknown mode "unknown" in the plan`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "plan.jsonl")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+
+			reader, err := NewReader(path)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, strings.ReplaceAll(tt.wantErr, "<path>", path))
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, ModeTable, reader.Header().Mode)
+		})
+	}
+}
+
+func TestHeader_CheckEnvironment(t *testing.T) {
+	header := &Header{
+		AccountId:   "123456789012",
+		Region:      "us-east-1",
+		EndpointUrl: "",
+	}
+
+	tests := []struct {
+		name        string
+		environment *wrapper.TargetEnvironment
+		wantErr     string
+	}{
+		{
+			name: "same environment",
+			environment: &wrapper.TargetEnvironment{
+				AccountId:   "123456789012",
+				Region:      "us-east-1",
+				EndpointUrl: "",
+			},
+			wantErr: "",
+		},
+		{
+			name: "different account",
+			environment: &wrapper.TargetEnvironment{
+				AccountId:   "210987654321",
+				Region:      "us-east-1",
+				EndpointUrl: "",
+			},
+			wantErr: `PlanMismatchError: the plan was created for a different environment. account: "123456789012" in the plan, but "210987654321" now`,
+		},
+		{
+			name: "different region and endpoint",
+			environment: &wrapper.TargetEnvironment{
+				AccountId:   "123456789012",
+				Region:      "ap-northeast-1",
+				EndpointUrl: "https://s3.ap-northeast-1.amazonaws.com",
+			},
+			wantErr: `PlanMismatchError: the plan was created for a different environment. region: "us-east-1" in the plan, but "ap-northeast-1" now, endpoint: "" in the plan, but "https://s3.ap-northeast-1.amazonaws.com" now`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := header.CheckEnvironment(tt.environment)
+
+			if tt.wantErr != "" {
+				assert.EqualError(t, err, tt.wantErr)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package protection
+
+import (
+	"fmt"
+	"testing"
+
+	"go.uber.org/goleak"
+)
+
+func TestMain(m *testing.M) {
+	fmt.Println()
+	fmt.Println("==========================================")
+	fmt.Println("========= Start Test: protection =========")
+	fmt.Println("==========================================")
+	goleak.VerifyTestMain(m)
+}
@@ -0,0 +1,66 @@
+package protection
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Config is the content of a protection config file. The buckets matching any of the rules cannot be
+// cleared or deleted by cls3.
+type Config struct {
+	BucketPatterns []string `json:"bucketPatterns,omitempty"` // glob or regular expression with the "regex:" prefix
+	Tags           []string `json:"tags,omitempty"`           // key=value, or key for any value
+	AccountIds     []string `json:"accountIds,omitempty"`
+}
+
+// Merge returns a config with the rules of both configs.
+func (c Config) Merge(other Config) Config {
+	return Config{
+		BucketPatterns: append(append([]string{}, c.BucketPatterns...), other.BucketPatterns...),
+		Tags:           append(append([]string{}, c.Tags...), other.Tags...),
+		AccountIds:     append(append([]string{}, c.AccountIds...), other.AccountIds...),
+	}
+}
+
+// DefaultPath returns the path of the protection config file in the user config directory
+func DefaultPath() (string, error) {
+	configDir, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("ProtectionConfigError: %w", err)
+	}
+	return filepath.Join(configDir, "cls3", "protection.json"), nil
+}
+
+// Load reads a protection config file. Unknown fields are an error so that a misspelled rule
+// does not leave the buckets unprotected.
+func Load(path string) (Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, fmt.Errorf("ProtectionConfigError: %w", err)
+	}
+
+	config := Config{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&config); err != nil {
+		return Config{}, fmt.Errorf("ProtectionConfigError: %v: %w", path, err)
+	}
+	return config, nil
+}
+
+// LoadDefault reads the protection config file at the default path, or returns an empty config if it does not exist.
+// The user config directory may not be determined (e.g. HOME is not set in a container), which also means no file.
+func LoadDefault() (Config, error) {
+	path, err := DefaultPath()
+	if err != nil {
+		return Config{}, nil
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return Config{}, nil
+	}
+	return Load(path)
+}
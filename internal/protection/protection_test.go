@@ -0,0 +1,127 @@
+package protection
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string // the file is not written if empty
+		want    Config
+		wantErr string
+	}{
+		{
+			name: "load all rules",
+			content: `{
+  "bucketPatterns": ["prod-*", "regex:-(prd|production)$"],
+  "tags": ["cls3:protected=true", "aws:cloudformation:stack-name"],
+  "accountIds": ["123456789012"]
+}`,
+			want: Config{
+				BucketPatterns: []string{"prod-*", "regex:-(prd|production)$"},
+				Tags:           []string{"cls3:protected=true", "aws:cloudformation:stack-name"},
+				AccountIds:     []string{"123456789012"},
+			},
+		},
+		{
+			name:    "load an empty config",
+			content: `{}`,
+			want:    Config{},
+		},
+		{
+			name:    "error when a field is misspelled",
+			content: `{"bucketPattern": ["prod-*"]}`,
+			wantErr: `json: unknown field "bucketPattern"`,
+		},
+		{
+			name:    "error when the file is not JSON",
+			content: `bucketPatterns: [prod-*]`,
+			wantErr: "invalid character",
+		},
+		{
+			name:    "error when the file does not exist",
+			wantErr: "no such file or directory",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "protection.json")
+			if tt.content != "" {
+				require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			}
+
+			got, err := Load(path)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), "ProtectionConfigError: ")
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLoadDefault(t *testing.T) {
+	configDir := t.TempDir()
+	t.Setenv("XDG_CONFIG_HOME", configDir)
+	t.Setenv("HOME", configDir)
+
+	path, err := DefaultPath()
+	require.NoError(t, err)
+
+	got, err := LoadDefault()
+	require.NoError(t, err)
+	assert.Equal(t, Config{}, got, "empty config when the default file does not exist")
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o700))
+	require.NoError(t, os.WriteFile(path, []byte(`{"accountIds": ["123456789012"]}`), 0o600))
+
+	got, err = LoadDefault()
+	require.NoError(t, err)
+	assert.Equal(t, Config{AccountIds: []string{"123456789012"}}, got)
+}
+
+func TestLoadDefault_NoUserConfigDir(t *testing.T) {
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("HOME", "")
+	t.Setenv("AppData", "")
+
+	_, err := DefaultPath()
+	require.Error(t, err)
+
+	got, err := LoadDefault()
+	require.NoError(t, err)
+	assert.Equal(t, Config{}, got, "empty config when the user config directory cannot be determined")
+}
+
+func TestConfig_Merge(t *testing.T) {
+	fileConfig := Config{
+		BucketPatterns: []string{"prod-*"},
+		Tags:           []string{"cls3:protected=true"},
+	}
+	flagConfig := Config{
+		BucketPatterns: []string{"*-logs"},
+		AccountIds:     []string{"123456789012"},
+	}
+
+	got := fileConfig.Merge(flagConfig)
+	assert.Equal(t, Config{
+		BucketPatterns: []string{"prod-*", "*-logs"},
+		Tags:           []string{"cls3:protected=true"},
+		AccountIds:     []string{"123456789012"},
+	}, got)
+	assert.Equal(t, []string{"prod-*"}, fileConfig.BucketPatterns, "the original config is not changed")
+}
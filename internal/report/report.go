@@ -0,0 +1,271 @@
+package report
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/smithy-go"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// Version is the format version of the reports written by this cls3.
+const Version = 1
+
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+type Status string
+
+const (
+	StatusSucceeded        Status = "succeeded"
+	StatusFailed           Status = "failed"
+	StatusPartiallyCleared Status = "partiallyCleared" // for a bucket from which some targets were deleted before an error
+	StatusPartiallyFailed  Status = "partiallyFailed"  // for a run in which some buckets failed with --continueOnError
+	StatusInterrupted      Status = "interrupted"      // for a run stopped by SIGINT or SIGTERM
+)
+
+// Report is the machine-readable result of a run.
+type Report struct {
+	Version         int             `json:"version"`
+	Status          Status          `json:"status"`
+	ExitCode        int             `json:"exitCode"`
+	Error           string          `json:"error,omitempty"`
+	DryRun          bool            `json:"dryRun"`
+	StartedAt       time.Time       `json:"startedAt"`
+	FinishedAt      time.Time       `json:"finishedAt"`
+	DurationSeconds float64         `json:"durationSeconds"`
+	Summary         Summary         `json:"summary"`
+	Buckets         []Bucket        `json:"buckets"`
+	SkippedBuckets  []SkippedBucket `json:"skippedBuckets,omitempty"`
+}
+
+// Summary is the totals of all buckets in a run.
+type Summary struct {
+	BucketsCount                 int   `json:"bucketsCount"`
+	SucceededBucketsCount        int   `json:"succeededBucketsCount"`
+	PartiallyClearedBucketsCount int   `json:"partiallyClearedBucketsCount"`
+	FailedBucketsCount           int   `json:"failedBucketsCount"`
+	SkippedBucketsCount          int   `json:"skippedBucketsCount"`
+	DeletedBucketsCount          int   `json:"deletedBucketsCount"`
+	ObjectsCount                 int64 `json:"objectsCount"`
+	VersionsCount                int64 `json:"versionsCount"`
+	DeleteMarkersCount           int64 `json:"deleteMarkersCount"`
+	NamespacesCount              int64 `json:"namespacesCount"`
+	TablesCount                  int64 `json:"tablesCount"`
+	IndexesCount                 int64 `json:"indexesCount"`
+}
+
+// Bucket is the result of clearing a bucket. The numbers are 0 for a failed bucket, and the ones
+// deleted before the error for a partially cleared bucket.
+type Bucket struct {
+	Target string `json:"target"` // bucket name for S3 and S3Vectors, bucket arn for S3Tables
+	Type   string `json:"type"`   // general, directory, table or vector
+	Status Status `json:"status"`
+	wrapper.ClearBucketOutput
+	Errors          []Error   `json:"errors,omitempty"`
+	StartedAt       time.Time `json:"startedAt"`
+	FinishedAt      time.Time `json:"finishedAt"`
+	DurationSeconds float64   `json:"durationSeconds"`
+}
+
+// SkippedBucket is a bucket that was not cleared on purpose, e.g. a bucket not expired yet in the sweep command.
+type SkippedBucket struct {
+	Target string `json:"target"`
+	Reason string `json:"reason"`
+}
+
+// Error is an error in clearing a bucket. Key and VersionId are only for the objects that
+// DeleteObjects failed to delete.
+type Error struct {
+	Code      string `json:"code,omitempty"`
+	Message   string `json:"message"`
+	Key       string `json:"key,omitempty"`
+	VersionId string `json:"versionId,omitempty"`
+}
+
+// Options are the options of a run written to the report.
+type Options struct {
+	BucketType string // general, directory, table or vector
+	Region     string // used for the buckets whose region is not determined by clearing them
+	DryRun     bool
+}
+
+// Reporter collects the results of buckets to make a report. It is safe for concurrent use.
+type Reporter struct {
+	startedAt      time.Time
+	buckets        []Bucket
+	skippedBuckets []SkippedBucket
+	mtx            sync.Mutex
+}
+
+// NewReporter starts a report at the current time
+func NewReporter() *Reporter {
+	return &Reporter{
+		startedAt: time.Now().UTC(),
+		buckets:   []Bucket{},
+	}
+}
+
+// AddBucket adds the result of clearing a bucket. The output is ignored if err is not nil, and the numbers
+// of a partially cleared bucket are taken from the error.
+func (r *Reporter) AddBucket(target string, output *wrapper.ClearBucketOutput, err error, startedAt time.Time, finishedAt time.Time) {
+	bucket := Bucket{
+		Target:          target,
+		Status:          StatusSucceeded,
+		StartedAt:       startedAt.UTC(),
+		FinishedAt:      finishedAt.UTC(),
+		DurationSeconds: finishedAt.Sub(startedAt).Seconds(),
+	}
+	var partialErr *wrapper.PartiallyClearedError
+	switch {
+	case errors.As(err, &partialErr):
+		bucket.Status = StatusPartiallyCleared
+		bucket.Errors = toErrors(err)
+		bucket.ClearBucketOutput = *partialErr.Output
+	case err != nil:
+		bucket.Status = StatusFailed
+		bucket.Errors = toErrors(err)
+	case output != nil:
+		bucket.ClearBucketOutput = *output
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.buckets = append(r.buckets, bucket)
+}
+
+// AddSkippedBucket adds a bucket that is not cleared with the reason
+func (r *Reporter) AddSkippedBucket(target string, reason string) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	r.skippedBuckets = append(r.skippedBuckets, SkippedBucket{
+		Target: target,
+		Reason: reason,
+	})
+}
+
+// Report makes the report of the run with the error of the run (nil if succeeded)
+func (r *Reporter) Report(options Options, err error) *Report {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	finishedAt := time.Now().UTC()
+	report := &Report{
+		Version:         Version,
+		Status:          StatusSucceeded,
+		ExitCode:        0,
+		DryRun:          options.DryRun,
+		StartedAt:       r.startedAt,
+		FinishedAt:      finishedAt,
+		DurationSeconds: finishedAt.Sub(r.startedAt).Seconds(),
+		Buckets:         append([]Bucket{}, r.buckets...),
+		SkippedBuckets:  append([]SkippedBucket(nil), r.skippedBuckets...),
+	}
+	if err != nil {
+		report.Status = StatusFailed
+		report.ExitCode = 1
+		report.Error = err.Error()
+	}
+
+	// NOTE: The buckets are added in the order they are completed, so sort them to make the report stable.
+	sort.SliceStable(report.Buckets, func(i, j int) bool {
+		return report.Buckets[i].Target < report.Buckets[j].Target
+	})
+	sort.SliceStable(report.SkippedBuckets, func(i, j int) bool {
+		return report.SkippedBuckets[i].Target < report.SkippedBuckets[j].Target
+	})
+	report.Summary.SkippedBucketsCount = len(report.SkippedBuckets)
+
+	for i := range report.Buckets {
+		bucket := &report.Buckets[i]
+		bucket.Type = options.BucketType
+		if bucket.Region == "" {
+			bucket.Region = options.Region
+		}
+
+		report.Summary.BucketsCount++
+		switch bucket.Status {
+		case StatusFailed:
+			report.Summary.FailedBucketsCount++
+			continue
+		case StatusPartiallyCleared:
+			report.Summary.PartiallyClearedBucketsCount++
+		default:
+			report.Summary.SucceededBucketsCount++
+		}
+		if bucket.BucketDeleted {
+			report.Summary.DeletedBucketsCount++
+		}
+		report.Summary.ObjectsCount += bucket.ObjectsCount
+		report.Summary.VersionsCount += bucket.VersionsCount
+		report.Summary.DeleteMarkersCount += bucket.DeleteMarkersCount
+		report.Summary.NamespacesCount += bucket.NamespacesCount
+		report.Summary.TablesCount += bucket.TablesCount
+		report.Summary.IndexesCount += bucket.IndexesCount
+	}
+
+	return report
+}
+
+// Write writes the report as indented JSON
+func Write(w io.Writer, report *Report) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(report); err != nil {
+		return fmt.Errorf("ReportError: %w", err)
+	}
+	return nil
+}
+
+// WriteFile writes the report to a file
+func WriteFile(path string, report *Report) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("ReportError: %w", err)
+	}
+	if err := Write(file, report); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("ReportError: %w", err)
+	}
+	return nil
+}
+
+// toErrors returns an error for each object DeleteObjects failed to delete, or the error
+// itself with the error code of the API if any.
+func toErrors(err error) []Error {
+	var deleteObjectsErr *client.DeleteObjectsError
+	if errors.As(err, &deleteObjectsErr) {
+		errs := make([]Error, 0, len(deleteObjectsErr.Errors))
+		for _, e := range deleteObjectsErr.Errors {
+			errs = append(errs, Error{
+				Code:      aws.ToString(e.Code),
+				Message:   aws.ToString(e.Message),
+				Key:       aws.ToString(e.Key),
+				VersionId: aws.ToString(e.VersionId),
+			})
+		}
+		return errs
+	}
+
+	e := Error{
+		Message: err.Error(),
+	}
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		e.Code = apiErr.ErrorCode()
+	}
+	return []Error{e}
+}
@@ -0,0 +1,266 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
+	"github.com/go-to-k/cls3/internal/wrapper"
+	"github.com/go-to-k/cls3/pkg/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+/*
+	Test Cases
+*/
+
+func TestReporter_Report(t *testing.T) {
+	startedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(90 * time.Second)
+
+	reporter := NewReporter()
+	reporter.AddBucket("bucket2", nil, &client.ClientError{
+		ResourceName: aws.String("bucket2"),
+		Err:          fmt.Errorf("DeleteBucketError"),
+	}, startedAt, finishedAt)
+	reporter.AddBucket("bucket1", &wrapper.ClearBucketOutput{
+		ObjectsCount:       10,
+		VersionsCount:      5,
+		DeleteMarkersCount: 1,
+		BucketDeleted:      true,
+		Region:             "ap-northeast-1",
+	}, nil, startedAt, finishedAt)
+	reporter.AddBucket("bucket3", &wrapper.ClearBucketOutput{
+		ObjectsCount: 2,
+	}, nil, startedAt, finishedAt)
+
+	got := reporter.Report(Options{BucketType: "general", Region: "us-east-1"}, fmt.Errorf("DeleteBucketError"))
+
+	assert.Equal(t, StatusFailed, got.Status)
+	assert.Equal(t, 1, got.ExitCode)
+	assert.Equal(t, "DeleteBucketError", got.Error)
+	assert.Equal(t, Summary{
+		BucketsCount:          3,
+		SucceededBucketsCount: 2,
+		FailedBucketsCount:    1,
+		DeletedBucketsCount:   1,
+		ObjectsCount:          12,
+		VersionsCount:         5,
+		DeleteMarkersCount:    1,
+	}, got.Summary)
+	assert.Equal(t, []Bucket{
+		{
+			Target: "bucket1",
+			Type:   "general",
+			Status: StatusSucceeded,
+			ClearBucketOutput: wrapper.ClearBucketOutput{
+				ObjectsCount:       10,
+				VersionsCount:      5,
+				DeleteMarkersCount: 1,
+				BucketDeleted:      true,
+				Region:             "ap-northeast-1",
+			},
+			StartedAt:       startedAt,
+			FinishedAt:      finishedAt,
+			DurationSeconds: 90,
+		},
+		{
+			Target: "bucket2",
+			Type:   "general",
+			Status: StatusFailed,
+			ClearBucketOutput: wrapper.ClearBucketOutput{
+				Region: "us-east-1",
+			},
+			Errors: []Error{
+				{Message: "[resource bucket2] DeleteBucketError"},
+			},
+			StartedAt:       startedAt,
+			FinishedAt:      finishedAt,
+			DurationSeconds: 90,
+		},
+		{
+			Target: "bucket3",
+			Type:   "general",
+			Status: StatusSucceeded,
+			ClearBucketOutput: wrapper.ClearBucketOutput{
+				ObjectsCount: 2,
+				Region:       "us-east-1",
+			},
+			StartedAt:       startedAt,
+			FinishedAt:      finishedAt,
+			DurationSeconds: 90,
+		},
+	}, got.Buckets)
+}
+
+func TestReporter_Report_PartiallyCleared(t *testing.T) {
+	startedAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	finishedAt := startedAt.Add(90 * time.Second)
+
+	reporter := NewReporter()
+	reporter.AddBucket("bucket1", nil, &wrapper.PartiallyClearedError{
+		Output: &wrapper.ClearBucketOutput{
+			ObjectsCount: 10,
+			Region:       "ap-northeast-1",
+		},
+		Err: &client.ClientError{
+			ResourceName: aws.String("bucket1"),
+			Err:          fmt.Errorf("DeleteBucketError"),
+		},
+	}, startedAt, finishedAt)
+	reporter.AddBucket("bucket2", &wrapper.ClearBucketOutput{
+		ObjectsCount: 2,
+	}, nil, startedAt, finishedAt)
+
+	got := reporter.Report(Options{BucketType: "general", Region: "us-east-1"}, fmt.Errorf("DeleteBucketError"))
+
+	assert.Equal(t, Summary{
+		BucketsCount:                 2,
+		SucceededBucketsCount:        1,
+		PartiallyClearedBucketsCount: 1,
+		ObjectsCount:                 12,
+	}, got.Summary)
+	assert.Equal(t, Bucket{
+		Target: "bucket1",
+		Type:   "general",
+		Status: StatusPartiallyCleared,
+		ClearBucketOutput: wrapper.ClearBucketOutput{
+			ObjectsCount: 10,
+			Region:       "ap-northeast-1",
+		},
+		Errors: []Error{
+			{Message: "[resource bucket1] DeleteBucketError"},
+		},
+		StartedAt:       startedAt,
+		FinishedAt:      finishedAt,
+		DurationSeconds: 90,
+	}, got.Buckets[0])
+}
+
+func TestReporter_Report_SkippedBuckets(t *testing.T) {
+	reporter := NewReporter()
+	reporter.AddSkippedBucket("bucket2", "not expired until 2025-02-01T00:00:00Z")
+	reporter.AddSkippedBucket("bucket1", "invalid ttl tag: a week")
+
+	got := reporter.Report(Options{BucketType: "general", Region: "us-east-1"}, nil)
+
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, Summary{SkippedBucketsCount: 2}, got.Summary)
+	assert.Equal(t, []Bucket{}, got.Buckets)
+	assert.Equal(t, []SkippedBucket{
+		{Target: "bucket1", Reason: "invalid ttl tag: a week"},
+		{Target: "bucket2", Reason: "not expired until 2025-02-01T00:00:00Z"},
+	}, got.SkippedBuckets)
+}
+
+func Test_toErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want []Error
+	}{
+		{
+			name: "an error for each object DeleteObjects failed to delete",
+			err: &client.ClientError{
+				ResourceName: aws.String("test"),
+				Err: &client.DeleteObjectsError{
+					Errors: []types.Error{
+						{
+							Code:      aws.String("AccessDenied"),
+							Key:       aws.String("Key1"),
+							VersionId: aws.String("VersionId1"),
+							Message:   aws.String("Access Denied"),
+						},
+						{
+							Code:    aws.String("InternalError"),
+							Key:     aws.String("Key2"),
+							Message: aws.String("We encountered an internal error."),
+						},
+					},
+				},
+			},
+			want: []Error{
+				{Code: "AccessDenied", Message: "Access Denied", Key: "Key1", VersionId: "VersionId1"},
+				{Code: "InternalError", Message: "We encountered an internal error.", Key: "Key2"},
+			},
+		},
+		{
+			name: "the error code of an API error",
+			err: &client.ClientError{
+				ResourceName: aws.String("test"),
+				Err: fmt.Errorf("operation error S3: DeleteBucket: %w", &smithy.GenericAPIError{
+					Code:    "BucketNotEmpty",
+					Message: "The bucket you tried to delete is not empty",
+				}),
+			},
+			want: []Error{
+				{
+					Code:    "BucketNotEmpty",
+					Message: "[resource test] operation error S3: DeleteBucket: api error BucketNotEmpty: The bucket you tried to delete is not empty",
+				},
+			},
+		},
+		{
+			name: "no error code for other errors",
+			err:  fmt.Errorf("context canceled"),
+			want: []Error{
+				{Message: "context canceled"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, toErrors(tt.err))
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	reporter := NewReporter()
+	reporter.AddBucket("arn:aws:s3tables:us-east-1:123456789012:bucket/test", &wrapper.ClearBucketOutput{
+		NamespacesCount: 1,
+		TablesCount:     2,
+	}, nil, time.Now(), time.Now())
+	want := reporter.Report(Options{BucketType: "table", Region: "us-east-1", DryRun: true}, nil)
+
+	buf := &bytes.Buffer{}
+	require.NoError(t, Write(buf, want))
+
+	got := &Report{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), got))
+	assert.Equal(t, want, got)
+
+	// the keys are in camelCase for the parsers
+	fields := map[string]any{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &fields))
+	assert.Equal(t, true, fields["dryRun"])
+	bucket := fields["buckets"].([]any)[0].(map[string]any)
+	assert.Equal(t, float64(2), bucket["tablesCount"])
+	assert.Equal(t, "table", bucket["type"])
+}
+
+func TestWriteFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	want := NewReporter().Report(Options{BucketType: "general"}, nil)
+
+	require.NoError(t, WriteFile(path, want))
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	got := &Report{}
+	require.NoError(t, json.Unmarshal(data, got))
+	assert.Equal(t, want, got)
+	assert.Equal(t, StatusSucceeded, got.Status)
+	assert.Equal(t, []Bucket{}, got.Buckets)
+
+	assert.Error(t, WriteFile(filepath.Join(t.TempDir(), "not-exist", "report.json"), want))
+}
@@ -0,0 +1,147 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOFILE -package=$GOPACKAGE -write_package_comment=false
+package wrapper
+
+import (
+	"sync"
+
+	"github.com/go-to-k/cls3/internal/io"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// Checkpoint is the progress of clearing a bucket, which allows the clearing to be resumed.
+// All targets before the markers have been deleted, and Counts holds the numbers of them.
+// Completed means that all listed targets have been deleted, so the listing is not resumed.
+type Checkpoint struct {
+	KeyMarker         *string                `json:"keyMarker,omitempty"`         // for S3
+	VersionIdMarker   *string                `json:"versionIdMarker,omitempty"`   // for S3
+	VersionsCursor    *client.VersionsCursor `json:"versionsCursor,omitempty"`    // for S3 with the retention of versions
+	ContinuationToken *string                `json:"continuationToken,omitempty"` // for S3Tables namespaces and S3Vectors indexes
+	FilterTimes       *client.FilterTimes    `json:"filterTimes,omitempty"`       // for S3 with the filters relative to the time when the clearing started
+	Counts            ClearBucketOutput      `json:"counts"`
+	Completed         bool                   `json:"completed,omitempty"`
+}
+
+// isLastPage returns true if there is no page after the markers
+func (c Checkpoint) isLastPage() bool {
+	return c.KeyMarker == nil && c.VersionIdMarker == nil && c.ContinuationToken == nil
+}
+
+// ICheckpointer saves and loads the checkpoints of buckets.
+type ICheckpointer interface {
+	Load(bucket string) (Checkpoint, bool, error)
+	Save(bucket string, checkpoint Checkpoint) error
+	Delete(bucket string) error
+}
+
+// loadCheckpoint returns the saved checkpoint of a bucket (or a part of it by the key), or the empty one
+// to clear it from the beginning
+func loadCheckpoint(input ClearBucketInput, key string) (Checkpoint, error) {
+	if input.Checkpointer == nil || input.DryRun {
+		return Checkpoint{}, nil
+	}
+	checkpoint, found, err := input.Checkpointer.Load(key)
+	if err != nil {
+		return Checkpoint{}, err
+	}
+	if !found {
+		return Checkpoint{}, nil
+	}
+	io.Logger.Debug().Msgf("%v: Resume from the checkpoint", key)
+	return checkpoint, nil
+}
+
+// deleteCheckpoint deletes the checkpoint of a bucket (or a part of it by the key) after it has been cleared
+func deleteCheckpoint(input ClearBucketInput, key string) error {
+	if input.Checkpointer == nil || input.DryRun {
+		return nil
+	}
+	return input.Checkpointer.Delete(key)
+}
+
+// checkpointTracker saves a checkpoint each time the pages processed in parallel are completed
+// contiguously from the beginning. A page can be completed by several parts of it (e.g. namespaces).
+// A nil tracker does nothing.
+type checkpointTracker struct {
+	key          string // bucket name (or arn), or the bucket name with a key prefix
+	checkpointer ICheckpointer
+	checkpoint   Checkpoint
+	pages        []*trackedPage // pages not yet contiguously completed, in the listed order
+	mtx          sync.Mutex
+}
+
+type trackedPage struct {
+	next    Checkpoint // markers to the next page and the numbers of targets deleted in this page
+	pending int
+}
+
+// newCheckpointTracker returns nil if the checkpoints are not saved
+func newCheckpointTracker(input ClearBucketInput, key string, base Checkpoint) *checkpointTracker {
+	if input.Checkpointer == nil || input.DryRun {
+		return nil
+	}
+	return &checkpointTracker{
+		key:          key,
+		checkpointer: input.Checkpointer,
+		checkpoint:   base,
+		pages:        []*trackedPage{},
+	}
+}
+
+// addPage adds a listed page with the markers to the next page and the number of parts to be completed
+func (t *checkpointTracker) addPage(next Checkpoint, parts int) *trackedPage {
+	if t == nil {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	page := &trackedPage{
+		next:    next,
+		pending: parts,
+	}
+	t.pages = append(t.pages, page)
+	return page
+}
+
+// completePart completes a part of a page with the numbers of targets deleted in it
+func (t *checkpointTracker) completePart(page *trackedPage, counts ClearBucketOutput) error {
+	if t == nil {
+		return nil
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	page.pending--
+	page.next.Counts.add(counts)
+
+	advanced := false
+	for len(t.pages) > 0 && t.pages[0].pending <= 0 {
+		completed := t.pages[0]
+		t.pages = t.pages[1:]
+
+		t.checkpoint.KeyMarker = completed.next.KeyMarker
+		t.checkpoint.VersionIdMarker = completed.next.VersionIdMarker
+		t.checkpoint.ContinuationToken = completed.next.ContinuationToken
+		t.checkpoint.VersionsCursor = completed.next.VersionsCursor
+		t.checkpoint.Counts.add(completed.next.Counts)
+		// NOTE: The markers after the last page are empty, which would mean the beginning on resume and list
+		// the targets again with the counts restored, so the checkpoint is marked as completed instead.
+		t.checkpoint.Completed = completed.next.isLastPage()
+		advanced = true
+	}
+
+	if !advanced {
+		return nil
+	}
+	return t.checkpointer.Save(t.key, t.checkpoint)
+}
+
+// add adds the numbers of targets of another output
+func (o *ClearBucketOutput) add(other ClearBucketOutput) {
+	o.ObjectsCount += other.ObjectsCount
+	o.VersionsCount += other.VersionsCount
+	o.DeleteMarkersCount += other.DeleteMarkersCount
+	o.NamespacesCount += other.NamespacesCount
+	o.TablesCount += other.TablesCount
+	o.IndexesCount += other.IndexesCount
+}
@@ -0,0 +1,128 @@
+package wrapper
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"go.uber.org/mock/gomock"
+)
+
+/*
+	Test Cases
+*/
+
+func TestCheckpointTracker_completePart(t *testing.T) {
+	type completion struct {
+		page   int
+		counts ClearBucketOutput
+	}
+
+	cases := []struct {
+		name          string
+		base          Checkpoint
+		pages         []Checkpoint // next markers of each page
+		parts         int
+		completions   []completion
+		prepareMockFn func(m *MockICheckpointer)
+		wantErr       bool
+	}{
+		{
+			name:  "save a checkpoint for each page completed in order",
+			pages: []Checkpoint{{KeyMarker: aws.String("key1")}, {KeyMarker: aws.String("key2")}},
+			parts: 1,
+			completions: []completion{
+				{page: 0, counts: ClearBucketOutput{ObjectsCount: 1}},
+				{page: 1, counts: ClearBucketOutput{ObjectsCount: 2}},
+			},
+			prepareMockFn: func(m *MockICheckpointer) {
+				gomock.InOrder(
+					m.EXPECT().Save("test", Checkpoint{KeyMarker: aws.String("key1"), Counts: ClearBucketOutput{ObjectsCount: 1}}).Return(nil),
+					m.EXPECT().Save("test", Checkpoint{KeyMarker: aws.String("key2"), Counts: ClearBucketOutput{ObjectsCount: 3}}).Return(nil),
+				)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "not save a checkpoint until the previous pages are completed",
+			base:  Checkpoint{KeyMarker: aws.String("key0"), Counts: ClearBucketOutput{ObjectsCount: 10}},
+			pages: []Checkpoint{{KeyMarker: aws.String("key1")}, {KeyMarker: aws.String("key2")}, {KeyMarker: nil}},
+			parts: 1,
+			completions: []completion{
+				{page: 2, counts: ClearBucketOutput{ObjectsCount: 3}},
+				{page: 1, counts: ClearBucketOutput{ObjectsCount: 2}},
+				{page: 0, counts: ClearBucketOutput{ObjectsCount: 1}},
+			},
+			prepareMockFn: func(m *MockICheckpointer) {
+				m.EXPECT().Save("test", Checkpoint{KeyMarker: nil, Counts: ClearBucketOutput{ObjectsCount: 16}, Completed: true}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "not save a checkpoint until all parts of a page are completed",
+			pages: []Checkpoint{{ContinuationToken: aws.String("token1")}},
+			parts: 2,
+			completions: []completion{
+				{page: 0, counts: ClearBucketOutput{NamespacesCount: 1, TablesCount: 2}},
+				{page: 0, counts: ClearBucketOutput{NamespacesCount: 1, TablesCount: 3}},
+			},
+			prepareMockFn: func(m *MockICheckpointer) {
+				m.EXPECT().Save("test", Checkpoint{
+					ContinuationToken: aws.String("token1"),
+					Counts:            ClearBucketOutput{NamespacesCount: 2, TablesCount: 5},
+				}).Return(nil)
+			},
+			wantErr: false,
+		},
+		{
+			name:  "save checkpoint failure",
+			pages: []Checkpoint{{KeyMarker: aws.String("key1")}},
+			parts: 1,
+			completions: []completion{
+				{page: 0, counts: ClearBucketOutput{ObjectsCount: 1}},
+			},
+			prepareMockFn: func(m *MockICheckpointer) {
+				m.EXPECT().Save("test", gomock.Any()).Return(fmt.Errorf("SaveError"))
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			checkpointerMock := NewMockICheckpointer(ctrl)
+			tt.prepareMockFn(checkpointerMock)
+
+			tracker := newCheckpointTracker(ClearBucketInput{TargetBucket: "test", Checkpointer: checkpointerMock}, "test", tt.base)
+
+			pages := []*trackedPage{}
+			for _, next := range tt.pages {
+				pages = append(pages, tracker.addPage(next, tt.parts))
+			}
+
+			var err error
+			for _, c := range tt.completions {
+				if err = tracker.completePart(pages[c.page], c.counts); err != nil {
+					break
+				}
+			}
+
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckpointTracker_nil(t *testing.T) {
+	tracker := newCheckpointTracker(ClearBucketInput{TargetBucket: "test"}, "test", Checkpoint{})
+	if tracker != nil {
+		t.Fatalf("tracker = %#v, want nil without a checkpointer", tracker)
+	}
+
+	page := tracker.addPage(Checkpoint{KeyMarker: aws.String("key1")}, 1)
+	if err := tracker.completePart(page, ClearBucketOutput{ObjectsCount: 1}); err != nil {
+		t.Errorf("error = %v, want nil", err)
+	}
+}
@@ -0,0 +1,114 @@
+package wrapper
+
+import (
+	"context"
+	"sync"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/go-to-k/cls3/internal/interrupt"
+	"github.com/go-to-k/cls3/pkg/client"
+)
+
+// DefaultS3DeleteWorkers is the number of the delete workers for each bucket when it is not specified.
+const DefaultS3DeleteWorkers = 8
+
+// deleteWorkers is a pool of the workers that run the deletions of a bucket sent by the listing loops.
+// The channel of the deletions is as large as the pool, so a listing loop waits while all workers are busy
+// and the channel is full. This bounds the listed pages held in memory to about twice the number of the workers.
+type deleteWorkers struct {
+	jobs    chan deleteJob
+	parent  context.Context
+	ctx     context.Context // canceled when a deletion fails or the parent is canceled
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	failure error
+	mtx     sync.Mutex
+}
+
+type deleteJob struct {
+	fn   func(ctx context.Context) error
+	done func() // called after fn is run or skipped if not nil
+}
+
+// newDeleteWorkers starts the workers, or the default number of them if the number is not positive.
+// They run until close is called.
+func newDeleteWorkers(ctx context.Context, number int) *deleteWorkers {
+	if number <= 0 {
+		number = DefaultS3DeleteWorkers
+	}
+	workersCtx, cancel := context.WithCancel(ctx)
+	w := &deleteWorkers{
+		jobs:   make(chan deleteJob, number),
+		parent: ctx,
+		ctx:    workersCtx,
+		cancel: cancel,
+	}
+	w.wg.Add(number)
+	for range number {
+		go w.run()
+	}
+	return w
+}
+
+func (w *deleteWorkers) run() {
+	defer w.wg.Done()
+	for job := range w.jobs {
+		// NOTE: After a deletion fails (or the context is canceled), the rest are skipped but still done
+		// so that the listing loops waiting for them can return. When the run is interrupted, the deletions
+		// in progress are finished, but the ones not started yet are skipped in the same way.
+		if w.ctx.Err() == nil && !interrupt.IsStopping(w.parent) {
+			if err := job.fn(w.ctx); err != nil {
+				w.fail(err)
+			}
+		}
+		if job.done != nil {
+			job.done()
+		}
+	}
+}
+
+func (w *deleteWorkers) fail(err error) {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.failure == nil {
+		w.failure = err
+	}
+	w.cancel()
+}
+
+// submit waits until a worker can take the deletion. It returns false without sending it if the workers have stopped,
+// so done is not called for it.
+func (w *deleteWorkers) submit(job deleteJob) bool {
+	select {
+	case w.jobs <- job:
+		return true
+	case <-w.ctx.Done():
+		return false
+	case <-interrupt.Stopping(w.parent):
+		return false
+	}
+}
+
+// stoppedErr returns the first error of the deletions, or the error of the context if it was canceled or
+// interrupted because the deletions after it are skipped. It returns nil if the workers have not stopped.
+func (w *deleteWorkers) stoppedErr(bucket string) error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.failure != nil {
+		return w.failure
+	}
+	if err := w.parent.Err(); err != nil {
+		return &client.ClientError{
+			ResourceName: aws.String(bucket),
+			Err:          err,
+		}
+	}
+	return interrupted(w.parent, bucket)
+}
+
+// close waits for the workers to finish the deletions sent to them. No more deletions can be sent after it.
+func (w *deleteWorkers) close() {
+	close(w.jobs)
+	w.wg.Wait()
+	w.cancel()
+}
@@ -0,0 +1,205 @@
+package wrapper
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-to-k/cls3/internal/interrupt"
+)
+
+/*
+	Test Cases
+*/
+
+func TestDeleteWorkers(t *testing.T) {
+	cases := []struct {
+		name        string
+		number      int
+		jobs        int
+		failAt      int // the index of the job to fail, or -1
+		cancel      bool
+		interrupt   bool
+		wantWorkers int
+		wantRun     int64
+		wantErr     string
+	}{
+		{
+			name:        "run all deletions with the workers",
+			number:      2,
+			jobs:        10,
+			failAt:      -1,
+			wantWorkers: 2,
+			wantRun:     10,
+		},
+		{
+			name:        "run deletions with the default number of workers",
+			number:      0,
+			jobs:        3,
+			failAt:      -1,
+			wantWorkers: DefaultS3DeleteWorkers,
+			wantRun:     3,
+		},
+		{
+			name:        "skip the deletions after a deletion fails",
+			number:      1,
+			jobs:        3,
+			failAt:      0,
+			wantWorkers: 1,
+			wantRun:     1,
+			wantErr:     "DeleteObjectsError",
+		},
+		{
+			name:        "skip the deletions after the context is canceled",
+			number:      1,
+			jobs:        3,
+			failAt:      -1,
+			cancel:      true,
+			wantWorkers: 1,
+			wantRun:     0,
+			wantErr:     "[resource test] context canceled",
+		},
+		{
+			name:        "skip the deletions after the run is interrupted",
+			number:      1,
+			jobs:        3,
+			failAt:      -1,
+			interrupt:   true,
+			wantWorkers: 1,
+			wantRun:     0,
+			wantErr:     "[resource test] InterruptedError: stopped by a signal",
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			if tt.cancel {
+				cancel()
+			}
+			ctx, stop := interrupt.WithStopping(ctx)
+			if tt.interrupt {
+				stop()
+			}
+
+			workers := newDeleteWorkers(ctx, tt.number)
+			if cap(workers.jobs) != tt.wantWorkers {
+				t.Errorf("workers = %d, want %d", cap(workers.jobs), tt.wantWorkers)
+			}
+
+			var run atomic.Int64
+			var done sync.WaitGroup
+			for i := range tt.jobs {
+				done.Add(1)
+				sent := workers.submit(deleteJob{
+					done: done.Done,
+					fn: func(ctx context.Context) error {
+						run.Add(1)
+						if i == tt.failAt {
+							return fmt.Errorf("DeleteObjectsError")
+						}
+						return nil
+					},
+				})
+				if !sent {
+					done.Done()
+				}
+			}
+			done.Wait()
+			workers.close()
+
+			if run.Load() != tt.wantRun {
+				t.Errorf("run = %d, want %d", run.Load(), tt.wantRun)
+			}
+			err := workers.stoppedErr("test")
+			if tt.wantErr == "" && err != nil {
+				t.Errorf("err = %#v, want nil", err.Error())
+			}
+			if tt.wantErr != "" && (err == nil || err.Error() != tt.wantErr) {
+				t.Errorf("err = %#v, want %#v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDeleteWorkers_submit(t *testing.T) {
+	workers := newDeleteWorkers(context.Background(), 1)
+	defer workers.close()
+
+	var running atomic.Int64
+	var maxRunning atomic.Int64
+	release := make(chan struct{})
+	job := deleteJob{
+		fn: func(ctx context.Context) error {
+			current := running.Add(1)
+			if current > maxRunning.Load() {
+				maxRunning.Store(current)
+			}
+			<-release
+			running.Add(-1)
+			return nil
+		},
+	}
+
+	// NOTE: One deletion is run by the worker and one waits in the channel, so the third one waits for them.
+	workers.submit(job)
+	workers.submit(job)
+	sent := make(chan bool)
+	go func() {
+		sent <- workers.submit(job)
+	}()
+
+	select {
+	case <-sent:
+		t.Fatal("submit did not wait for the busy workers")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if !<-sent {
+		t.Error("submit = false, want true")
+	}
+	if maxRunning.Load() != 1 {
+		t.Errorf("max running = %d, want 1", maxRunning.Load())
+	}
+}
+
+func TestDeleteWorkers_Interrupted(t *testing.T) {
+	ctx, stop := interrupt.WithStopping(context.Background())
+	workers := newDeleteWorkers(ctx, 1)
+
+	var run atomic.Int64
+	started := make(chan struct{})
+	release := make(chan struct{})
+	workers.submit(deleteJob{fn: func(ctx context.Context) error {
+		close(started)
+		<-release
+		run.Add(1)
+		return ctx.Err()
+	}})
+	workers.submit(deleteJob{fn: func(ctx context.Context) error {
+		run.Add(1)
+		return nil
+	}})
+
+	// NOTE: The deletion in progress is finished without its context canceled, and the one waiting is skipped.
+	<-started
+	stop()
+	if workers.submit(deleteJob{fn: func(ctx context.Context) error { return nil }}) {
+		t.Error("submit = true, want false after the run is interrupted")
+	}
+	close(release)
+	workers.close()
+
+	if run.Load() != 1 {
+		t.Errorf("run = %d, want 1", run.Load())
+	}
+	if err := workers.stoppedErr("test"); !errors.Is(err, interrupt.ErrInterrupted) {
+		t.Errorf("err = %v, want %v", err, interrupt.ErrInterrupted)
+	}
+}
@@ -55,11 +55,12 @@ func (mr *MockIWrapperMockRecorder) CheckAllBucketsExist(ctx, bucketNames any) *
 }
 
 // ClearBucket mocks base method.
-func (m *MockIWrapper) ClearBucket(ctx context.Context, input ClearBucketInput) error {
+func (m *MockIWrapper) ClearBucket(ctx context.Context, input ClearBucketInput) (*ClearBucketOutput, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ClearBucket", ctx, input)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(*ClearBucketOutput)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ClearBucket indicates an expected call of ClearBucket.
@@ -68,6 +69,21 @@ func (mr *MockIWrapperMockRecorder) ClearBucket(ctx, input any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBucket", reflect.TypeOf((*MockIWrapper)(nil).ClearBucket), ctx, input)
 }
 
+// GetBucketTags mocks base method.
+func (m *MockIWrapper) GetBucketTags(ctx context.Context, bucket ListBucketNamesFilteredByKeywordOutput) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketTags", ctx, bucket)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketTags indicates an expected call of GetBucketTags.
+func (mr *MockIWrapperMockRecorder) GetBucketTags(ctx, bucket any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketTags", reflect.TypeOf((*MockIWrapper)(nil).GetBucketTags), ctx, bucket)
+}
+
 // GetLiveClearedMessage mocks base method.
 func (m *MockIWrapper) GetLiveClearedMessage(bucket string, count int64, isCompleted bool) (string, error) {
 	m.ctrl.T.Helper()
@@ -154,3 +170,17 @@ func (mr *MockIWrapperMockRecorder) OutputDeletedMessage(bucket any) *gomock.Cal
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutputDeletedMessage", reflect.TypeOf((*MockIWrapper)(nil).OutputDeletedMessage), bucket)
 }
+
+// OutputDryRunMessage mocks base method.
+func (m *MockIWrapper) OutputDryRunMessage(bucket string, output *ClearBucketOutput) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "OutputDryRunMessage", bucket, output)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// OutputDryRunMessage indicates an expected call of OutputDryRunMessage.
+func (mr *MockIWrapperMockRecorder) OutputDryRunMessage(bucket, output any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OutputDryRunMessage", reflect.TypeOf((*MockIWrapper)(nil).OutputDryRunMessage), bucket, output)
+}
@@ -34,33 +34,61 @@ func (s *S3TablesWrapper) deleteNamespace(
 	bucketArn string,
 	bucketName string,
 	namespace string,
+	dryRun bool,
+	recorder ITargetRecorder,
+	manifest IDeletionRecorder,
 	progressCh chan<- struct{},
-) error {
+) (int64, error) {
 	eg := errgroup.Group{}
 	sem := semaphore.NewWeighted(S3TablesSemaphoreWeight)
 
+	var tablesCount int64
 	var continuationToken *string
 	for {
 		select {
 		case <-ctx.Done():
-			return &client.ClientError{
+			return 0, &client.ClientError{
 				ResourceName: aws.String(bucketName + "/" + namespace),
 				Err:          ctx.Err(),
 			}
 		default:
 		}
+		if err := interrupted(ctx, bucketName+"/"+namespace); err != nil {
+			_ = eg.Wait()
+			return 0, err
+		}
 
 		output, err := s.client.ListTablesByPage(ctx, aws.String(bucketArn), aws.String(namespace), continuationToken)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		if len(output.Tables) == 0 {
 			break
 		}
+		tablesCount += int64(len(output.Tables))
+
+		if dryRun && recorder != nil {
+			targets := make([]Target, 0, len(output.Tables))
+			for _, table := range output.Tables {
+				targets = append(targets, Target{Namespace: namespace, Table: aws.ToString(table.Name)})
+			}
+			if err := recorder.RecordTargets(bucketArn, targets); err != nil {
+				return 0, err
+			}
+		}
 
 		for _, table := range output.Tables {
+			// NOTE: Nothing is deleted in the dry-run mode, so the listed tables are only counted.
+			if dryRun {
+				progressCh <- struct{}{}
+				continue
+			}
+			if err := interrupted(ctx, bucketName+"/"+namespace); err != nil {
+				_ = eg.Wait()
+				return 0, err
+			}
 			if err := sem.Acquire(ctx, 1); err != nil {
-				return err
+				return 0, err
 			}
 			eg.Go(func() error {
 				defer sem.Release(1)
@@ -68,7 +96,7 @@ func (s *S3TablesWrapper) deleteNamespace(
 					return err
 				}
 				progressCh <- struct{}{}
-				return nil
+				return recordDeleted(manifest, bucketArn, Target{Namespace: namespace, Table: aws.ToString(table.Name)})
 			})
 		}
 
@@ -78,24 +106,99 @@ func (s *S3TablesWrapper) deleteNamespace(
 		}
 	}
 
+	if err := eg.Wait(); err != nil {
+		return 0, err
+	}
+
+	if dryRun {
+		if recorder != nil {
+			// NOTE: The namespace itself is recorded after its tables so that it is deleted after them.
+			if err := recorder.RecordTargets(bucketArn, []Target{{Namespace: namespace}}); err != nil {
+				return 0, err
+			}
+		}
+		return tablesCount, nil
+	}
+	if err := s.client.DeleteNamespace(ctx, aws.String(namespace), aws.String(bucketArn)); err != nil {
+		return 0, err
+	}
+	if err := recordDeleted(manifest, bucketArn, Target{Namespace: namespace}); err != nil {
+		return 0, err
+	}
+	return tablesCount, nil
+}
+
+// deleteTargetNamespace deletes the given tables in a namespace, and then the namespace itself if needed
+func (s *S3TablesWrapper) deleteTargetNamespace(
+	ctx context.Context,
+	bucketArn string,
+	namespace string,
+	tables []string,
+	deletesNamespace bool,
+	manifest IDeletionRecorder,
+	progressCh chan<- struct{},
+) error {
+	eg := errgroup.Group{}
+	sem := semaphore.NewWeighted(S3TablesSemaphoreWeight)
+
+	for _, table := range tables {
+		if err := interrupted(ctx, namespace); err != nil {
+			_ = eg.Wait()
+			return err
+		}
+		if err := sem.Acquire(ctx, 1); err != nil {
+			_ = eg.Wait()
+			return err
+		}
+		eg.Go(func() error {
+			defer sem.Release(1)
+			if err := s.client.DeleteTable(ctx, aws.String(table), aws.String(namespace), aws.String(bucketArn)); err != nil {
+				return err
+			}
+			progressCh <- struct{}{}
+			return recordDeleted(manifest, bucketArn, Target{Namespace: namespace, Table: table})
+		})
+	}
+
 	if err := eg.Wait(); err != nil {
 		return err
 	}
 
-	return s.client.DeleteNamespace(ctx, aws.String(namespace), aws.String(bucketArn))
+	if !deletesNamespace {
+		return nil
+	}
+	if err := s.client.DeleteNamespace(ctx, aws.String(namespace), aws.String(bucketArn)); err != nil {
+		return err
+	}
+	return recordDeleted(manifest, bucketArn, Target{Namespace: namespace})
 }
 
 func (s *S3TablesWrapper) ClearBucket(
 	ctx context.Context,
 	input ClearBucketInput,
-) error {
+) (*ClearBucketOutput, error) {
 	bucketArn := input.TargetBucket
 	bucketName, err := s.outputBucketName(bucketArn)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	checkpoint := Checkpoint{}
+	if input.Targets == nil {
+		checkpoint, err = loadCheckpoint(input, input.TargetBucket)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	var deletedTablesCount atomic.Int64
+	var deletedNamespacesCount atomic.Int64
+	deletedTablesCount.Store(checkpoint.Counts.TablesCount)
+	deletedNamespacesCount.Store(checkpoint.Counts.NamespacesCount)
+	if !input.QuietMode && checkpoint.Counts.TablesCount > 0 {
+		input.ClearingCountCh <- checkpoint.Counts.TablesCount
+	}
+
 	progressCh := make(chan struct{})
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -109,20 +212,94 @@ func (s *S3TablesWrapper) ClearBucket(
 		}
 	}()
 
+	// NOTE: When the targets are given, only they are deleted without listing, so tables
+	// created after the targets were determined are not deleted.
+	if input.Targets != nil {
+		err = s.clearTargetNamespaces(ctx, input, bucketName, &deletedNamespacesCount, progressCh)
+	} else {
+		err = s.clearNamespaces(ctx, input, bucketName, newCheckpointTracker(input, input.TargetBucket, checkpoint), &deletedNamespacesCount, progressCh)
+	}
+	close(progressCh)
+	wg.Wait()
+	output := &ClearBucketOutput{
+		NamespacesCount: deletedNamespacesCount.Load(),
+		TablesCount:     deletedTablesCount.Load(),
+	}
+	if err != nil {
+		return nil, partiallyCleared(input, output, err)
+	}
+	if err := deleteCheckpoint(input, input.TargetBucket); err != nil {
+		return nil, err
+	}
+
+	// NOTE: In the dry-run mode, the bucket is only reported as the one to be deleted,
+	// and the message is displayed as a summary of all buckets in the app.go.
+	if input.DryRun {
+		output.BucketDeleted = input.ForceMode
+		return output, nil
+	}
+
+	if input.QuietMode {
+		// When not in quiet mode, the message is displayed along with other buckets in the app.go.
+		if err := s.OutputClearedMessage(bucketArn, output.TablesCount); err != nil {
+			return nil, err
+		}
+	}
+
+	if !input.ForceMode {
+		return output, nil
+	}
+
+	if err := s.client.DeleteTableBucket(ctx, aws.String(bucketArn)); err != nil {
+		return nil, partiallyCleared(input, output, err)
+	}
+	output.BucketDeleted = true
+
+	// NOTE: When not in quiet mode, the message is displayed along with other buckets in the app.go.
+	if !input.QuietMode {
+		return output, nil
+	}
+
+	if err := s.OutputDeletedMessage(bucketArn); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// clearNamespaces lists all namespaces in a bucket and deletes them with their tables
+func (s *S3TablesWrapper) clearNamespaces(
+	ctx context.Context,
+	input ClearBucketInput,
+	bucketName string,
+	tracker *checkpointTracker,
+	deletedNamespacesCount *atomic.Int64,
+	progressCh chan<- struct{},
+) error {
+	bucketArn := input.TargetBucket
 	eg := errgroup.Group{}
 	sem := semaphore.NewWeighted(S3TablesSemaphoreWeight)
 	var continuationToken *string
+	if tracker != nil {
+		if tracker.checkpoint.Completed {
+			return nil
+		}
+		continuationToken = tracker.checkpoint.ContinuationToken
+	}
 	for {
 		select {
 		case <-ctx.Done():
-			close(progressCh)
-			wg.Wait()
+			_ = eg.Wait()
 			return &client.ClientError{
 				ResourceName: aws.String(bucketName),
 				Err:          ctx.Err(),
 			}
 		default:
 		}
+		if err := interrupted(ctx, bucketName); err != nil {
+			_ = eg.Wait()
+			return err
+		}
 
 		output, err := s.client.ListNamespacesByPage(
 			ctx,
@@ -130,24 +307,37 @@ func (s *S3TablesWrapper) ClearBucket(
 			continuationToken,
 		)
 		if err != nil {
-			close(progressCh)
-			wg.Wait()
+			_ = eg.Wait()
 			return err
 		}
 		if len(output.Namespaces) == 0 {
 			break
 		}
 
+		namespacesCount := 0
+		for _, summary := range output.Namespaces {
+			namespacesCount += len(summary.Namespace)
+		}
+		page := tracker.addPage(Checkpoint{ContinuationToken: output.ContinuationToken}, namespacesCount)
+
 		for _, summary := range output.Namespaces {
 			for _, namespace := range summary.Namespace {
+				if err := interrupted(ctx, bucketName); err != nil {
+					_ = eg.Wait()
+					return err
+				}
 				if err := sem.Acquire(ctx, 1); err != nil {
-					close(progressCh)
-					wg.Wait()
+					_ = eg.Wait()
 					return err
 				}
 				eg.Go(func() error {
 					defer sem.Release(1)
-					return s.deleteNamespace(ctx, bucketArn, bucketName, namespace, progressCh)
+					tablesCount, err := s.deleteNamespace(ctx, bucketArn, bucketName, namespace, input.DryRun, input.Recorder, input.Manifest, progressCh)
+					if err != nil {
+						return err
+					}
+					deletedNamespacesCount.Add(1)
+					return tracker.completePart(page, ClearBucketOutput{NamespacesCount: 1, TablesCount: tablesCount})
 				})
 			}
 		}
@@ -158,40 +348,67 @@ func (s *S3TablesWrapper) ClearBucket(
 		}
 	}
 
-	if err := eg.Wait(); err != nil {
-		close(progressCh)
-		wg.Wait()
-		return err
-	}
-	close(progressCh)
-	wg.Wait()
+	return eg.Wait()
+}
 
-	finalCount := deletedTablesCount.Load()
-	if input.QuietMode {
-		// When not in quiet mode, the message is displayed along with other buckets in the app.go.
-		if err := s.OutputClearedMessage(bucketArn, finalCount); err != nil {
-			return err
+// clearTargetNamespaces deletes the given tables and namespaces in a bucket
+func (s *S3TablesWrapper) clearTargetNamespaces(
+	ctx context.Context,
+	input ClearBucketInput,
+	bucketName string,
+	deletedNamespacesCount *atomic.Int64,
+	progressCh chan<- struct{},
+) error {
+	bucketArn := input.TargetBucket
+	tables := map[string][]string{}
+	namespaces := []string{}
+	deletesNamespace := map[string]bool{}
+
+	err := input.Targets.ForEachTargets(bucketArn, func(targets []Target) error {
+		for _, target := range targets {
+			if _, ok := tables[target.Namespace]; !ok {
+				tables[target.Namespace] = []string{}
+				namespaces = append(namespaces, target.Namespace)
+			}
+			if target.Table == "" {
+				deletesNamespace[target.Namespace] = true
+				continue
+			}
+			tables[target.Namespace] = append(tables[target.Namespace], target.Table)
 		}
-	}
-
-	if !input.ForceMode {
 		return nil
-	}
-
-	if err := s.client.DeleteTableBucket(ctx, aws.String(bucketArn)); err != nil {
+	})
+	if err != nil {
 		return err
 	}
 
-	// NOTE: When not in quiet mode, the message is displayed along with other buckets in the app.go.
-	if !input.QuietMode {
-		return nil
-	}
-
-	if err := s.OutputDeletedMessage(bucketArn); err != nil {
-		return err
+	eg := errgroup.Group{}
+	sem := semaphore.NewWeighted(S3TablesSemaphoreWeight)
+	for _, namespace := range namespaces {
+		if err := interrupted(ctx, bucketName); err != nil {
+			_ = eg.Wait()
+			return err
+		}
+		if err := sem.Acquire(ctx, 1); err != nil {
+			_ = eg.Wait()
+			return &client.ClientError{
+				ResourceName: aws.String(bucketName),
+				Err:          err,
+			}
+		}
+		eg.Go(func() error {
+			defer sem.Release(1)
+			if err := s.deleteTargetNamespace(ctx, bucketArn, namespace, tables[namespace], deletesNamespace[namespace], input.Manifest, progressCh); err != nil {
+				return err
+			}
+			if deletesNamespace[namespace] {
+				deletedNamespacesCount.Add(1)
+			}
+			return nil
+		})
 	}
 
-	return nil
+	return eg.Wait()
 }
 
 func (s *S3TablesWrapper) outputBucketName(bucketArn string) (string, error) {
@@ -235,6 +452,18 @@ func (s *S3TablesWrapper) OutputCheckingMessage(bucket string) error {
 	return nil
 }
 
+func (s *S3TablesWrapper) OutputDryRunMessage(bucket string, output *ClearBucketOutput) error {
+	bucketName, err := s.outputBucketName(bucket)
+	if err != nil {
+		return err
+	}
+	io.Logger.Info().Msgf("%v Dry run: %v namespaces, %v tables would be deleted.", bucketName, output.NamespacesCount, output.TablesCount)
+	if output.BucketDeleted {
+		io.Logger.Info().Msgf("%v Dry run: the bucket would be deleted.", bucketName)
+	}
+	return nil
+}
+
 func (s *S3TablesWrapper) GetLiveClearingMessage(bucket string, count int64) (string, error) {
 	bucketName, err := s.outputBucketName(bucket)
 	if err != nil {
@@ -269,6 +498,8 @@ func (s *S3TablesWrapper) ListBucketNamesFilteredByKeyword(ctx context.Context,
 			filteredBuckets = append(filteredBuckets, ListBucketNamesFilteredByKeywordOutput{
 				BucketName:   *bucket.Name,
 				TargetBucket: *bucket.Arn,
+				CreationDate: bucket.CreatedAt,
+				Region:       regionFromArn(*bucket.Arn),
 			})
 		}
 	}
@@ -283,6 +514,14 @@ func (s *S3TablesWrapper) ListBucketNamesFilteredByKeyword(ctx context.Context,
 	return filteredBuckets, nil
 }
 
+// GetBucketTags returns an error because the tags of Table Buckets cannot be read.
+func (s *S3TablesWrapper) GetBucketTags(ctx context.Context, bucket ListBucketNamesFilteredByKeywordOutput) (map[string]string, error) {
+	return nil, &client.ClientError{
+		ResourceName: aws.String(bucket.BucketName),
+		Err:          fmt.Errorf("NotSupportedError: The tags of Table Buckets cannot be read."),
+	}
+}
+
 func (s *S3TablesWrapper) CheckAllBucketsExist(ctx context.Context, bucketNames []string) ([]string, error) {
 	targetBucketArns := []string{}
 	nonExistingBucketNames := []string{}
@@ -29,14 +29,19 @@ func TestS3TablesWrapper_ClearBucket(t *testing.T) {
 		bucketName string
 		forceMode  bool
 		quietMode  bool
+		dryRun     bool
 	}
 
 	cases := []struct {
-		name          string
-		args          args
-		prepareMockFn func(m *client.MockIS3Tables)
-		want          error
-		wantErr       bool
+		name                    string
+		args                    args
+		prepareMockFn           func(m *client.MockIS3Tables)
+		prepareTargetMockFn     func(r *MockITargetRecorder, s *MockITargetSource) // set the recorder and the source only if not nil
+		prepareCheckpointMockFn func(c *MockICheckpointer)                         // set the checkpointer only if not nil
+		prepareManifestMockFn   func(r *MockIDeletionRecorder)                     // set the manifest only if not nil
+		want                    error
+		wantOutput              *ClearBucketOutput
+		wantErr                 bool
 	}{
 		{
 			name: "clear tables successfully",
@@ -259,6 +264,217 @@ func TestS3TablesWrapper_ClearBucket(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "list namespaces and tables without deleting them and the bucket in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  true,
+				quietMode:  true,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().ListNamespacesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					nil,
+				).Return(
+					&client.ListNamespacesByPageOutput{
+						Namespaces: []types.NamespaceSummary{
+							{
+								Namespace: []string{"namespace1"},
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+
+				m.EXPECT().ListTablesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("namespace1"),
+					nil,
+				).Return(
+					&client.ListTablesByPageOutput{
+						Tables: []types.TableSummary{
+							{
+								Name: aws.String("table1"),
+							},
+							{
+								Name: aws.String("table2"),
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				NamespacesCount: 1,
+				TablesCount:     2,
+				BucketDeleted:   true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "record listed tables and namespaces in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  false,
+				quietMode:  true,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().ListNamespacesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					nil,
+				).Return(
+					&client.ListNamespacesByPageOutput{
+						Namespaces: []types.NamespaceSummary{
+							{
+								Namespace: []string{"namespace1"},
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+
+				m.EXPECT().ListTablesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("namespace1"),
+					nil,
+				).Return(
+					&client.ListTablesByPageOutput{
+						Tables: []types.TableSummary{
+							{
+								Name: aws.String("table1"),
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				gomock.InOrder(
+					r.EXPECT().RecordTargets(
+						"arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+						[]Target{{Namespace: "namespace1", Table: "table1"}},
+					).Return(nil),
+					r.EXPECT().RecordTargets(
+						"arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+						[]Target{{Namespace: "namespace1"}},
+					).Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				NamespacesCount: 1,
+				TablesCount:     1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete only given tables and namespaces and the bucket without listing",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().DeleteTable(
+					gomock.Any(),
+					aws.String("table1"),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+				m.EXPECT().DeleteNamespace(
+					gomock.Any(),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+				m.EXPECT().DeleteTable(
+					gomock.Any(),
+					aws.String("table2"),
+					aws.String("namespace2"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+				m.EXPECT().DeleteTableBucket(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("arn:aws:s3:us-east-1:123456789012:table-bucket/test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						// namespace2 itself is not a target, so only its table is deleted.
+						return fn([]Target{
+							{Namespace: "namespace1", Table: "table1"},
+							{Namespace: "namespace1"},
+							{Namespace: "namespace2", Table: "table2"},
+						})
+					},
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				NamespacesCount: 1,
+				TablesCount:     2,
+				BucketDeleted:   true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete given tables failure for target source errors",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("arn:aws:s3:us-east-1:123456789012:table-bucket/test", gomock.Any()).Return(fmt.Errorf("ForEachTargetsError"))
+			},
+			want:    fmt.Errorf("ForEachTargetsError"),
+			wantErr: true,
+		},
+		{
+			name: "delete given tables failure for delete table errors",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().DeleteTable(
+					gomock.Any(),
+					aws.String("table1"),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(fmt.Errorf("DeleteTableError"))
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("arn:aws:s3:us-east-1:123456789012:table-bucket/test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn([]Target{
+							{Namespace: "namespace1", Table: "table1"},
+							{Namespace: "namespace1"},
+						})
+					},
+				)
+			},
+			want:    fmt.Errorf("DeleteTableError"),
+			wantErr: true,
+		},
 		{
 			name: "invalid bucket ARN format",
 			args: args{
@@ -389,6 +605,195 @@ func TestS3TablesWrapper_ClearBucket(t *testing.T) {
 			want:    fmt.Errorf("DeleteTableBucketError"),
 			wantErr: true,
 		},
+		{
+			name: "resume clearing namespaces from the checkpoint",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  false,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().ListNamespacesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("token"),
+				).Return(
+					&client.ListNamespacesByPageOutput{
+						Namespaces: []types.NamespaceSummary{
+							{
+								Namespace: []string{"namespace2"},
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().ListTablesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("namespace2"),
+					nil,
+				).Return(
+					&client.ListTablesByPageOutput{
+						Tables: []types.TableSummary{
+							{
+								Name: aws.String("table3"),
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteTable(
+					gomock.Any(),
+					aws.String("table3"),
+					aws.String("namespace2"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+				m.EXPECT().DeleteNamespace(
+					gomock.Any(),
+					aws.String("namespace2"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				gomock.InOrder(
+					c.EXPECT().Load("arn:aws:s3:us-east-1:123456789012:table-bucket/test").Return(Checkpoint{
+						ContinuationToken: aws.String("token"),
+						Counts:            ClearBucketOutput{NamespacesCount: 1, TablesCount: 2},
+					}, true, nil),
+					c.EXPECT().Save("arn:aws:s3:us-east-1:123456789012:table-bucket/test", Checkpoint{
+						Counts:    ClearBucketOutput{NamespacesCount: 2, TablesCount: 3},
+						Completed: true,
+					}).Return(nil),
+					c.EXPECT().Delete("arn:aws:s3:us-east-1:123456789012:table-bucket/test").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				NamespacesCount: 2,
+				TablesCount:     3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "record deleted tables and namespaces to the manifest",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().ListNamespacesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					nil,
+				).Return(
+					&client.ListNamespacesByPageOutput{
+						Namespaces: []types.NamespaceSummary{
+							{
+								Namespace: []string{"namespace1"},
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().ListTablesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("namespace1"),
+					nil,
+				).Return(
+					&client.ListTablesByPageOutput{
+						Tables: []types.TableSummary{
+							{
+								Name: aws.String("table1"),
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteTable(
+					gomock.Any(),
+					aws.String("table1"),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+				m.EXPECT().DeleteNamespace(
+					gomock.Any(),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+			},
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				gomock.InOrder(
+					r.EXPECT().RecordDeleted("arn:aws:s3:us-east-1:123456789012:table-bucket/test", []Target{
+						{Namespace: "namespace1", Table: "table1"},
+					}).Return(nil),
+					r.EXPECT().RecordDeleted("arn:aws:s3:us-east-1:123456789012:table-bucket/test", []Target{
+						{Namespace: "namespace1"},
+					}).Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				NamespacesCount: 1,
+				TablesCount:     1,
+			},
+			wantErr: false,
+		},
+		{
+			name: "save checkpoint failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "arn:aws:s3:us-east-1:123456789012:table-bucket/test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3Tables) {
+				m.EXPECT().ListNamespacesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					nil,
+				).Return(
+					&client.ListNamespacesByPageOutput{
+						Namespaces: []types.NamespaceSummary{
+							{
+								Namespace: []string{"namespace1"},
+							},
+						},
+						ContinuationToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().ListTablesByPage(
+					gomock.Any(),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+					aws.String("namespace1"),
+					nil,
+				).Return(
+					&client.ListTablesByPageOutput{
+						Tables: []types.TableSummary{},
+					},
+					nil,
+				)
+				m.EXPECT().DeleteNamespace(
+					gomock.Any(),
+					aws.String("namespace1"),
+					aws.String("arn:aws:s3:us-east-1:123456789012:table-bucket/test"),
+				).Return(nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("arn:aws:s3:us-east-1:123456789012:table-bucket/test").Return(Checkpoint{}, false, nil)
+				c.EXPECT().Save("arn:aws:s3:us-east-1:123456789012:table-bucket/test", gomock.Any()).Return(fmt.Errorf("SaveError"))
+			},
+			want:    fmt.Errorf("SaveError"),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range cases {
@@ -407,12 +812,35 @@ func TestS3TablesWrapper_ClearBucket(t *testing.T) {
 				}()
 			}
 
-			err := s3Tables.ClearBucket(tt.args.ctx, ClearBucketInput{
+			input := ClearBucketInput{
 				TargetBucket:    tt.args.bucketName,
 				ForceMode:       tt.args.forceMode,
 				QuietMode:       tt.args.quietMode,
 				ClearingCountCh: clearingCountCh,
-			})
+				DryRun:          tt.args.dryRun,
+			}
+			if tt.prepareTargetMockFn != nil {
+				recorderMock := NewMockITargetRecorder(ctrl)
+				sourceMock := NewMockITargetSource(ctrl)
+				tt.prepareTargetMockFn(recorderMock, sourceMock)
+				if tt.args.dryRun {
+					input.Recorder = recorderMock
+				} else {
+					input.Targets = sourceMock
+				}
+			}
+			if tt.prepareCheckpointMockFn != nil {
+				checkpointerMock := NewMockICheckpointer(ctrl)
+				tt.prepareCheckpointMockFn(checkpointerMock)
+				input.Checkpointer = checkpointerMock
+			}
+			if tt.prepareManifestMockFn != nil {
+				manifestMock := NewMockIDeletionRecorder(ctrl)
+				tt.prepareManifestMockFn(manifestMock)
+				input.Manifest = manifestMock
+			}
+
+			output, err := s3Tables.ClearBucket(tt.args.ctx, input)
 
 			close(clearingCountCh)
 
@@ -424,6 +852,9 @@ func TestS3TablesWrapper_ClearBucket(t *testing.T) {
 				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.Error())
 				return
 			}
+			if tt.wantOutput != nil && !reflect.DeepEqual(output, tt.wantOutput) {
+				t.Errorf("output = %#v, want %#v", output, tt.wantOutput)
+			}
 		})
 	}
 }
@@ -436,6 +867,7 @@ func TestS3TablesWrapper_deleteNamespace(t *testing.T) {
 		bucketArn  string
 		bucketName string
 		namespace  string
+		dryRun     bool
 	}
 
 	type want struct {
@@ -683,7 +1115,7 @@ func TestS3TablesWrapper_deleteNamespace(t *testing.T) {
 				}
 			}()
 
-			err := s3Tables.deleteNamespace(tt.args.ctx, tt.args.bucketArn, tt.args.bucketName, tt.args.namespace, progressCh)
+			tablesCount, err := s3Tables.deleteNamespace(tt.args.ctx, tt.args.bucketArn, tt.args.bucketName, tt.args.namespace, tt.args.dryRun, nil, nil, progressCh)
 			close(progressCh)
 			wg.Wait()
 
@@ -698,6 +1130,9 @@ func TestS3TablesWrapper_deleteNamespace(t *testing.T) {
 			if deletedCount.Load() != int64(tt.want.deletedCount) {
 				t.Errorf("deletedCount = %d, want %d", deletedCount.Load(), tt.want.deletedCount)
 			}
+			if !tt.wantErr && tablesCount != int64(tt.want.deletedCount) {
+				t.Errorf("tablesCount = %d, want %d", tablesCount, tt.want.deletedCount)
+			}
 		})
 	}
 }
@@ -752,10 +1187,12 @@ func TestS3TablesWrapper_ListBucketNamesFilteredByKeyword(t *testing.T) {
 					{
 						BucketName:   "test1",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/test1",
+						Region:       "us-east-1",
 					},
 					{
 						BucketName:   "test2",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/test2",
+						Region:       "us-east-1",
 					},
 				},
 				err: nil,
@@ -792,14 +1229,17 @@ func TestS3TablesWrapper_ListBucketNamesFilteredByKeyword(t *testing.T) {
 					{
 						BucketName:   "test1",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/test1",
+						Region:       "us-east-1",
 					},
 					{
 						BucketName:   "test2",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/test2",
+						Region:       "us-east-1",
 					},
 					{
 						BucketName:   "other",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/other",
+						Region:       "us-east-1",
 					},
 				},
 				err: nil,
@@ -909,6 +1349,7 @@ func TestS3TablesWrapper_ListBucketNamesFilteredByKeyword(t *testing.T) {
 					{
 						BucketName:   "test1",
 						TargetBucket: "arn:aws:s3:us-east-1:123456789012:table-bucket/test1",
+						Region:       "us-east-1",
 					},
 				},
 				err: nil,
@@ -1293,6 +1734,52 @@ func TestS3TablesWrapper_OutputCheckingMessage(t *testing.T) {
 	}
 }
 
+func TestS3TablesWrapper_OutputDryRunMessage(t *testing.T) {
+	io.NewLogger(false)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	io.Logger = &logger
+
+	tests := []struct {
+		name          string
+		bucket        string
+		output        *ClearBucketOutput
+		wantErr       bool
+		wantLogOutput string
+	}{
+		{
+			name:          "dry run result without bucket deletion",
+			bucket:        "arn:aws:s3:us-east-1:123456789012:table-bucket/test-bucket",
+			output:        &ClearBucketOutput{NamespacesCount: 2, TablesCount: 10},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 2 namespaces, 10 tables would be deleted."}`,
+		},
+		{
+			name:          "dry run result with bucket deletion",
+			bucket:        "arn:aws:s3:us-east-1:123456789012:table-bucket/test-bucket",
+			output:        &ClearBucketOutput{NamespacesCount: 0, TablesCount: 0, BucketDeleted: true},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 0 namespaces, 0 tables would be deleted."}` + "\n" + `{"level":"info","message":"test-bucket Dry run: the bucket would be deleted."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			s3Tables := NewS3TablesWrapper(nil)
+			err := s3Tables.OutputDryRunMessage(tt.bucket, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OutputDryRunMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := buf.String()[:len(buf.String())-1] // remove trailing newline
+			if got != tt.wantLogOutput {
+				t.Errorf("OutputDryRunMessage() log = %v, want %v", got, tt.wantLogOutput)
+			}
+		})
+	}
+}
+
 func TestS3TablesWrapper_GetLiveClearingMessage(t *testing.T) {
 	io.NewLogger(false)
 
@@ -32,10 +32,24 @@ func NewS3VectorsWrapper(client client.IS3Vectors) *S3VectorsWrapper {
 func (s *S3VectorsWrapper) ClearBucket(
 	ctx context.Context,
 	input ClearBucketInput,
-) error {
+) (*ClearBucketOutput, error) {
 	bucketName := input.TargetBucket
 
+	checkpoint := Checkpoint{}
+	if input.Targets == nil {
+		var err error
+		checkpoint, err = loadCheckpoint(input, input.TargetBucket)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var deletedIndexesCount atomic.Int64
+	deletedIndexesCount.Store(checkpoint.Counts.IndexesCount)
+	if !input.QuietMode && checkpoint.Counts.IndexesCount > 0 {
+		input.ClearingCountCh <- checkpoint.Counts.IndexesCount
+	}
+
 	progressCh := make(chan struct{})
 	wg := sync.WaitGroup{}
 	wg.Add(1)
@@ -49,40 +63,130 @@ func (s *S3VectorsWrapper) ClearBucket(
 		}
 	}()
 
+	// NOTE: When the targets are given, only they are deleted without listing, so indexes
+	// created after the targets were determined are not deleted.
+	var err error
+	if input.Targets != nil {
+		err = s.clearTargetIndexes(ctx, input, progressCh)
+	} else {
+		err = s.clearIndexes(ctx, input, newCheckpointTracker(input, input.TargetBucket, checkpoint), progressCh)
+	}
+	close(progressCh)
+	wg.Wait()
+	output := &ClearBucketOutput{
+		IndexesCount: deletedIndexesCount.Load(),
+	}
+	if err != nil {
+		return nil, partiallyCleared(input, output, err)
+	}
+	if err := deleteCheckpoint(input, input.TargetBucket); err != nil {
+		return nil, err
+	}
+
+	// NOTE: In the dry-run mode, the bucket is only reported as the one to be deleted,
+	// and the message is displayed as a summary of all buckets in the app.go.
+	if input.DryRun {
+		output.BucketDeleted = input.ForceMode
+		return output, nil
+	}
+
+	if input.QuietMode {
+		// When not in quiet mode, the message is displayed along with other buckets in the app.go.
+		if err := s.OutputClearedMessage(bucketName, output.IndexesCount); err != nil {
+			return nil, err
+		}
+	}
+
+	if !input.ForceMode {
+		return output, nil
+	}
+
+	if err := s.client.DeleteVectorBucket(ctx, aws.String(bucketName)); err != nil {
+		return nil, partiallyCleared(input, output, err)
+	}
+	output.BucketDeleted = true
+
+	// NOTE: When not in quiet mode, the message is displayed along with other buckets in the app.go.
+	if !input.QuietMode {
+		return output, nil
+	}
+
+	if err := s.OutputDeletedMessage(bucketName); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
+
+// clearIndexes lists all indexes in a bucket and deletes them
+func (s *S3VectorsWrapper) clearIndexes(ctx context.Context, input ClearBucketInput, tracker *checkpointTracker, progressCh chan<- struct{}) error {
+	bucketName := input.TargetBucket
 	eg := errgroup.Group{}
 	sem := semaphore.NewWeighted(S3VectorsSemaphoreWeight)
 	var nextToken *string
+	if tracker != nil {
+		if tracker.checkpoint.Completed {
+			return nil
+		}
+		nextToken = tracker.checkpoint.ContinuationToken
+	}
+	// NOTE: Only one key prefix is supported for S3Vectors.
+	var prefix *string
+	if len(input.Prefixes) > 0 {
+		prefix = aws.String(input.Prefixes[0])
+	}
 	for {
 		select {
 		case <-ctx.Done():
-			close(progressCh)
-			wg.Wait()
+			_ = eg.Wait()
 			return &client.ClientError{
 				ResourceName: aws.String(bucketName),
 				Err:          ctx.Err(),
 			}
 		default:
 		}
+		if err := interrupted(ctx, bucketName); err != nil {
+			_ = eg.Wait()
+			return err
+		}
 
 		output, err := s.client.ListIndexesByPage(
 			ctx,
 			aws.String(bucketName),
 			nextToken,
-			input.Prefix,
+			prefix,
 		)
 		if err != nil {
-			close(progressCh)
-			wg.Wait()
+			_ = eg.Wait()
 			return err
 		}
 		if len(output.Indexes) == 0 {
 			break
 		}
 
+		if input.DryRun && input.Recorder != nil {
+			targets := make([]Target, 0, len(output.Indexes))
+			for _, index := range output.Indexes {
+				targets = append(targets, Target{Index: aws.ToString(index.IndexName)})
+			}
+			if err := input.Recorder.RecordTargets(bucketName, targets); err != nil {
+				return err
+			}
+		}
+
+		page := tracker.addPage(Checkpoint{ContinuationToken: output.NextToken}, len(output.Indexes))
 		for _, index := range output.Indexes {
+			// NOTE: Nothing is deleted in the dry-run mode, so the listed indexes are only counted.
+			if input.DryRun {
+				progressCh <- struct{}{}
+				continue
+			}
+			if err := interrupted(ctx, bucketName); err != nil {
+				_ = eg.Wait()
+				return err
+			}
 			if err := sem.Acquire(ctx, 1); err != nil {
-				close(progressCh)
-				wg.Wait()
+				_ = eg.Wait()
 				return err
 			}
 			eg.Go(func() error {
@@ -91,7 +195,10 @@ func (s *S3VectorsWrapper) ClearBucket(
 					return err
 				}
 				progressCh <- struct{}{}
-				return nil
+				if err := recordDeleted(input.Manifest, bucketName, Target{Index: aws.ToString(index.IndexName)}); err != nil {
+					return err
+				}
+				return tracker.completePart(page, ClearBucketOutput{IndexesCount: 1})
 			})
 		}
 
@@ -101,40 +208,43 @@ func (s *S3VectorsWrapper) ClearBucket(
 		}
 	}
 
-	if err := eg.Wait(); err != nil {
-		close(progressCh)
-		wg.Wait()
-		return err
-	}
-	close(progressCh)
-	wg.Wait()
-
-	finalCount := deletedIndexesCount.Load()
-	if input.QuietMode {
-		// When not in quiet mode, the message is displayed along with other buckets in the app.go.
-		if err := s.OutputClearedMessage(bucketName, finalCount); err != nil {
-			return err
-		}
-	}
-
-	if !input.ForceMode {
-		return nil
-	}
+	return eg.Wait()
+}
 
-	if err := s.client.DeleteVectorBucket(ctx, aws.String(bucketName)); err != nil {
-		return err
-	}
+// clearTargetIndexes deletes the given indexes in a bucket
+func (s *S3VectorsWrapper) clearTargetIndexes(ctx context.Context, input ClearBucketInput, progressCh chan<- struct{}) error {
+	bucketName := input.TargetBucket
+	eg := errgroup.Group{}
+	sem := semaphore.NewWeighted(S3VectorsSemaphoreWeight)
 
-	// NOTE: When not in quiet mode, the message is displayed along with other buckets in the app.go.
-	if !input.QuietMode {
+	err := input.Targets.ForEachTargets(bucketName, func(targets []Target) error {
+		for _, target := range targets {
+			if err := interrupted(ctx, bucketName); err != nil {
+				return err
+			}
+			if err := sem.Acquire(ctx, 1); err != nil {
+				return &client.ClientError{
+					ResourceName: aws.String(bucketName),
+					Err:          err,
+				}
+			}
+			eg.Go(func() error {
+				defer sem.Release(1)
+				if err := s.client.DeleteIndex(ctx, aws.String(target.Index), aws.String(bucketName)); err != nil {
+					return err
+				}
+				progressCh <- struct{}{}
+				return recordDeleted(input.Manifest, bucketName, Target{Index: target.Index})
+			})
+		}
 		return nil
-	}
-
-	if err := s.OutputDeletedMessage(bucketName); err != nil {
+	})
+	if err != nil {
+		_ = eg.Wait()
 		return err
 	}
 
-	return nil
+	return eg.Wait()
 }
 
 func (s *S3VectorsWrapper) OutputClearedMessage(bucket string, count int64) error {
@@ -156,6 +266,14 @@ func (s *S3VectorsWrapper) OutputCheckingMessage(bucket string) error {
 	return nil
 }
 
+func (s *S3VectorsWrapper) OutputDryRunMessage(bucket string, output *ClearBucketOutput) error {
+	io.Logger.Info().Msgf("%v Dry run: %v indexes would be deleted.", bucket, output.IndexesCount)
+	if output.BucketDeleted {
+		io.Logger.Info().Msgf("%v Dry run: the bucket would be deleted.", bucket)
+	}
+	return nil
+}
+
 func (s *S3VectorsWrapper) GetLiveClearingMessage(bucket string, count int64) (string, error) {
 	return fmt.Sprintf("%v Clearing... %v indexes", bucket, count), nil
 }
@@ -181,6 +299,8 @@ func (s *S3VectorsWrapper) ListBucketNamesFilteredByKeyword(ctx context.Context,
 			filteredBuckets = append(filteredBuckets, ListBucketNamesFilteredByKeywordOutput{
 				BucketName:   *bucket.VectorBucketName,
 				TargetBucket: *bucket.VectorBucketName,
+				CreationDate: bucket.CreationTime,
+				Region:       regionFromArn(aws.ToString(bucket.VectorBucketArn)),
 			})
 		}
 	}
@@ -195,6 +315,14 @@ func (s *S3VectorsWrapper) ListBucketNamesFilteredByKeyword(ctx context.Context,
 	return filteredBuckets, nil
 }
 
+// GetBucketTags returns an error because the tags of Vector Buckets cannot be read.
+func (s *S3VectorsWrapper) GetBucketTags(ctx context.Context, bucket ListBucketNamesFilteredByKeywordOutput) (map[string]string, error) {
+	return nil, &client.ClientError{
+		ResourceName: aws.String(bucket.BucketName),
+		Err:          fmt.Errorf("NotSupportedError: The tags of Vector Buckets cannot be read."),
+	}
+}
+
 func (s *S3VectorsWrapper) CheckAllBucketsExist(ctx context.Context, bucketNames []string) ([]string, error) {
 	targetBucketArns := []string{}
 	nonExistingBucketNames := []string{}
@@ -27,15 +27,20 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 		bucketName string
 		forceMode  bool
 		quietMode  bool
-		prefix     *string
+		prefixes   []string
+		dryRun     bool
 	}
 
 	cases := []struct {
-		name          string
-		args          args
-		prepareMockFn func(m *client.MockIS3Vectors)
-		want          error
-		wantErr       bool
+		name                    string
+		args                    args
+		prepareMockFn           func(m *client.MockIS3Vectors)
+		prepareTargetMockFn     func(r *MockITargetRecorder, s *MockITargetSource) // set the recorder and the source only if not nil
+		prepareCheckpointMockFn func(c *MockICheckpointer)                         // set the checkpointer only if not nil
+		prepareManifestMockFn   func(r *MockIDeletionRecorder)                     // set the manifest only if not nil
+		want                    error
+		wantOutput              *ClearBucketOutput
+		wantErr                 bool
 	}{
 		{
 			name: "clear indexes successfully",
@@ -44,7 +49,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -89,7 +94,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  true,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -125,7 +130,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  true,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -159,6 +164,144 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "list indexes without deleting them and the bucket in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  true,
+				quietMode:  true,
+				prefixes:   nil,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					nil,
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes: []types.IndexSummary{
+							{
+								IndexName: aws.String("index1"),
+							},
+							{
+								IndexName: aws.String("index2"),
+							},
+						},
+						NextToken: nil,
+					},
+					nil,
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount:  2,
+				BucketDeleted: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "record listed indexes in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  true,
+				prefixes:   nil,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					nil,
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes: []types.IndexSummary{
+							{
+								IndexName: aws.String("index1"),
+							},
+							{
+								IndexName: aws.String("index2"),
+							},
+						},
+						NextToken: nil,
+					},
+					nil,
+				)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				r.EXPECT().RecordTargets("test-vector-bucket", []Target{{Index: "index1"}, {Index: "index2"}}).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete only given indexes and the bucket without listing",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  true,
+				quietMode:  false,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().DeleteIndex(
+					gomock.Any(),
+					aws.String("index1"),
+					aws.String("test-vector-bucket"),
+				).Return(nil)
+				m.EXPECT().DeleteVectorBucket(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+				).Return(nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test-vector-bucket", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn([]Target{{Index: "index1"}})
+					},
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount:  1,
+				BucketDeleted: true,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete given indexes failure for delete index errors",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  true,
+				quietMode:  false,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().DeleteIndex(
+					gomock.Any(),
+					aws.String("index1"),
+					aws.String("test-vector-bucket"),
+				).Return(fmt.Errorf("DeleteIndexError"))
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test-vector-bucket", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn([]Target{{Index: "index1"}})
+					},
+				)
+			},
+			want:    fmt.Errorf("DeleteIndexError"),
+			wantErr: true,
+		},
 		{
 			name: "list indexes failure",
 			args: args{
@@ -166,7 +309,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -186,7 +329,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -222,7 +365,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  true,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -263,7 +406,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  false,
-				prefix:     aws.String("test-"),
+				prefixes:   []string{"test-"},
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				m.EXPECT().ListIndexesByPage(
@@ -308,7 +451,7 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				bucketName: "test-vector-bucket",
 				forceMode:  false,
 				quietMode:  false,
-				prefix:     nil,
+				prefixes:   nil,
 			},
 			prepareMockFn: func(m *client.MockIS3Vectors) {
 				// First page
@@ -371,6 +514,188 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "record deleted indexes to the manifest",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  true,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					nil,
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes: []types.IndexSummary{
+							{
+								IndexName: aws.String("index1"),
+							},
+							{
+								IndexName: aws.String("index2"),
+							},
+						},
+						NextToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteIndex(gomock.Any(), aws.String("index1"), aws.String("test-vector-bucket")).Return(nil)
+				m.EXPECT().DeleteIndex(gomock.Any(), aws.String("index2"), aws.String("test-vector-bucket")).Return(nil)
+			},
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				r.EXPECT().RecordDeleted("test-vector-bucket", []Target{{Index: "index1"}}).Return(nil)
+				r.EXPECT().RecordDeleted("test-vector-bucket", []Target{{Index: "index2"}}).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount: 2,
+			},
+			wantErr: false,
+		},
+		{
+			name: "record deleted indexes failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  true,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					nil,
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes: []types.IndexSummary{
+							{
+								IndexName: aws.String("index1"),
+							},
+						},
+						NextToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteIndex(gomock.Any(), aws.String("index1"), aws.String("test-vector-bucket")).Return(nil)
+			},
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				r.EXPECT().RecordDeleted("test-vector-bucket", []Target{{Index: "index1"}}).Return(fmt.Errorf("ManifestFileError"))
+			},
+			want:    fmt.Errorf("ManifestFileError"),
+			wantErr: true,
+		},
+		{
+			name: "resume clearing indexes from the checkpoint",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  false,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					aws.String("token"),
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes: []types.IndexSummary{
+							{
+								IndexName: aws.String("index3"),
+							},
+						},
+						NextToken: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteIndex(
+					gomock.Any(),
+					aws.String("index3"),
+					aws.String("test-vector-bucket"),
+				).Return(nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				gomock.InOrder(
+					c.EXPECT().Load("test-vector-bucket").Return(Checkpoint{
+						ContinuationToken: aws.String("token"),
+						Counts:            ClearBucketOutput{IndexesCount: 2},
+					}, true, nil),
+					c.EXPECT().Save("test-vector-bucket", Checkpoint{
+						Counts:    ClearBucketOutput{IndexesCount: 3},
+						Completed: true,
+					}).Return(nil),
+					c.EXPECT().Delete("test-vector-bucket").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "resume clearing indexes from the completed checkpoint without counting them again",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  false,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				gomock.InOrder(
+					c.EXPECT().Load("test-vector-bucket").Return(Checkpoint{
+						Counts:    ClearBucketOutput{IndexesCount: 3},
+						Completed: true,
+					}, true, nil),
+					c.EXPECT().Delete("test-vector-bucket").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				IndexesCount: 3,
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete checkpoint failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test-vector-bucket",
+				forceMode:  false,
+				quietMode:  true,
+				prefixes:   nil,
+			},
+			prepareMockFn: func(m *client.MockIS3Vectors) {
+				m.EXPECT().ListIndexesByPage(
+					gomock.Any(),
+					aws.String("test-vector-bucket"),
+					nil,
+					(*string)(nil),
+				).Return(
+					&client.ListIndexesByPageOutput{
+						Indexes:   []types.IndexSummary{},
+						NextToken: nil,
+					},
+					nil,
+				)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("test-vector-bucket").Return(Checkpoint{}, false, nil)
+				c.EXPECT().Delete("test-vector-bucket").Return(fmt.Errorf("DeleteError"))
+			},
+			want:    fmt.Errorf("DeleteError"),
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range cases {
@@ -389,13 +714,36 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				}()
 			}
 
-			err := s3Vectors.ClearBucket(tt.args.ctx, ClearBucketInput{
+			input := ClearBucketInput{
 				TargetBucket:    tt.args.bucketName,
 				ForceMode:       tt.args.forceMode,
 				QuietMode:       tt.args.quietMode,
-				Prefix:          tt.args.prefix,
+				Prefixes:        tt.args.prefixes,
 				ClearingCountCh: clearingCountCh,
-			})
+				DryRun:          tt.args.dryRun,
+			}
+			if tt.prepareTargetMockFn != nil {
+				recorderMock := NewMockITargetRecorder(ctrl)
+				sourceMock := NewMockITargetSource(ctrl)
+				tt.prepareTargetMockFn(recorderMock, sourceMock)
+				if tt.args.dryRun {
+					input.Recorder = recorderMock
+				} else {
+					input.Targets = sourceMock
+				}
+			}
+			if tt.prepareCheckpointMockFn != nil {
+				checkpointerMock := NewMockICheckpointer(ctrl)
+				tt.prepareCheckpointMockFn(checkpointerMock)
+				input.Checkpointer = checkpointerMock
+			}
+			if tt.prepareManifestMockFn != nil {
+				manifestMock := NewMockIDeletionRecorder(ctrl)
+				tt.prepareManifestMockFn(manifestMock)
+				input.Manifest = manifestMock
+			}
+
+			output, err := s3Vectors.ClearBucket(tt.args.ctx, input)
 
 			close(clearingCountCh)
 
@@ -407,6 +755,9 @@ func TestS3VectorsWrapper_ClearBucket(t *testing.T) {
 				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.Error())
 				return
 			}
+			if tt.wantOutput != nil && !reflect.DeepEqual(output, tt.wantOutput) {
+				t.Errorf("output = %#v, want %#v", output, tt.wantOutput)
+			}
 		})
 	}
 }
@@ -940,6 +1291,52 @@ func TestS3VectorsWrapper_OutputCheckingMessage(t *testing.T) {
 	}
 }
 
+func TestS3VectorsWrapper_OutputDryRunMessage(t *testing.T) {
+	io.NewLogger(false)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	io.Logger = &logger
+
+	tests := []struct {
+		name          string
+		bucket        string
+		output        *ClearBucketOutput
+		wantErr       bool
+		wantLogOutput string
+	}{
+		{
+			name:          "dry run result without bucket deletion",
+			bucket:        "test-bucket",
+			output:        &ClearBucketOutput{IndexesCount: 10},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 10 indexes would be deleted."}`,
+		},
+		{
+			name:          "dry run result with bucket deletion",
+			bucket:        "test-bucket",
+			output:        &ClearBucketOutput{IndexesCount: 0, BucketDeleted: true},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 0 indexes would be deleted."}` + "\n" + `{"level":"info","message":"test-bucket Dry run: the bucket would be deleted."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			s3Vectors := NewS3VectorsWrapper(nil)
+			err := s3Vectors.OutputDryRunMessage(tt.bucket, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OutputDryRunMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := buf.String()[:len(buf.String())-1] // remove trailing newline
+			if got != tt.wantLogOutput {
+				t.Errorf("OutputDryRunMessage() log = %v, want %v", got, tt.wantLogOutput)
+			}
+		})
+	}
+}
+
 func TestS3VectorsWrapper_GetLiveClearingMessage(t *testing.T) {
 	io.NewLogger(false)
 
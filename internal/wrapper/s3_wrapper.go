@@ -2,16 +2,23 @@ package wrapper
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/go-to-k/cls3/internal/interrupt"
 	"github.com/go-to-k/cls3/internal/io"
 	"github.com/go-to-k/cls3/pkg/client"
 	"golang.org/x/sync/errgroup"
 )
 
+// The delimiter to discover the top-level prefixes to split the keys into partitions
+const S3PartitionDelimiter = "/"
+
 var _ IWrapper = (*S3Wrapper)(nil)
 
 type S3Wrapper struct {
@@ -25,93 +32,343 @@ func NewS3Wrapper(client client.IS3) *S3Wrapper {
 }
 
 type objectDeletionState struct {
-	errorStr        string
-	errorsCount     int
-	errorsMtx       sync.Mutex
-	objectsCount    int64
-	objectsCountMtx sync.Mutex
+	errors             []types.Error
+	errorsMtx          sync.Mutex
+	objectsCount       int64
+	latestCount        int64
+	versionsCount      int64
+	deleteMarkersCount int64
+	objectsCountMtx    sync.Mutex
+	deleteWorkers      *deleteWorkers // deletes the listed pages of all partitions of the bucket
+}
+
+// restoreCounts restores the numbers of objects deleted before a checkpoint
+func (st *objectDeletionState) restoreCounts(counts ClearBucketOutput) {
+	st.latestCount += counts.ObjectsCount
+	st.versionsCount += counts.VersionsCount
+	st.deleteMarkersCount += counts.DeleteMarkersCount
+	st.objectsCount += counts.ObjectsCount + counts.VersionsCount + counts.DeleteMarkersCount
+}
+
+// resetErrors drops the errors of the objects in a partition to list them again
+func (st *objectDeletionState) resetErrors(part partition) {
+	st.errorsMtx.Lock()
+	defer st.errorsMtx.Unlock()
+	st.errors = slices.DeleteFunc(st.errors, func(e types.Error) bool {
+		return part.contains(aws.ToString(e.Key))
+	})
+}
+
+// partition is a part of the keys of a bucket that is listed and deleted by one loop in parallel with the others:
+// the keys under a prefix (or all keys if nil) in a key range (or all of them if nil)
+type partition struct {
+	prefix   *string
+	keyRange *client.KeyRange
+}
+
+func (p partition) contains(key string) bool {
+	if !strings.HasPrefix(key, aws.ToString(p.prefix)) {
+		return false
+	}
+	if p.keyRange == nil {
+		return true
+	}
+	return (p.keyRange.StartAfter == nil || key > *p.keyRange.StartAfter) &&
+		(p.keyRange.LastKey == nil || key <= *p.keyRange.LastKey)
+}
+
+// keyPrefixes returns the key prefixes to be listed, or only nil to list all keys
+func keyPrefixes(input ClearBucketInput) []*string {
+	if len(input.Prefixes) == 0 {
+		return []*string{nil}
+	}
+	prefixes := make([]*string, 0, len(input.Prefixes))
+	for _, prefix := range input.Prefixes {
+		prefixes = append(prefixes, aws.String(prefix))
+	}
+	return prefixes
+}
+
+// checkpointKey returns the key of the checkpoint for a partition of a bucket.
+// When several prefixes or key ranges are listed, each of them has its own checkpoint because they are cleared in parallel.
+func checkpointKey(input ClearBucketInput, part partition) string {
+	key := input.TargetBucket
+	if len(input.Prefixes) > 1 {
+		key += "/" + aws.ToString(part.prefix)
+	}
+	if part.keyRange != nil && part.keyRange.StartAfter != nil {
+		key += "@" + *part.keyRange.StartAfter
+	}
+	return key
+}
+
+// partitions splits the keys under each prefix into the key ranges to be listed in parallel.
+// The keys up to a split point (inclusive) belong to the range before it. The split points are given, or
+// chosen evenly from the top-level prefixes under each prefix if the number of partitions is given.
+func (s *S3Wrapper) partitions(ctx context.Context, input ClearBucketInput, bucketRegion string) ([]partition, error) {
+	parts := []partition{}
+	for _, prefix := range keyPrefixes(input) {
+		splitPoints := []string{}
+		if len(input.SplitPoints) > 0 {
+			for _, point := range input.SplitPoints {
+				if strings.HasPrefix(point, aws.ToString(prefix)) {
+					splitPoints = append(splitPoints, point)
+				}
+			}
+		} else if input.Partitions > 1 {
+			commonPrefixes, err := s.client.ListCommonPrefixes(ctx, aws.String(input.TargetBucket), bucketRegion, prefix, S3PartitionDelimiter)
+			if err != nil {
+				return nil, err
+			}
+			splitPoints = chooseSplitPoints(commonPrefixes, input.Partitions)
+			io.Logger.Debug().Msgf("%s: Split %q into %d partitions by %d prefixes", input.TargetBucket, aws.ToString(prefix), len(splitPoints)+1, len(commonPrefixes))
+		}
+
+		if len(splitPoints) == 0 {
+			parts = append(parts, partition{prefix: prefix})
+			continue
+		}
+		var startAfter *string
+		for _, point := range splitPoints {
+			parts = append(parts, partition{
+				prefix: prefix,
+				keyRange: &client.KeyRange{
+					StartAfter: startAfter,
+					LastKey:    aws.String(point),
+				},
+			})
+			startAfter = aws.String(point)
+		}
+		parts = append(parts, partition{
+			prefix: prefix,
+			keyRange: &client.KeyRange{
+				StartAfter: startAfter,
+			},
+		})
+	}
+	return parts, nil
+}
+
+// chooseSplitPoints returns up to partitions-1 prefixes evenly spaced in the sorted prefixes, so that each range
+// has about the same number of the prefixes. The first prefix is not chosen because no prefix would be before it.
+func chooseSplitPoints(prefixes []string, partitions int) []string {
+	splitPoints := []string{}
+	for i := 1; i < partitions; i++ {
+		index := i * len(prefixes) / partitions
+		if index == 0 || (len(splitPoints) > 0 && splitPoints[len(splitPoints)-1] == prefixes[index]) {
+			continue
+		}
+		splitPoints = append(splitPoints, prefixes[index])
+	}
+	return splitPoints
+}
+
+// addCounts adds the numbers of a listed page and returns the total number of objects
+func (st *objectDeletionState) addCounts(output *client.ListObjectsOrVersionsByPageOutput) int64 {
+	st.objectsCount += int64(len(output.ObjectIdentifiers))
+	st.latestCount += int64(output.ObjectsCount)
+	st.versionsCount += int64(output.VersionsCount)
+	st.deleteMarkersCount += int64(output.DeleteMarkersCount)
+	return st.objectsCount
 }
 
 func (s *S3Wrapper) ClearBucket(
 	ctx context.Context,
 	input ClearBucketInput,
-) error {
+) (*ClearBucketOutput, error) {
 	// NOTE: This `bucketRegion` allows buckets outside the specified region to be deleted.
 	// If the `directoryBucketsMode` is true, bucketRegion is empty because only one region's
 	// buckets can be operated on.
 	bucketRegion, err := s.client.GetBucketLocation(ctx, aws.String(input.TargetBucket))
 	if err != nil {
-		return err
+		return nil, err
 	}
 
-	if err := s.clearObjects(ctx, input, bucketRegion); err != nil {
-		return err
+	parts, err := s.partitions(ctx, input, bucketRegion)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.clearObjects(ctx, input, bucketRegion, parts)
+	if err != nil {
+		return nil, err
+	}
+	output.Region = bucketRegion
+	for _, part := range parts {
+		if err := deleteCheckpoint(input, checkpointKey(input, part)); err != nil {
+			return nil, err
+		}
 	}
 
 	if !input.ForceMode {
-		return nil
+		return output, nil
 	}
 
-	if err := s.deleteBucket(ctx, input.TargetBucket, bucketRegion, input.QuietMode); err != nil {
-		return err
+	// NOTE: In the dry-run mode, the bucket is only reported as the one to be deleted.
+	if !input.DryRun {
+		if err := s.deleteBucket(ctx, input.TargetBucket, bucketRegion, input.QuietMode); err != nil {
+			return nil, partiallyCleared(input, output, err)
+		}
 	}
+	output.BucketDeleted = true
 
-	return nil
+	return output, nil
 }
 
-func (s *S3Wrapper) clearObjects(ctx context.Context, input ClearBucketInput, bucketRegion string) error {
+func (s *S3Wrapper) clearObjects(ctx context.Context, input ClearBucketInput, bucketRegion string, parts []partition) (*ClearBucketOutput, error) {
 	state := &objectDeletionState{}
 
+	checkpoints := make([]Checkpoint, len(parts))
+	if input.Targets == nil {
+		for i, part := range parts {
+			checkpoint, err := loadCheckpoint(input, checkpointKey(input, part))
+			if err != nil {
+				return nil, err
+			}
+			checkpoints[i] = checkpoint
+			state.restoreCounts(checkpoint.Counts)
+		}
+	}
+
 	if !input.QuietMode {
-		// NOTE: Send 0 to the channel to indicate that the clearing has started.
-		input.ClearingCountCh <- 0
+		// NOTE: Send the count to the channel to indicate that the clearing has started.
+		// It is 0 unless the clearing is resumed from a checkpoint.
+		input.ClearingCountCh <- state.objectsCount
 	}
 
-	// NOTE: Try clearing objects up to 2 times to handle eventual consistency
-	// There was a case where the object deletion was completed but the object was still there.
-	// So, even if all objects are deleted, it is not guaranteed that the object is deleted.
-	// Therefore, we try to delete the objects again.
-	maxAttempts := 2
-	for attempt := 0; attempt < maxAttempts; attempt++ {
-		done, err := s.processObjectDeletionAttempt(ctx, input, bucketRegion, state, attempt)
-		if err != nil {
-			return err
-		}
-		if done {
-			break
+	var err error
+	if input.Targets != nil {
+		// NOTE: When the targets are given, only they are deleted without listing, so objects
+		// put after the targets were determined are not deleted.
+		err = s.processTargetDeletion(ctx, input, bucketRegion, state)
+	} else {
+		// NOTE: The partitions are listed in parallel. They do not overlap each other (the app drops
+		// the prefixes under another prefix), so the objects of a partition are only listed for the partition.
+		// All of them send the total count to the same channel, so the progress is still shown in one line.
+		eg, egCtx := errgroup.WithContext(ctx)
+		state.deleteWorkers = newDeleteWorkers(egCtx, input.DeleteWorkers)
+		for i, part := range parts {
+			eg.Go(func() error {
+				return s.processObjectDeletion(egCtx, input, part, bucketRegion, state, checkpoints[i])
+			})
 		}
+		err = eg.Wait()
+		state.deleteWorkers.close()
+	}
+	if errors.Is(err, interrupt.ErrInterrupted) {
+		// NOTE: The deletions started before the interruption have finished, so the objects counted
+		// by them have been deleted except for the ones with errors.
+		return nil, partiallyCleared(input, &ClearBucketOutput{ObjectsCount: state.objectsCount - int64(len(state.errors))}, err)
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	if state.errorsCount > 0 {
-		state.objectsCount -= int64(state.errorsCount)
+	if len(state.errors) > 0 {
+		state.objectsCount -= int64(len(state.errors))
 		if !input.QuietMode {
 			input.ClearingCountCh <- state.objectsCount
 		}
 
+		if input.Manifest != nil {
+			if err := input.Manifest.RecordFailed(input.TargetBucket, toFailedTargets(state.errors)); err != nil {
+				return nil, err
+			}
+		}
+
 		// NOTE: The error is from `DeleteObjectsOutput.Errors`, not `err`.
 		// However, we want to treat it as an error, so we use `client.ClientError`.
-		return &client.ClientError{
+		// The kinds of the failed objects are not known, so all deleted ones are counted as objects.
+		return nil, partiallyCleared(input, &ClearBucketOutput{ObjectsCount: state.objectsCount}, &client.ClientError{
 			ResourceName: aws.String(input.TargetBucket),
-			Err:          fmt.Errorf("DeleteObjectsError: %v objects with errors were found. %v", state.errorsCount, state.errorStr),
-		}
+			Err:          &client.DeleteObjectsError{Errors: state.errors},
+		})
+	}
+
+	output := &ClearBucketOutput{
+		ObjectsCount:       state.latestCount,
+		VersionsCount:      state.versionsCount,
+		DeleteMarkersCount: state.deleteMarkersCount,
 	}
 
 	// NOTE: When not in quiet mode, the message is displayed along with other buckets in the app.go.
-	if !input.QuietMode {
-		return nil
+	// In the dry-run mode, the message is displayed as a summary of all buckets in the app.go.
+	if !input.QuietMode || input.DryRun {
+		return output, nil
 	}
 
 	if err := s.OutputClearedMessage(input.TargetBucket, state.objectsCount); err != nil {
-		return err
+		return nil, err
 	}
 
+	return output, nil
+}
+
+// processObjectDeletion lists and deletes the objects in a partition
+func (s *S3Wrapper) processObjectDeletion(
+	ctx context.Context,
+	input ClearBucketInput,
+	part partition,
+	bucketRegion string,
+	state *objectDeletionState,
+	checkpoint Checkpoint,
+) error {
+	// NOTE: Try clearing objects up to 2 times to handle eventual consistency
+	// There was a case where the object deletion was completed but the object was still there.
+	// So, even if all objects are deleted, it is not guaranteed that the object is deleted.
+	// Therefore, we try to delete the objects again.
+	// The checkpoints are only for the first attempt, and the retry lists the objects from the beginning.
+	maxAttempts := 2
+
+	// NOTE: A resumed clearing uses the times of the filter when the clearing started instead of the ones resolved
+	// from the options again (e.g. 7d), so that it deletes the same objects as before it stopped.
+	if checkpoint.FilterTimes != nil {
+		input.Filter = input.Filter.WithTimes(checkpoint.FilterTimes)
+	} else {
+		checkpoint.FilterTimes = input.Filter.Times()
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		var tracker *checkpointTracker
+		if attempt == 0 {
+			// NOTE: The listing of a completed checkpoint is not resumed, and the retry checks the objects left.
+			if checkpoint.Completed {
+				continue
+			}
+			tracker = newCheckpointTracker(input, checkpointKey(input, part), checkpoint)
+		}
+		done, err := s.processObjectDeletionAttempt(ctx, input, part, bucketRegion, state, attempt, tracker)
+		if err != nil {
+			return err
+		}
+		if done {
+			break
+		}
+	}
 	return nil
 }
 
-func (s *S3Wrapper) processObjectDeletionAttempt(ctx context.Context, input ClearBucketInput, bucketRegion string, state *objectDeletionState, attempt int) (bool, error) {
-	eg := errgroup.Group{}
+func (s *S3Wrapper) processObjectDeletionAttempt(
+	ctx context.Context,
+	input ClearBucketInput,
+	part partition,
+	bucketRegion string,
+	state *objectDeletionState,
+	attempt int,
+	tracker *checkpointTracker,
+) (bool, error) {
+	var pending sync.WaitGroup // deletions of this attempt sent to the delete workers
+	// NOTE: Wait for the deletions also when the listing fails, so that no checkpoint is saved after returning.
+	defer pending.Wait()
 	var keyMarker *string
 	var versionIdMarker *string
+	var versionsCursor *client.VersionsCursor
+	listed := false // whether any objects have been listed in this attempt
+	if tracker != nil {
+		keyMarker = tracker.checkpoint.KeyMarker
+		versionIdMarker = tracker.checkpoint.VersionIdMarker
+		versionsCursor = tracker.checkpoint.VersionsCursor
+	}
+	fromBeginning := keyMarker == nil && versionIdMarker == nil
 
 	for {
 		select {
@@ -122,6 +379,10 @@ func (s *S3Wrapper) processObjectDeletionAttempt(ctx context.Context, input Clea
 			}
 		default:
 		}
+		// NOTE: When interrupted, the listing stops here, and the pages already listed are still deleted.
+		if err := interrupted(ctx, input.TargetBucket); err != nil {
+			return false, err
+		}
 
 		// NOTE: ListObjectVersions/ListObjectsV2 API can only retrieve up to 1000 items, so it is good to pass it
 		// directly to DeleteObjects, which can only delete up to 1000 items.
@@ -132,75 +393,225 @@ func (s *S3Wrapper) processObjectDeletionAttempt(ctx context.Context, input Clea
 			input.OldVersionsOnly,
 			keyMarker,
 			versionIdMarker,
-			input.Prefix,
+			part.prefix,
+			input.Filter,
+			versionsCursor,
+			part.keyRange,
 		)
 		if err != nil {
 			return false, err
 		}
 
-		isFirstPage := keyMarker == nil && versionIdMarker == nil
+		isLastPage := output.NextKeyMarker == nil && output.NextVersionIdMarker == nil
 		if len(output.ObjectIdentifiers) == 0 {
-			// If no objects found in the first page of a new attempt, we're done
-			if isFirstPage {
+			// If no objects found in a new attempt from the beginning, we're done
+			if !listed && isLastPage && fromBeginning {
 				return true, nil
 			}
-			break
-		} else if isFirstPage && attempt > 0 {
-			state.errorStr = ""
-			state.errorsCount = 0
+			// NOTE: All objects in a page can be filtered out (e.g. by -o or the time filters),
+			// so the listing goes on to the next page.
+			if isLastPage {
+				break
+			}
+			keyMarker = output.NextKeyMarker
+			versionIdMarker = output.NextVersionIdMarker
+			versionsCursor = output.VersionsCursor
+			continue
+		} else if !listed && attempt > 0 {
+			state.resetErrors(part)
 			io.Logger.Debug().Msgf("%s: Retry attempt %d", input.TargetBucket, attempt)
 		}
+		listed = true
+
+		if input.DryRun {
+			// NOTE: Nothing is deleted in the dry-run mode, so the listed objects are only counted.
+			state.objectsCountMtx.Lock()
+			state.addCounts(output)
+			state.objectsCountMtx.Unlock()
+			if input.Recorder != nil {
+				if err := input.Recorder.RecordTargets(input.TargetBucket, toTargets(output)); err != nil {
+					return false, err
+				}
+			}
+		} else {
+			// NOTE: The page is sent to the delete workers, and the listing goes on while they delete it.
+			// If all workers are busy and the channel is full, the listing waits for them (backpressure),
+			// so the listed pages held in memory are bounded even when the listing is faster than the deletion.
+			page := tracker.addPage(Checkpoint{
+				KeyMarker:       output.NextKeyMarker,
+				VersionIdMarker: output.NextVersionIdMarker,
+				VersionsCursor:  output.VersionsCursor,
+			}, 1)
+			pending.Add(1)
+			sent := state.deleteWorkers.submit(deleteJob{done: pending.Done, fn: func(ctx context.Context) error {
+				// NOTE: This loop with the `attempt` variable is a retry process for the bug where DeleteObjects
+				// was executed but objects were not deleted.
+				// Therefore, it is not counted in the number of deletions if it is not the first attempt.
+				if attempt == 0 {
+					state.objectsCountMtx.Lock()
+					count := state.addCounts(output)
+					if !input.QuietMode {
+						input.ClearingCountCh <- count
+					}
+					state.objectsCountMtx.Unlock()
+				}
+
+				if err := s.deleteObjects(ctx, input, toTargets(output), bucketRegion, state); err != nil {
+					return err
+				}
+
+				return tracker.completePart(page, ClearBucketOutput{
+					ObjectsCount:       int64(output.ObjectsCount),
+					VersionsCount:      int64(output.VersionsCount),
+					DeleteMarkersCount: int64(output.DeleteMarkersCount),
+				})
+			}})
+			if !sent {
+				pending.Done()
+				break
+			}
+		}
+
+		keyMarker = output.NextKeyMarker
+		versionIdMarker = output.NextVersionIdMarker
+		versionsCursor = output.VersionsCursor
 
-		eg.Go(func() error {
-			// NOTE: This loop with the `attempt` variable is a retry process for the bug where DeleteObjects
-			// was executed but objects were not deleted.
-			// Therefore, it is not counted in the number of deletions if it is not the first attempt.
-			if attempt == 0 {
+		if keyMarker == nil && versionIdMarker == nil {
+			break
+		}
+	}
+
+	pending.Wait()
+	if err := state.deleteWorkers.stoppedErr(input.TargetBucket); err != nil {
+		return false, err
+	}
+
+	// NOTE: In the dry-run mode, one listing is enough because nothing is deleted.
+	return input.DryRun, nil
+}
+
+func (s *S3Wrapper) processTargetDeletion(ctx context.Context, input ClearBucketInput, bucketRegion string, state *objectDeletionState) error {
+	// NOTE: The given targets are not paced by the listing, so they are always deleted by the delete workers.
+	workers := newDeleteWorkers(ctx, input.DeleteWorkers)
+
+	err := input.Targets.ForEachTargets(input.TargetBucket, func(targets []Target) error {
+		for chunk := range slices.Chunk(targets, client.MaxDeleteObjectsCount) {
+			sent := workers.submit(deleteJob{fn: func(ctx context.Context) error {
 				state.objectsCountMtx.Lock()
-				state.objectsCount += int64(len(output.ObjectIdentifiers))
+				state.objectsCount += int64(len(chunk))
+				state.latestCount += int64(len(chunk))
 				if !input.QuietMode {
 					input.ClearingCountCh <- state.objectsCount
 				}
 				state.objectsCountMtx.Unlock()
-			}
-
-			// NOTE: One DeleteObjects is executed for each loop of the List, and it usually ends during
-			// the next loop. Therefore, there seems to be no throttling concern, so the number of
-			// parallels is not limited by semaphore. (Throttling occurs at about 3500 deletions
-			// per second.)
-			gotErrors, err := s.client.DeleteObjects(ctx, aws.String(input.TargetBucket), output.ObjectIdentifiers, bucketRegion)
-			if err != nil {
-				return err
-			}
 
-			if len(gotErrors) > 0 {
-				state.errorsMtx.Lock()
-				state.errorsCount += len(gotErrors)
-				for _, error := range gotErrors {
-					state.errorStr += fmt.Sprintf("\nCode: %v\n", *error.Code)
-					state.errorStr += fmt.Sprintf("Key: %v\n", *error.Key)
-					state.errorStr += fmt.Sprintf("VersionId: %v\n", *error.VersionId)
-					state.errorStr += fmt.Sprintf("Message: %v\n", *error.Message)
+				// NOTE: Nothing is deleted in the dry-run mode, so the given targets are only counted.
+				if input.DryRun {
+					if input.Recorder != nil {
+						return input.Recorder.RecordTargets(input.TargetBucket, chunk)
+					}
+					return nil
 				}
-				state.errorsMtx.Unlock()
+				return s.deleteObjects(ctx, input, chunk, bucketRegion, state)
+			}})
+			if !sent {
+				return workers.stoppedErr(input.TargetBucket)
 			}
+		}
+		return nil
+	})
+	workers.close()
+	if err != nil {
+		return err
+	}
+	return workers.stoppedErr(input.TargetBucket)
+}
+
+func (s *S3Wrapper) deleteObjects(ctx context.Context, input ClearBucketInput, targets []Target, bucketRegion string, state *objectDeletionState) error {
+	gotErrors, err := s.client.DeleteObjects(ctx, aws.String(input.TargetBucket), toObjectIdentifiers(targets), bucketRegion)
+	if err != nil {
+		return err
+	}
 
-			return nil
+	if len(gotErrors) > 0 {
+		state.errorsMtx.Lock()
+		state.errors = append(state.errors, gotErrors...)
+		state.errorsMtx.Unlock()
+	}
+
+	// NOTE: The objects that could not be deleted are recorded as failures when the clearing ends,
+	// because they may be deleted in the retry attempt.
+	if input.Manifest != nil {
+		if err := input.Manifest.RecordDeleted(input.TargetBucket, excludeFailedTargets(targets, gotErrors)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// toTargets converts a page of objects to targets. The delete markers are at the end of the page.
+func toTargets(output *client.ListObjectsOrVersionsByPageOutput) []Target {
+	targets := make([]Target, 0, len(output.ObjectIdentifiers))
+	deleteMarkersStart := len(output.ObjectIdentifiers) - output.DeleteMarkersCount
+	for i, object := range output.ObjectIdentifiers {
+		targets = append(targets, Target{
+			Key:          aws.ToString(object.Key),
+			VersionId:    aws.ToString(object.VersionId),
+			DeleteMarker: i >= deleteMarkersStart,
 		})
+	}
+	return targets
+}
 
-		keyMarker = output.NextKeyMarker
-		versionIdMarker = output.NextVersionIdMarker
+// excludeFailedTargets returns the targets other than the ones DeleteObjects failed to delete
+func excludeFailedTargets(targets []Target, errors []types.Error) []Target {
+	if len(errors) == 0 {
+		return targets
+	}
 
-		if keyMarker == nil && versionIdMarker == nil {
-			break
+	failed := make(map[Target]struct{}, len(errors))
+	for _, e := range errors {
+		failed[Target{Key: aws.ToString(e.Key), VersionId: aws.ToString(e.VersionId)}] = struct{}{}
+	}
+
+	deleted := make([]Target, 0, len(targets))
+	for _, target := range targets {
+		if _, ok := failed[Target{Key: target.Key, VersionId: target.VersionId}]; ok {
+			continue
 		}
+		deleted = append(deleted, target)
 	}
+	return deleted
+}
 
-	if err := eg.Wait(); err != nil {
-		return false, err
+func toFailedTargets(errors []types.Error) []FailedTarget {
+	targets := make([]FailedTarget, 0, len(errors))
+	for _, e := range errors {
+		targets = append(targets, FailedTarget{
+			Target: Target{
+				Key:       aws.ToString(e.Key),
+				VersionId: aws.ToString(e.VersionId),
+			},
+			Code:    aws.ToString(e.Code),
+			Message: aws.ToString(e.Message),
+		})
 	}
+	return targets
+}
 
-	return false, nil
+func toObjectIdentifiers(targets []Target) []types.ObjectIdentifier {
+	objects := make([]types.ObjectIdentifier, 0, len(targets))
+	for _, target := range targets {
+		object := types.ObjectIdentifier{
+			Key: aws.String(target.Key),
+		}
+		if target.VersionId != "" {
+			object.VersionId = aws.String(target.VersionId)
+		}
+		objects = append(objects, object)
+	}
+	return objects
 }
 
 func (s *S3Wrapper) deleteBucket(ctx context.Context, bucket string, bucketRegion string, quietMode bool) error {
@@ -235,6 +646,20 @@ func (s *S3Wrapper) OutputCheckingMessage(bucket string) error {
 	return nil
 }
 
+func (s *S3Wrapper) OutputDryRunMessage(bucket string, output *ClearBucketOutput) error {
+	io.Logger.Info().Msgf(
+		"%v Dry run: %v objects, %v versions, %v delete markers would be deleted.",
+		bucket,
+		output.ObjectsCount,
+		output.VersionsCount,
+		output.DeleteMarkersCount,
+	)
+	if output.BucketDeleted {
+		io.Logger.Info().Msgf("%v Dry run: the bucket would be deleted.", bucket)
+	}
+	return nil
+}
+
 func (s *S3Wrapper) GetLiveClearingMessage(bucket string, count int64) (string, error) {
 	return fmt.Sprintf("%v Clearing... %v objects", bucket, count), nil
 }
@@ -261,6 +686,8 @@ func (s *S3Wrapper) ListBucketNamesFilteredByKeyword(ctx context.Context, keywor
 			filteredBuckets = append(filteredBuckets, ListBucketNamesFilteredByKeywordOutput{
 				BucketName:   *bucket.Name,
 				TargetBucket: *bucket.Name,
+				CreationDate: bucket.CreationDate,
+				Region:       aws.ToString(bucket.BucketRegion),
 			})
 		}
 	}
@@ -275,6 +702,19 @@ func (s *S3Wrapper) ListBucketNamesFilteredByKeyword(ctx context.Context, keywor
 	return filteredBuckets, nil
 }
 
+// GetBucketTags returns the tags of a bucket
+func (s *S3Wrapper) GetBucketTags(ctx context.Context, bucket ListBucketNamesFilteredByKeywordOutput) (map[string]string, error) {
+	region := bucket.Region
+	if region == "" {
+		var err error
+		region, err = s.client.GetBucketLocation(ctx, aws.String(bucket.TargetBucket))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return s.client.GetBucketTagging(ctx, aws.String(bucket.TargetBucket), region)
+}
+
 func (s *S3Wrapper) CheckAllBucketsExist(ctx context.Context, bucketNames []string) ([]string, error) {
 	targetBucketNames := []string{}
 	nonExistingBucketNames := []string{}
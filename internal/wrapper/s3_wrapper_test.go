@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -23,18 +24,29 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 	io.NewLogger(false)
 
 	type args struct {
-		ctx        context.Context
-		bucketName string
-		forceMode  bool
-		quietMode  bool
+		ctx           context.Context
+		bucketName    string
+		forceMode     bool
+		quietMode     bool
+		dryRun        bool
+		prefixes      []string
+		targets       bool // give the targets also in the dry-run mode
+		partitions    int
+		splitPoints   []string
+		deleteWorkers int
+		filter        *client.ObjectFilter
 	}
 
 	cases := []struct {
-		name          string
-		args          args
-		prepareMockFn func(m *client.MockIS3)
-		want          error
-		wantErr       bool
+		name                    string
+		args                    args
+		prepareMockFn           func(m *client.MockIS3)
+		prepareTargetMockFn     func(r *MockITargetRecorder, s *MockITargetSource) // set the recorder and the source only if not nil
+		prepareCheckpointMockFn func(c *MockICheckpointer)                         // set the checkpointer only if not nil
+		prepareManifestMockFn   func(r *MockIDeletionRecorder)                     // set the manifest only if not nil
+		want                    error
+		wantOutput              *ClearBucketOutput
+		wantErr                 bool
 	}{
 		{
 			name: "clear objects successfully",
@@ -46,7 +58,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -62,7 +74,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						NextVersionIdMarker: nil,
 					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -82,7 +94,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -98,7 +110,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						NextVersionIdMarker: nil,
 					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -118,7 +130,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -134,7 +146,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						NextVersionIdMarker: nil,
 					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -145,6 +157,273 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			want:    nil,
 			wantErr: false,
 		},
+		{
+			name: "list objects without deleting them and the bucket in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  true,
+				quietMode:  true,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForLatestVersions"),
+								VersionId: aws.String("VersionIdForLatestVersions"),
+							},
+							{
+								Key:       aws.String("KeyForVersions"),
+								VersionId: aws.String("VersionIdForVersions"),
+							},
+							{
+								Key:       aws.String("KeyForDeleteMarkers"),
+								VersionId: aws.String("VersionIdForDeleteMarkers"),
+							},
+						},
+						NextKeyMarker:       aws.String("NextKeyMarker"),
+						NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+						ObjectsCount:        1,
+						VersionsCount:       1,
+						DeleteMarkersCount:  1,
+					}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("NextKeyMarker"), aws.String("NextVersionIdMarker"), nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForDeleteMarkers2"),
+								VersionId: aws.String("VersionIdForDeleteMarkers2"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						DeleteMarkersCount:  1,
+					}, nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount:       1,
+				VersionsCount:      1,
+				DeleteMarkersCount: 2,
+				BucketDeleted:      true,
+				Region:             "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "record listed objects in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions"),
+								VersionId: aws.String("VersionIdForVersions"),
+							},
+							{
+								Key: aws.String("KeyWithoutVersionId"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						ObjectsCount:        2,
+					}, nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				r.EXPECT().RecordTargets("test", []Target{
+					{Key: "KeyForVersions", VersionId: "VersionIdForVersions"},
+					{Key: "KeyWithoutVersionId"},
+				}).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount: 2,
+				Region:       "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "record listed objects failure in dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				dryRun:     true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions"),
+								VersionId: aws.String("VersionIdForVersions"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						ObjectsCount:        1,
+					}, nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				r.EXPECT().RecordTargets("test", gomock.Any()).Return(fmt.Errorf("RecordTargetsError"))
+			},
+			want:    fmt.Errorf("RecordTargetsError"),
+			wantErr: true,
+		},
+		{
+			name: "delete only given targets and the bucket without listing",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), []types.ObjectIdentifier{
+					{
+						Key:       aws.String("KeyForVersions"),
+						VersionId: aws.String("VersionIdForVersions"),
+					},
+					{
+						Key: aws.String("KeyWithoutVersionId"),
+					},
+				}, "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteBucket(gomock.Any(), aws.String("test"), "us-east-1").Return(nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn([]Target{
+							{Key: "KeyForVersions", VersionId: "VersionIdForVersions"},
+							{Key: "KeyWithoutVersionId"},
+						})
+					},
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount:  2,
+				BucketDeleted: true,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete given targets in batches of up to 1000 objects",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Len(1000), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Len(1), "us-east-1").Return([]types.Error{}, nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						targets := make([]Target, 0, 1001)
+						for i := 0; i < 1001; i++ {
+							targets = append(targets, Target{Key: fmt.Sprintf("Key%d", i)})
+						}
+						return fn(targets)
+					},
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount: 1001,
+				Region:       "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "count and record given targets without deleting them in the dry-run mode",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				dryRun:     true,
+				targets:    true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				targets := []Target{
+					{Key: "KeyForVersions", VersionId: "VersionIdForVersions"},
+					{Key: "KeyWithoutVersionId"},
+				}
+				s.EXPECT().ForEachTargets("test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn(targets)
+					},
+				)
+				r.EXPECT().RecordTargets("test", targets).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount: 2,
+				Region:       "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "delete given targets failure for target source errors",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test", gomock.Any()).Return(fmt.Errorf("ForEachTargetsError"))
+			},
+			want:    fmt.Errorf("ForEachTargetsError"),
+			wantErr: true,
+		},
+		{
+			name: "delete given targets failure for delete objects errors",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  true,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return(nil, fmt.Errorf("DeleteObjectsError"))
+			},
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test", gomock.Any()).DoAndReturn(
+					func(bucket string, fn func(targets []Target) error) error {
+						return fn([]Target{{Key: "Key"}})
+					},
+				)
+			},
+			want:    fmt.Errorf("DeleteObjectsError"),
+			wantErr: true,
+		},
 		{
 			name: "clear objects failure for get bucket location errors",
 			args: args{
@@ -169,7 +448,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(nil, fmt.Errorf("ListObjectVersionsByPageError"))
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(nil, fmt.Errorf("ListObjectVersionsByPageError"))
 			},
 			want:    fmt.Errorf("ListObjectVersionsByPageError"),
 			wantErr: true,
@@ -184,7 +463,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -214,7 +493,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -239,7 +518,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						},
 					}, nil,
 				)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -259,7 +538,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -275,7 +554,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						NextVersionIdMarker: nil,
 					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -296,7 +575,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -317,7 +596,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(&client.ListObjectsOrVersionsByPageOutput{
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(&client.ListObjectsOrVersionsByPageOutput{
 					ObjectIdentifiers:   []types.ObjectIdentifier{},
 					NextKeyMarker:       nil,
 					NextVersionIdMarker: nil,
@@ -337,7 +616,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -358,6 +637,9 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 					aws.String("NextKeyMarker1"),
 					aws.String("NextVersionIdMarker1"),
 					nil,
+					nil,
+					nil,
+					nil,
 				).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
@@ -379,6 +661,9 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 					aws.String("NextKeyMarker2"),
 					aws.String("NextVersionIdMarker2"),
 					nil,
+					nil,
+					nil,
+					nil,
 				).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
@@ -395,7 +680,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
@@ -415,7 +700,7 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
@@ -436,6 +721,9 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 					aws.String("NextKeyMarker1"),
 					aws.String("NextVersionIdMarker1"),
 					nil,
+					nil,
+					nil,
+					nil,
 				).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
@@ -457,6 +745,9 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 					aws.String("NextKeyMarker2"),
 					aws.String("NextVersionIdMarker2"),
 					nil,
+					nil,
+					nil,
+					nil,
 				).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
@@ -467,146 +758,962 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 						},
 						NextKeyMarker:       nil,
 						NextVersionIdMarker: nil,
-					},
-					nil,
-				)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+					},
+					nil,
+				)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return(
+					[]types.Error{
+						{
+							Key:       aws.String("Key"),
+							Code:      aws.String("Code"),
+							Message:   aws.String("Message"),
+							VersionId: aws.String("VersionId"),
+						},
+					}, nil,
+				)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers:   []types.ObjectIdentifier{},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+					}, nil)
+			},
+			want:    fmt.Errorf("[resource test] DeleteObjectsError: 1 objects with errors were found. \nCode: Code\nKey: Key\nVersionId: VersionId\nMessage: Message\n"),
+			wantErr: true,
+		},
+		{
+			name: "clear objects failure for delete objects errors if several loops are executed",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions1"),
+								VersionId: aws.String("VersionIdForVersions1"),
+							},
+						},
+						NextKeyMarker:       aws.String("NextKeyMarker1"),
+						NextVersionIdMarker: aws.String("NextVersionIdMarker1"),
+					},
+					nil,
+				)
+				m.EXPECT().ListObjectsOrVersionsByPage(
+					gomock.Any(),
+					aws.String("test"),
+					"us-east-1",
+					false,
+					aws.String("NextKeyMarker1"),
+					aws.String("NextVersionIdMarker1"),
+					nil,
+					nil,
+					nil,
+					nil,
+				).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions2"),
+								VersionId: aws.String("VersionIdForVersions2"),
+							},
+						},
+						NextKeyMarker:       aws.String("NextKeyMarker2"),
+						NextVersionIdMarker: aws.String("NextVersionIdMarker2"),
+					},
+					nil,
+				)
+				m.EXPECT().ListObjectsOrVersionsByPage(
+					gomock.Any(),
+					aws.String("test"),
+					"us-east-1",
+					false,
+					aws.String("NextKeyMarker2"),
+					aws.String("NextVersionIdMarker2"),
+					nil,
+					nil,
+					nil,
+					nil,
+				).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions3"),
+								VersionId: aws.String("VersionIdForVersions3"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, fmt.Errorf("DeleteObjectsError"))
+			},
+			want:    fmt.Errorf("DeleteObjectsError"),
+			wantErr: true,
+		},
+		{
+			name: "clear objects successfully when retry deletion loop is executed",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions1"),
+								VersionId: aws.String("VersionIdForVersions1"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+					},
+					nil,
+				)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				// retry loop
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions1"),
+								VersionId: aws.String("VersionIdForVersions1"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+					},
+					nil,
+				)
+				// retry deletion
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+			},
+			want:    nil,
+			wantErr: false,
+		},
+		{
+			name: "clear objects successfully when all objects in a page are filtered out",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				firstPage := &client.ListObjectsOrVersionsByPageOutput{
+					ObjectIdentifiers:   []types.ObjectIdentifier{},
+					NextKeyMarker:       aws.String("NextKeyMarker"),
+					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+				}
+				gomock.InOrder(
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(firstPage, nil),
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("NextKeyMarker"), aws.String("NextVersionIdMarker"), nil, nil, nil, nil).Return(
+						&client.ListObjectsOrVersionsByPageOutput{
+							ObjectIdentifiers: []types.ObjectIdentifier{
+								{
+									Key:       aws.String("Key1"),
+									VersionId: aws.String("VersionId1"),
+								},
+							},
+							NextKeyMarker:       nil,
+							NextVersionIdMarker: nil,
+							VersionsCount:       1,
+						}, nil),
+					// retry attempt
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(firstPage, nil),
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("NextKeyMarker"), aws.String("NextVersionIdMarker"), nil, nil, nil, nil).Return(
+						&client.ListObjectsOrVersionsByPageOutput{
+							ObjectIdentifiers:   []types.ObjectIdentifier{},
+							NextKeyMarker:       nil,
+							NextVersionIdMarker: nil,
+						}, nil),
+				)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Len(1), "us-east-1").Return([]types.Error{}, nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 1,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "resume clearing objects from the checkpoint",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("KeyMarker"), aws.String("VersionIdMarker"), nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions"),
+								VersionId: aws.String("VersionIdForVersions"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						VersionsCount:       1,
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				gomock.InOrder(
+					c.EXPECT().Load("test").Return(Checkpoint{
+						KeyMarker:       aws.String("KeyMarker"),
+						VersionIdMarker: aws.String("VersionIdMarker"),
+						Counts:          ClearBucketOutput{VersionsCount: 5, DeleteMarkersCount: 2},
+					}, true, nil),
+					c.EXPECT().Save("test", Checkpoint{
+						Counts:    ClearBucketOutput{VersionsCount: 6, DeleteMarkersCount: 2},
+						Completed: true,
+					}).Return(nil),
+					c.EXPECT().Delete("test").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount:      6,
+				DeleteMarkersCount: 2,
+				Region:             "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "resume clearing objects from the completed checkpoint without counting them again",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  false,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				// NOTE: Only the retry attempt lists the objects from the beginning to check the objects left.
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				gomock.InOrder(
+					c.EXPECT().Load("test").Return(Checkpoint{
+						Counts:    ClearBucketOutput{VersionsCount: 5, DeleteMarkersCount: 2},
+						Completed: true,
+					}, true, nil),
+					c.EXPECT().Delete("test").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount:      5,
+				DeleteMarkersCount: 2,
+				Region:             "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "resume clearing objects passing the versions cursor to the next page",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("Key1"), aws.String("VersionId1"), nil, nil, &client.VersionsCursor{Key: "Key1", NoncurrentCount: 1}, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key1"),
+								VersionId: aws.String("VersionId2"),
+							},
+						},
+						NextKeyMarker:       aws.String("Key2"),
+						NextVersionIdMarker: aws.String("VersionId3"),
+						VersionsCount:       1,
+						VersionsCursor:      &client.VersionsCursor{Key: "Key2", NoncurrentCount: 2},
+					}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("Key2"), aws.String("VersionId3"), nil, nil, &client.VersionsCursor{Key: "Key2", NoncurrentCount: 2}, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key2"),
+								VersionId: aws.String("VersionId4"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						VersionsCount:       1,
+						VersionsCursor:      &client.VersionsCursor{Key: "Key2", NoncurrentCount: 3},
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil).Times(2)
+				// NOTE: The retry attempt lists the versions from the beginning without the cursor.
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("test").Return(Checkpoint{
+					KeyMarker:       aws.String("Key1"),
+					VersionIdMarker: aws.String("VersionId1"),
+					VersionsCursor:  &client.VersionsCursor{Key: "Key1", NoncurrentCount: 1},
+				}, true, nil)
+				saved := []Checkpoint{}
+				c.EXPECT().Save("test", gomock.Any()).DoAndReturn(func(bucket string, checkpoint Checkpoint) error {
+					saved = append(saved, checkpoint)
+					return nil
+				}).MinTimes(1).MaxTimes(2)
+				c.EXPECT().Delete("test").DoAndReturn(func(bucket string) error {
+					last := saved[len(saved)-1]
+					if last.VersionsCursor == nil || last.VersionsCursor.NoncurrentCount != 3 {
+						return fmt.Errorf("unexpected last checkpoint: %#v", last)
+					}
+					return nil
+				})
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 2,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "resume clearing objects with the filter times saved in the checkpoint",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				filter: &client.ObjectFilter{
+					OlderThan: aws.Time(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)),
+					MinSize:   aws.Int64(1024),
+				},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				savedFilter := &client.ObjectFilter{
+					OlderThan: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+					MinSize:   aws.Int64(1024),
+				}
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("KeyMarker"), aws.String("VersionIdMarker"), nil, savedFilter, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("logs/Key"),
+								VersionId: aws.String("VersionId"),
+							},
+						},
+						VersionsCount: 1,
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, savedFilter, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				filterTimes := &client.FilterTimes{
+					OlderThan: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				}
+				gomock.InOrder(
+					c.EXPECT().Load("test").Return(Checkpoint{
+						KeyMarker:       aws.String("KeyMarker"),
+						VersionIdMarker: aws.String("VersionIdMarker"),
+						FilterTimes:     filterTimes,
+					}, true, nil),
+					c.EXPECT().Save("test", Checkpoint{
+						FilterTimes: filterTimes,
+						Counts:      ClearBucketOutput{VersionsCount: 1},
+						Completed:   true,
+					}).Return(nil),
+					c.EXPECT().Delete("test").Return(nil),
+				)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 1,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "clear objects of multiple key prefixes in parallel with a checkpoint for each prefix",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				prefixes:   []string{"logs/", "tmp/"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				for _, prefix := range []string{"logs/", "tmp/"} {
+					gomock.InOrder(
+						m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, aws.String(prefix), nil, nil, nil).Return(
+							&client.ListObjectsOrVersionsByPageOutput{
+								ObjectIdentifiers: []types.ObjectIdentifier{
+									{
+										Key:       aws.String(prefix + "Key"),
+										VersionId: aws.String("VersionId"),
+									},
+								},
+								VersionsCount: 1,
+							}, nil),
+						m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, aws.String(prefix), nil, nil, nil).Return(
+							&client.ListObjectsOrVersionsByPageOutput{
+								ObjectIdentifiers: []types.ObjectIdentifier{},
+							}, nil),
+					)
+				}
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil).Times(2)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				for _, key := range []string{"test/logs/", "test/tmp/"} {
+					c.EXPECT().Load(key).Return(Checkpoint{}, false, nil)
+					c.EXPECT().Save(key, gomock.Any()).Return(nil).AnyTimes()
+					c.EXPECT().Delete(key).Return(nil)
+				}
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 2,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "clear objects in parallel key ranges split by the top-level prefixes with a checkpoint for each range",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  false,
+				partitions: 2,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListCommonPrefixes(gomock.Any(), aws.String("test"), "us-east-1", nil, "/").Return(
+					[]string{"a/", "b/", "c/", "d/"}, nil,
+				)
+				for _, keyRange := range []*client.KeyRange{
+					{LastKey: aws.String("c/")},
+					{StartAfter: aws.String("c/")},
+				} {
+					gomock.InOrder(
+						m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, keyRange).Return(
+							&client.ListObjectsOrVersionsByPageOutput{
+								ObjectIdentifiers: []types.ObjectIdentifier{
+									{
+										Key:       aws.String("Key"),
+										VersionId: aws.String("VersionId"),
+									},
+								},
+								VersionsCount: 1,
+							}, nil),
+						m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, keyRange).Return(
+							&client.ListObjectsOrVersionsByPageOutput{
+								ObjectIdentifiers: []types.ObjectIdentifier{},
+							}, nil),
+					)
+				}
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil).Times(2)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				for _, key := range []string{"test", "test@c/"} {
+					c.EXPECT().Load(key).Return(Checkpoint{}, false, nil)
+					c.EXPECT().Save(key, gomock.Any()).Return(nil).AnyTimes()
+					c.EXPECT().Delete(key).Return(nil)
+				}
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 2,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "list the whole prefix if no top-level prefixes are found to split the keys",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				dryRun:     true,
+				prefixes:   []string{"logs/"},
+				partitions: 4,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListCommonPrefixes(gomock.Any(), aws.String("test"), "us-east-1", aws.String("logs/"), "/").Return(
+					[]string{}, nil,
+				)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, aws.String("logs/"), nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("logs/Key"),
+								VersionId: aws.String("VersionId"),
+							},
+						},
+						VersionsCount: 1,
+					}, nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 1,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "list objects in key ranges split at the given points under each prefix in the dry-run mode",
+			args: args{
+				ctx:         context.Background(),
+				bucketName:  "test",
+				forceMode:   false,
+				quietMode:   false,
+				dryRun:      true,
+				prefixes:    []string{"logs/", "tmp/"},
+				splitPoints: []string{"logs/2024", "logs/2025", "other"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				for _, keyRange := range []*client.KeyRange{
+					{LastKey: aws.String("logs/2024")},
+					{StartAfter: aws.String("logs/2024"), LastKey: aws.String("logs/2025")},
+					{StartAfter: aws.String("logs/2025")},
+				} {
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, aws.String("logs/"), nil, nil, keyRange).Return(
+						&client.ListObjectsOrVersionsByPageOutput{
+							ObjectIdentifiers: []types.ObjectIdentifier{
+								{
+									Key:       aws.String("logs/Key"),
+									VersionId: aws.String("VersionId"),
+								},
+							},
+							VersionsCount: 1,
+						}, nil)
+				}
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, aws.String("tmp/"), nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("tmp/Key"),
+								VersionId: aws.String("VersionId"),
+							},
+						},
+						VersionsCount: 1,
+					}, nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 4,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "list common prefixes failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+				partitions: 2,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListCommonPrefixes(gomock.Any(), aws.String("test"), "us-east-1", nil, "/").Return(
+					nil, fmt.Errorf("ListCommonPrefixesError"),
+				)
+			},
+			want:    fmt.Errorf("ListCommonPrefixesError"),
+			wantErr: true,
+		},
+		{
+			name: "delete the listed pages with one delete worker",
+			args: args{
+				ctx:           context.Background(),
+				bucketName:    "test",
+				forceMode:     false,
+				quietMode:     false,
+				deleteWorkers: 1,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				var keyMarker *string
+				for i := range 3 {
+					nextKeyMarker := aws.String(fmt.Sprintf("Key%d", i))
+					if i == 2 {
+						nextKeyMarker = nil
+					}
+					m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, keyMarker, nil, nil, nil, nil, nil).Return(
+						&client.ListObjectsOrVersionsByPageOutput{
+							ObjectIdentifiers: []types.ObjectIdentifier{
+								{
+									Key:       aws.String(fmt.Sprintf("Key%d", i)),
+									VersionId: aws.String("VersionId"),
+								},
+							},
+							NextKeyMarker: nextKeyMarker,
+							VersionsCount: 1,
+						}, nil)
+					keyMarker = nextKeyMarker
+				}
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil).Times(3)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 3,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "stop listing when a deletion fails with one delete worker",
+			args: args{
+				ctx:           context.Background(),
+				bucketName:    "test",
+				forceMode:     false,
+				quietMode:     true,
+				deleteWorkers: 1,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, gomock.Any(), nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key"),
+								VersionId: aws.String("VersionId"),
+							},
+						},
+						NextKeyMarker: aws.String("Key"),
+						VersionsCount: 1,
+					}, nil).MinTimes(1)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return(
+					nil, fmt.Errorf("DeleteObjectsError"),
+				)
+			},
+			want:    fmt.Errorf("DeleteObjectsError"),
+			wantErr: true,
+		},
+		{
+			name: "save a checkpoint for each page deleted",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key1"),
+								VersionId: aws.String("VersionId1"),
+							},
+						},
+						NextKeyMarker:       aws.String("Key1"),
+						NextVersionIdMarker: aws.String("VersionId1"),
+						VersionsCount:       1,
+					}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, aws.String("Key1"), aws.String("VersionId1"), nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key2"),
+								VersionId: aws.String("VersionId2"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						VersionsCount:       1,
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil).Times(2)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{},
+					}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("test").Return(Checkpoint{}, false, nil)
+				// NOTE: The pages can be deleted in any order, but the checkpoints are saved in order.
+				saved := []Checkpoint{}
+				c.EXPECT().Save("test", gomock.Any()).DoAndReturn(func(bucket string, checkpoint Checkpoint) error {
+					saved = append(saved, checkpoint)
+					return nil
+				}).MinTimes(1).MaxTimes(2)
+				c.EXPECT().Delete("test").DoAndReturn(func(bucket string) error {
+					if saved[len(saved)-1].KeyMarker != nil || saved[len(saved)-1].Counts.VersionsCount != 2 || !saved[len(saved)-1].Completed {
+						return fmt.Errorf("unexpected last checkpoint: %#v", saved[len(saved)-1])
+					}
+					return nil
+				})
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount: 2,
+				Region:        "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "load checkpoint failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("test").Return(Checkpoint{}, false, fmt.Errorf("LoadError"))
+			},
+			want:    fmt.Errorf("LoadError"),
+			wantErr: true,
+		},
+		{
+			name: "save checkpoint failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key: aws.String("Key1"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						ObjectsCount:        1,
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
+			},
+			prepareCheckpointMockFn: func(c *MockICheckpointer) {
+				c.EXPECT().Load("test").Return(Checkpoint{}, false, nil)
+				c.EXPECT().Save("test", gomock.Any()).Return(fmt.Errorf("SaveError"))
+			},
+			want:    fmt.Errorf("SaveError"),
+			wantErr: true,
+		},
+		{
+			name: "record deleted objects to the manifest",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("KeyForVersions"),
+								VersionId: aws.String("VersionIdForVersions"),
+							},
+							{
+								Key:       aws.String("KeyForDeleteMarkers"),
+								VersionId: aws.String("VersionIdForDeleteMarkers"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						VersionsCount:       1,
+						DeleteMarkersCount:  1,
+					}, nil)
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), []types.ObjectIdentifier{
+					{
+						Key:       aws.String("KeyForVersions"),
+						VersionId: aws.String("VersionIdForVersions"),
+					},
+					{
+						Key:       aws.String("KeyForDeleteMarkers"),
+						VersionId: aws.String("VersionIdForDeleteMarkers"),
+					},
+				}, "us-east-1").Return([]types.Error{}, nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers:   []types.ObjectIdentifier{},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+					}, nil)
+			},
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				r.EXPECT().RecordDeleted("test", []Target{
+					{Key: "KeyForVersions", VersionId: "VersionIdForVersions"},
+					{Key: "KeyForDeleteMarkers", VersionId: "VersionIdForDeleteMarkers", DeleteMarker: true},
+				}).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				VersionsCount:      1,
+				DeleteMarkersCount: 1,
+				Region:             "us-east-1",
+			},
+			wantErr: false,
+		},
+		{
+			name: "record objects that could not be deleted to the manifest failures",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: "test",
+				forceMode:  false,
+				quietMode:  true,
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
+					&client.ListObjectsOrVersionsByPageOutput{
+						ObjectIdentifiers: []types.ObjectIdentifier{
+							{
+								Key:       aws.String("Key1"),
+								VersionId: aws.String("VersionId1"),
+							},
+							{
+								Key:       aws.String("Key2"),
+								VersionId: aws.String("VersionId2"),
+							},
+						},
+						NextKeyMarker:       nil,
+						NextVersionIdMarker: nil,
+						ObjectsCount:        2,
+					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return(
 					[]types.Error{
 						{
-							Key:       aws.String("Key"),
-							Code:      aws.String("Code"),
-							Message:   aws.String("Message"),
-							VersionId: aws.String("VersionId"),
+							Key:       aws.String("Key2"),
+							Code:      aws.String("AccessDenied"),
+							Message:   aws.String("Access Denied"),
+							VersionId: aws.String("VersionId2"),
 						},
 					}, nil,
 				)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers:   []types.ObjectIdentifier{},
 						NextKeyMarker:       nil,
 						NextVersionIdMarker: nil,
 					}, nil)
 			},
-			want:    fmt.Errorf("[resource test] DeleteObjectsError: 1 objects with errors were found. \nCode: Code\nKey: Key\nVersionId: VersionId\nMessage: Message\n"),
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				gomock.InOrder(
+					r.EXPECT().RecordDeleted("test", []Target{
+						{Key: "Key1", VersionId: "VersionId1"},
+					}).Return(nil),
+					r.EXPECT().RecordFailed("test", []FailedTarget{
+						{
+							Target:  Target{Key: "Key2", VersionId: "VersionId2"},
+							Code:    "AccessDenied",
+							Message: "Access Denied",
+						},
+					}).Return(nil),
+				)
+			},
+			want:    fmt.Errorf("[resource test] DeleteObjectsError: 1 objects with errors were found. \nCode: AccessDenied\nKey: Key2\nVersionId: VersionId2\nMessage: Access Denied\n"),
 			wantErr: true,
 		},
 		{
-			name: "clear objects failure for delete objects errors if several loops are executed",
+			name: "record deleted given targets to the manifest",
 			args: args{
 				ctx:        context.Background(),
 				bucketName: "test",
 				forceMode:  false,
-				quietMode:  false,
+				quietMode:  true,
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
-					&client.ListObjectsOrVersionsByPageOutput{
-						ObjectIdentifiers: []types.ObjectIdentifier{
-							{
-								Key:       aws.String("KeyForVersions1"),
-								VersionId: aws.String("VersionIdForVersions1"),
-							},
-						},
-						NextKeyMarker:       aws.String("NextKeyMarker1"),
-						NextVersionIdMarker: aws.String("NextVersionIdMarker1"),
-					},
-					nil,
-				)
-				m.EXPECT().ListObjectsOrVersionsByPage(
-					gomock.Any(),
-					aws.String("test"),
-					"us-east-1",
-					false,
-					aws.String("NextKeyMarker1"),
-					aws.String("NextVersionIdMarker1"),
-					nil,
-				).Return(
-					&client.ListObjectsOrVersionsByPageOutput{
-						ObjectIdentifiers: []types.ObjectIdentifier{
-							{
-								Key:       aws.String("KeyForVersions2"),
-								VersionId: aws.String("VersionIdForVersions2"),
-							},
-						},
-						NextKeyMarker:       aws.String("NextKeyMarker2"),
-						NextVersionIdMarker: aws.String("NextVersionIdMarker2"),
-					},
-					nil,
-				)
-				m.EXPECT().ListObjectsOrVersionsByPage(
-					gomock.Any(),
-					aws.String("test"),
-					"us-east-1",
-					false,
-					aws.String("NextKeyMarker2"),
-					aws.String("NextVersionIdMarker2"),
-					nil,
-				).Return(
-					&client.ListObjectsOrVersionsByPageOutput{
-						ObjectIdentifiers: []types.ObjectIdentifier{
-							{
-								Key:       aws.String("KeyForVersions3"),
-								VersionId: aws.String("VersionIdForVersions3"),
-							},
-						},
-						NextKeyMarker:       nil,
-						NextVersionIdMarker: nil,
-					},
-					nil,
-				)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, fmt.Errorf("DeleteObjectsError"))
+				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Len(1), "us-east-1").Return([]types.Error{}, nil)
 			},
-			want:    fmt.Errorf("DeleteObjectsError"),
-			wantErr: true,
+			prepareTargetMockFn: func(r *MockITargetRecorder, s *MockITargetSource) {
+				s.EXPECT().ForEachTargets("test", gomock.Any()).DoAndReturn(func(bucket string, fn func(targets []Target) error) error {
+					return fn([]Target{{Key: "Key1", VersionId: "VersionId1", DeleteMarker: true}})
+				})
+			},
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				r.EXPECT().RecordDeleted("test", []Target{
+					{Key: "Key1", VersionId: "VersionId1", DeleteMarker: true},
+				}).Return(nil)
+			},
+			want: nil,
+			wantOutput: &ClearBucketOutput{
+				ObjectsCount: 1,
+				Region:       "us-east-1",
+			},
+			wantErr: false,
 		},
 		{
-			name: "clear objects successfully when retry deletion loop is executed",
+			name: "record deleted objects failure",
 			args: args{
 				ctx:        context.Background(),
 				bucketName: "test",
 				forceMode:  false,
-				quietMode:  false,
+				quietMode:  true,
 			},
 			prepareMockFn: func(m *client.MockIS3) {
 				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-east-1", nil)
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
-					&client.ListObjectsOrVersionsByPageOutput{
-						ObjectIdentifiers: []types.ObjectIdentifier{
-							{
-								Key:       aws.String("KeyForVersions1"),
-								VersionId: aws.String("VersionIdForVersions1"),
-							},
-						},
-						NextKeyMarker:       nil,
-						NextVersionIdMarker: nil,
-					},
-					nil,
-				)
-				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
-				// retry loop
-				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil).Return(
+				m.EXPECT().ListObjectsOrVersionsByPage(gomock.Any(), aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil).Return(
 					&client.ListObjectsOrVersionsByPageOutput{
 						ObjectIdentifiers: []types.ObjectIdentifier{
 							{
-								Key:       aws.String("KeyForVersions1"),
-								VersionId: aws.String("VersionIdForVersions1"),
+								Key: aws.String("Key1"),
 							},
 						},
 						NextKeyMarker:       nil,
 						NextVersionIdMarker: nil,
-					},
-					nil,
-				)
-				// retry deletion
+						ObjectsCount:        1,
+					}, nil)
 				m.EXPECT().DeleteObjects(gomock.Any(), aws.String("test"), gomock.Any(), "us-east-1").Return([]types.Error{}, nil)
 			},
-			want:    nil,
-			wantErr: false,
+			prepareManifestMockFn: func(r *MockIDeletionRecorder) {
+				r.EXPECT().RecordDeleted("test", gomock.Any()).Return(fmt.Errorf("ManifestFileError"))
+			},
+			want:    fmt.Errorf("ManifestFileError"),
+			wantErr: true,
 		},
 	}
 
@@ -626,12 +1733,41 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 				}()
 			}
 
-			err := s3.ClearBucket(tt.args.ctx, ClearBucketInput{
+			input := ClearBucketInput{
 				TargetBucket:    tt.args.bucketName,
 				ForceMode:       tt.args.forceMode,
 				QuietMode:       tt.args.quietMode,
 				ClearingCountCh: clearingCountCh,
-			})
+				DryRun:          tt.args.dryRun,
+				Prefixes:        tt.args.prefixes,
+				Partitions:      tt.args.partitions,
+				SplitPoints:     tt.args.splitPoints,
+				DeleteWorkers:   tt.args.deleteWorkers,
+				Filter:          tt.args.filter,
+			}
+			if tt.prepareTargetMockFn != nil {
+				recorderMock := NewMockITargetRecorder(ctrl)
+				sourceMock := NewMockITargetSource(ctrl)
+				tt.prepareTargetMockFn(recorderMock, sourceMock)
+				if tt.args.dryRun {
+					input.Recorder = recorderMock
+				}
+				if !tt.args.dryRun || tt.args.targets {
+					input.Targets = sourceMock
+				}
+			}
+			if tt.prepareCheckpointMockFn != nil {
+				checkpointerMock := NewMockICheckpointer(ctrl)
+				tt.prepareCheckpointMockFn(checkpointerMock)
+				input.Checkpointer = checkpointerMock
+			}
+			if tt.prepareManifestMockFn != nil {
+				manifestMock := NewMockIDeletionRecorder(ctrl)
+				tt.prepareManifestMockFn(manifestMock)
+				input.Manifest = manifestMock
+			}
+
+			output, err := s3.ClearBucket(tt.args.ctx, input)
 
 			close(clearingCountCh)
 
@@ -643,6 +1779,9 @@ func TestS3Wrapper_ClearBucket(t *testing.T) {
 				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.Error())
 				return
 			}
+			if tt.wantOutput != nil && !reflect.DeepEqual(output, tt.wantOutput) {
+				t.Errorf("output = %#v, want %#v", output, tt.wantOutput)
+			}
 		})
 	}
 }
@@ -704,6 +1843,37 @@ func TestS3Wrapper_ListBucketNamesFilteredByKeyword(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "list buckets filtered by keyword successfully with creation dates and regions",
+			args: args{
+				ctx:     context.Background(),
+				keyword: aws.String("test"),
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().ListBucketsOrDirectoryBuckets(gomock.Any()).Return(
+					[]types.Bucket{
+						{
+							Name:         aws.String("test1"),
+							CreationDate: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+							BucketRegion: aws.String("ap-northeast-1"),
+						},
+					},
+					nil,
+				)
+			},
+			want: want{
+				output: []ListBucketNamesFilteredByKeywordOutput{
+					{
+						BucketName:   "test1",
+						TargetBucket: "test1",
+						CreationDate: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+						Region:       "ap-northeast-1",
+					},
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
 		{
 			name: "list buckets filtered by keyword successfully when keyword is empty",
 			args: args{
@@ -1066,6 +2236,129 @@ func TestS3Wrapper_CheckAllBucketsExist(t *testing.T) {
 	}
 }
 
+func TestS3Wrapper_GetBucketTags(t *testing.T) {
+	type args struct {
+		ctx    context.Context
+		bucket ListBucketNamesFilteredByKeywordOutput
+	}
+
+	type want struct {
+		tags map[string]string
+		err  error
+	}
+
+	cases := []struct {
+		name          string
+		args          args
+		prepareMockFn func(m *client.MockIS3)
+		want          want
+		wantErr       bool
+	}{
+		{
+			name: "get bucket tags successfully",
+			args: args{
+				ctx:    context.Background(),
+				bucket: ListBucketNamesFilteredByKeywordOutput{BucketName: "test", TargetBucket: "test", Region: "ap-northeast-1"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketTagging(gomock.Any(), aws.String("test"), "ap-northeast-1").Return(
+					map[string]string{"env": "pr"},
+					nil,
+				)
+			},
+			want: want{
+				tags: map[string]string{"env": "pr"},
+				err:  nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "get bucket tags successfully with the location when the region is unknown",
+			args: args{
+				ctx:    context.Background(),
+				bucket: ListBucketNamesFilteredByKeywordOutput{BucketName: "test", TargetBucket: "test"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return("us-west-2", nil)
+				m.EXPECT().GetBucketTagging(gomock.Any(), aws.String("test"), "us-west-2").Return(
+					map[string]string{},
+					nil,
+				)
+			},
+			want: want{
+				tags: map[string]string{},
+				err:  nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "get bucket tags failure",
+			args: args{
+				ctx:    context.Background(),
+				bucket: ListBucketNamesFilteredByKeywordOutput{BucketName: "test", TargetBucket: "test", Region: "us-east-1"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketTagging(gomock.Any(), aws.String("test"), "us-east-1").Return(
+					nil,
+					&client.ClientError{
+						ResourceName: aws.String("test"),
+						Err:          fmt.Errorf("GetBucketTaggingError"),
+					},
+				)
+			},
+			want: want{
+				tags: nil,
+				err:  fmt.Errorf("[resource test] GetBucketTaggingError"),
+			},
+			wantErr: true,
+		},
+		{
+			name: "get bucket location failure",
+			args: args{
+				ctx:    context.Background(),
+				bucket: ListBucketNamesFilteredByKeywordOutput{BucketName: "test", TargetBucket: "test"},
+			},
+			prepareMockFn: func(m *client.MockIS3) {
+				m.EXPECT().GetBucketLocation(gomock.Any(), aws.String("test")).Return(
+					"",
+					&client.ClientError{
+						ResourceName: aws.String("test"),
+						Err:          fmt.Errorf("GetBucketLocationError"),
+					},
+				)
+			},
+			want: want{
+				tags: nil,
+				err:  fmt.Errorf("[resource test] GetBucketLocationError"),
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			s3Mock := client.NewMockIS3(ctrl)
+			tt.prepareMockFn(s3Mock)
+
+			s3 := NewS3Wrapper(s3Mock)
+
+			tags, err := s3.GetBucketTags(tt.args.ctx, tt.args.bucket)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.want.err.Error() {
+				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.err.Error())
+				return
+			}
+			if !reflect.DeepEqual(tags, tt.want.tags) {
+				t.Errorf("tags = %#v, want %#v", tags, tt.want.tags)
+			}
+		})
+	}
+}
+
 func TestS3Wrapper_OutputClearedMessage(t *testing.T) {
 	io.NewLogger(false)
 
@@ -1186,6 +2479,52 @@ func TestS3Wrapper_OutputCheckingMessage(t *testing.T) {
 	}
 }
 
+func TestS3Wrapper_OutputDryRunMessage(t *testing.T) {
+	io.NewLogger(false)
+
+	var buf bytes.Buffer
+	logger := zerolog.New(&buf)
+	io.Logger = &logger
+
+	tests := []struct {
+		name          string
+		bucket        string
+		output        *ClearBucketOutput
+		wantErr       bool
+		wantLogOutput string
+	}{
+		{
+			name:          "dry run result without bucket deletion",
+			bucket:        "test-bucket",
+			output:        &ClearBucketOutput{ObjectsCount: 100, VersionsCount: 20, DeleteMarkersCount: 3},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 100 objects, 20 versions, 3 delete markers would be deleted."}`,
+		},
+		{
+			name:          "dry run result with bucket deletion",
+			bucket:        "test-bucket",
+			output:        &ClearBucketOutput{ObjectsCount: 0, VersionsCount: 0, DeleteMarkersCount: 0, BucketDeleted: true},
+			wantErr:       false,
+			wantLogOutput: `{"level":"info","message":"test-bucket Dry run: 0 objects, 0 versions, 0 delete markers would be deleted."}` + "\n" + `{"level":"info","message":"test-bucket Dry run: the bucket would be deleted."}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+			s3 := NewS3Wrapper(nil)
+			err := s3.OutputDryRunMessage(tt.bucket, tt.output)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("OutputDryRunMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			got := buf.String()[:len(buf.String())-1] // remove trailing newline
+			if got != tt.wantLogOutput {
+				t.Errorf("OutputDryRunMessage() log = %v, want %v", got, tt.wantLogOutput)
+			}
+		})
+	}
+}
+
 func TestS3Wrapper_GetLiveClearingMessage(t *testing.T) {
 	io.NewLogger(false)
 
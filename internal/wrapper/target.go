@@ -0,0 +1,45 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOFILE -package=$GOPACKAGE -write_package_comment=false
+package wrapper
+
+// Target is a deletion target in a bucket.
+type Target struct {
+	Key          string `json:"key,omitempty"`          // for S3
+	VersionId    string `json:"versionId,omitempty"`    // for S3, empty when versions are not listed
+	DeleteMarker bool   `json:"deleteMarker,omitempty"` // for S3
+	Namespace    string `json:"namespace,omitempty"`    // for S3Tables
+	Table        string `json:"table,omitempty"`        // for S3Tables, empty for the namespace itself
+	Index        string `json:"index,omitempty"`        // for S3Vectors
+}
+
+// ITargetRecorder records the targets listed in the dry-run mode (e.g. to a plan file).
+// It must be safe for concurrent use because buckets and namespaces are listed in parallel.
+type ITargetRecorder interface {
+	RecordTargets(bucket string, targets []Target) error
+}
+
+// ITargetSource provides the targets to be deleted instead of listing them (e.g. from a plan file).
+type ITargetSource interface {
+	ForEachTargets(bucket string, fn func(targets []Target) error) error
+}
+
+// FailedTarget is a target that could not be deleted.
+type FailedTarget struct {
+	Target
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// IDeletionRecorder records the deleted targets and the ones that could not be deleted (e.g. to a manifest file).
+// It must be safe for concurrent use because buckets and pages are deleted in parallel.
+type IDeletionRecorder interface {
+	RecordDeleted(bucket string, targets []Target) error
+	RecordFailed(bucket string, targets []FailedTarget) error
+}
+
+// recordDeleted records a deleted target if the recorder is not nil
+func recordDeleted(recorder IDeletionRecorder, bucket string, target Target) error {
+	if recorder == nil {
+		return nil
+	}
+	return recorder.RecordDeleted(bucket, []Target{target})
+}
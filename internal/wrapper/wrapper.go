@@ -3,25 +3,33 @@ package wrapper
 
 import (
 	"context"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3tables"
 	"github.com/aws/aws-sdk-go-v2/service/s3vectors"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/go-to-k/cls3/internal/interrupt"
 	"github.com/go-to-k/cls3/pkg/client"
+	"github.com/go-to-k/cls3/pkg/endpoint"
 )
 
+// SDKRetryMaxAttempts is the default number of the attempts of the requests of the SDK clients without the retryer of cls3.
 const SDKRetryMaxAttempts = 3
 
 type IWrapper interface {
-	ClearBucket(ctx context.Context, input ClearBucketInput) error
+	ClearBucket(ctx context.Context, input ClearBucketInput) (*ClearBucketOutput, error)
 	OutputClearedMessage(bucket string, count int64) error
 	OutputDeletedMessage(bucket string) error
 	OutputCheckingMessage(bucket string) error
+	OutputDryRunMessage(bucket string, output *ClearBucketOutput) error
 	GetLiveClearingMessage(bucket string, count int64) (string, error)
 	GetLiveClearedMessage(bucket string, count int64, isCompleted bool) (string, error)
 	ListBucketNamesFilteredByKeyword(ctx context.Context, keyword *string) ([]ListBucketNamesFilteredByKeywordOutput, error)
 	CheckAllBucketsExist(ctx context.Context, bucketNames []string) ([]string, error)
+	GetBucketTags(ctx context.Context, bucket ListBucketNamesFilteredByKeywordOutput) (map[string]string, error)
 }
 
 type ClearBucketInput struct {
@@ -30,12 +38,91 @@ type ClearBucketInput struct {
 	OldVersionsOnly bool
 	QuietMode       bool
 	ClearingCountCh chan int64
-	Prefix          *string // not used for S3Tables
+	Prefixes        []string             // all keys (or indexes) if empty, only one for S3Vectors, and not used for S3Tables
+	Partitions      int                  // lists each prefix in up to this number of parallel key ranges split by the top-level prefixes for S3 if more than 1
+	SplitPoints     []string             // lists in parallel key ranges split after these sorted keys for S3 if not empty
+	DeleteWorkers   int                  // number of the parallel DeleteObjects calls for S3, or the default if not positive
+	Filter          *client.ObjectFilter // narrows down the objects to be deleted for S3 if not nil
+	DryRun          bool                 // list the targets but do not delete anything
+	Recorder        ITargetRecorder      // records the targets listed in the dry-run mode if not nil
+	Targets         ITargetSource        // deletes only these targets without listing them if not nil
+	Checkpointer    ICheckpointer        // saves the progress and resumes the clearing from the saved one if not nil
+	Manifest        IDeletionRecorder    // records the deleted targets and the ones that could not be deleted if not nil
+}
+
+// ClearBucketOutput holds the numbers of deleted targets for a bucket.
+// In the dry-run mode, they are the numbers of targets that would be deleted.
+type ClearBucketOutput struct {
+	ObjectsCount       int64  `json:"objectsCount"`       // for S3: latest versions, or all objects when versions are not listed or the targets are given
+	VersionsCount      int64  `json:"versionsCount"`      // for S3: noncurrent versions
+	DeleteMarkersCount int64  `json:"deleteMarkersCount"` // for S3
+	NamespacesCount    int64  `json:"namespacesCount"`    // for S3Tables
+	TablesCount        int64  `json:"tablesCount"`        // for S3Tables
+	IndexesCount       int64  `json:"indexesCount"`       // for S3Vectors
+	BucketDeleted      bool   `json:"bucketDeleted"`      // whether the bucket itself was deleted (or would be deleted in the dry-run mode)
+	Region             string `json:"region,omitempty"`   // for S3: the region of the bucket, empty for Directory Buckets
+}
+
+// TotalCount returns the number of all targets in the output
+func (o *ClearBucketOutput) TotalCount() int64 {
+	return o.ObjectsCount + o.VersionsCount + o.DeleteMarkersCount + o.NamespacesCount + o.TablesCount + o.IndexesCount
+}
+
+var _ error = (*PartiallyClearedError)(nil)
+
+// PartiallyClearedError is the error of a bucket from which some targets were deleted before the error.
+// Output holds the numbers of the deleted targets.
+type PartiallyClearedError struct {
+	Output *ClearBucketOutput
+	Err    error
+}
+
+func (e *PartiallyClearedError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PartiallyClearedError) Unwrap() error {
+	return e.Err
+}
+
+// partiallyCleared returns a PartiallyClearedError if any targets in the output were deleted, or the error itself.
+// In the dry-run mode, nothing is deleted, so the error is returned as it is.
+func partiallyCleared(input ClearBucketInput, output *ClearBucketOutput, err error) error {
+	if input.DryRun || output.TotalCount() == 0 {
+		return err
+	}
+	return &PartiallyClearedError{
+		Output: output,
+		Err:    err,
+	}
+}
+
+// regionFromArn returns the region in an arn, or empty if it cannot be parsed.
+func regionFromArn(resourceArn string) string {
+	parsed, err := arn.Parse(resourceArn)
+	if err != nil {
+		return ""
+	}
+	return parsed.Region
+}
+
+// interrupted returns an error of the resource if the run has been interrupted by a signal, so that
+// no more targets are listed or deleted. The deletions in progress are not canceled.
+func interrupted(ctx context.Context, resourceName string) error {
+	if !interrupt.IsStopping(ctx) {
+		return nil
+	}
+	return &client.ClientError{
+		ResourceName: aws.String(resourceName),
+		Err:          interrupt.ErrInterrupted,
+	}
 }
 
 type ListBucketNamesFilteredByKeywordOutput struct {
 	BucketName   string
-	TargetBucket string // bucket name for S3 and S3Vectors, bucket arn for S3Tables
+	TargetBucket string     // bucket name for S3 and S3Vectors, bucket arn for S3Tables
+	CreationDate *time.Time // nil if unknown
+	Region       string     // empty if unknown (e.g. S3-compatible storage)
 }
 
 type CreateS3WrapperInput struct {
@@ -46,6 +133,33 @@ type CreateS3WrapperInput struct {
 	TableBucketsMode     bool
 	DirectoryBucketsMode bool
 	VectorBucketsMode    bool
+	MaxAttempts          int           // the attempts of each request including the first one, the default of each client if not positive
+	RetryBaseDelay       time.Duration // the default of each client if not positive
+	RetryMaxDelay        time.Duration // the default of each client if not positive
+	RetryableErrorCodes  []string      // retried in addition to the default codes of each client
+	ExpectedBucketOwner  string        // the account that must own the buckets for S3, not checked if empty
+}
+
+// setRetryOptions overrides the retry options of a client with the specified ones.
+func (input CreateS3WrapperInput) setRetryOptions(o *client.RetryOptions) {
+	if input.MaxAttempts > 0 {
+		o.MaxAttempts = input.MaxAttempts
+	}
+	if input.RetryBaseDelay > 0 {
+		o.BaseDelay = input.RetryBaseDelay
+	}
+	if input.RetryMaxDelay > 0 {
+		o.MaxDelay = input.RetryMaxDelay
+	}
+	o.RetryableErrorCodes = input.RetryableErrorCodes
+}
+
+// sdkRetryMaxAttempts returns the attempts of the requests of the SDK clients without the retryer of cls3 (e.g. STS).
+func (input CreateS3WrapperInput) sdkRetryMaxAttempts() int {
+	if input.MaxAttempts > 0 {
+		return input.MaxAttempts
+	}
+	return SDKRetryMaxAttempts
 }
 
 func CreateS3Wrapper(ctx context.Context, input CreateS3WrapperInput) (IWrapper, error) {
@@ -57,9 +171,10 @@ func CreateS3Wrapper(ctx context.Context, input CreateS3WrapperInput) (IWrapper,
 	if input.TableBucketsMode {
 		client := client.NewS3Tables(
 			s3tables.NewFromConfig(config, func(o *s3tables.Options) {
-				o.RetryMaxAttempts = SDKRetryMaxAttempts
+				o.RetryMaxAttempts = input.sdkRetryMaxAttempts()
 				o.RetryMode = aws.RetryModeStandard
 			}),
+			input.setRetryOptions,
 		)
 		return NewS3TablesWrapper(client), nil
 	}
@@ -67,20 +182,76 @@ func CreateS3Wrapper(ctx context.Context, input CreateS3WrapperInput) (IWrapper,
 	if input.VectorBucketsMode {
 		client := client.NewS3Vectors(
 			s3vectors.NewFromConfig(config, func(o *s3vectors.Options) {
-				o.RetryMaxAttempts = SDKRetryMaxAttempts
+				o.RetryMaxAttempts = input.sdkRetryMaxAttempts()
 				o.RetryMode = aws.RetryModeStandard
 			}),
+			input.setRetryOptions,
 		)
 		return NewS3VectorsWrapper(client), nil
 	}
 
-	client := client.NewS3(
+	// NOTE: S3 Tables and S3 Vectors do not support the expected bucket owner.
+	client := newS3Client(config, input).WithExpectedBucketOwner(input.ExpectedBucketOwner)
+	return NewS3Wrapper(client), nil
+}
+
+// CreateS3Client creates a client for S3 (or Directory Buckets) to read objects other than the targets (e.g. S3 Inventory reports)
+func CreateS3Client(ctx context.Context, input CreateS3WrapperInput) (client.IS3, error) {
+	config, err := client.LoadAWSConfig(ctx, input.Region, input.Profile, input.EndpointUrl)
+	if err != nil {
+		return nil, err
+	}
+	return newS3Client(config, input), nil
+}
+
+func newS3Client(config aws.Config, input CreateS3WrapperInput) *client.S3 {
+	return client.NewS3(
 		s3.NewFromConfig(config, func(o *s3.Options) {
-			o.RetryMaxAttempts = SDKRetryMaxAttempts
+			o.RetryMaxAttempts = input.sdkRetryMaxAttempts()
 			o.RetryMode = aws.RetryModeStandard
 			o.UsePathStyle = input.PathStyle
 		}),
 		input.DirectoryBucketsMode,
+		input.setRetryOptions,
 	)
-	return NewS3Wrapper(client), nil
+}
+
+// TargetEnvironment identifies where the buckets are operated on.
+type TargetEnvironment struct {
+	AccountId   string // empty for non-AWS endpoints
+	Region      string
+	EndpointUrl string
+}
+
+func GetTargetEnvironment(ctx context.Context, input CreateS3WrapperInput) (*TargetEnvironment, error) {
+	config, err := client.LoadAWSConfig(ctx, input.Region, input.Profile, input.EndpointUrl)
+	if err != nil {
+		return nil, err
+	}
+
+	environment := &TargetEnvironment{
+		Region:      config.Region,
+		EndpointUrl: input.EndpointUrl,
+	}
+
+	// NOTE: S3-compatible storage does not have STS, so the account is not identified.
+	if !endpoint.IsAWSS3Endpoint(input.EndpointUrl) {
+		return environment, nil
+	}
+
+	client := client.NewSTS(
+		sts.NewFromConfig(config, func(o *sts.Options) {
+			// NOTE: The custom endpoint URL is for S3, so it must not be used for STS.
+			o.BaseEndpoint = nil
+			o.RetryMaxAttempts = input.sdkRetryMaxAttempts()
+			o.RetryMode = aws.RetryModeStandard
+		}),
+	)
+	accountId, err := client.GetCallerAccountId(ctx)
+	if err != nil {
+		return nil, err
+	}
+	environment.AccountId = accountId
+
+	return environment, nil
 }
@@ -2,9 +2,12 @@ package wrapper
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/go-to-k/cls3/pkg/client"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -43,6 +46,14 @@ func TestCreateS3Wrapper(t *testing.T) {
 			},
 			wantType: "*wrapper.S3Wrapper",
 		},
+		{
+			name: "TableBucketsMode with retryable error codes creates S3TablesWrapper",
+			input: CreateS3WrapperInput{
+				TableBucketsMode:    true,
+				RetryableErrorCodes: []string{"RequestTimeout"},
+			},
+			wantType: "*wrapper.S3TablesWrapper",
+		},
 	}
 
 	for _, tt := range tests {
@@ -59,3 +70,147 @@ func TestCreateS3Wrapper(t *testing.T) {
 		})
 	}
 }
+
+func TestCreateS3WrapperInput_setRetryOptions(t *testing.T) {
+	defaults := client.RetryOptions{
+		MaxAttempts: client.MaxAttempts,
+		BaseDelay:   client.DefaultRetryBaseDelay,
+		MaxDelay:    20 * time.Second,
+	}
+
+	tests := []struct {
+		name            string
+		input           CreateS3WrapperInput
+		want            client.RetryOptions
+		wantSDKAttempts int
+	}{
+		{
+			name:            "keep the defaults of the client",
+			input:           CreateS3WrapperInput{},
+			want:            defaults,
+			wantSDKAttempts: SDKRetryMaxAttempts,
+		},
+		{
+			name: "override the defaults of the client",
+			input: CreateS3WrapperInput{
+				MaxAttempts:         1,
+				RetryBaseDelay:      100 * time.Millisecond,
+				RetryMaxDelay:       time.Second,
+				RetryableErrorCodes: []string{"RequestTimeout"},
+			},
+			want: client.RetryOptions{
+				MaxAttempts:         1,
+				BaseDelay:           100 * time.Millisecond,
+				MaxDelay:            time.Second,
+				RetryableErrorCodes: []string{"RequestTimeout"},
+			},
+			wantSDKAttempts: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			options := defaults
+			tt.input.setRetryOptions(&options)
+			assert.Equal(t, tt.want, options)
+			assert.Equal(t, tt.wantSDKAttempts, tt.input.sdkRetryMaxAttempts())
+		})
+	}
+}
+
+func Test_partiallyCleared(t *testing.T) {
+	clearErr := fmt.Errorf("DeleteBucketError")
+
+	tests := []struct {
+		name       string
+		input      ClearBucketInput
+		output     *ClearBucketOutput
+		wantOutput *ClearBucketOutput // nil if the error is not a PartiallyClearedError
+	}{
+		{
+			name:       "some objects were deleted",
+			input:      ClearBucketInput{},
+			output:     &ClearBucketOutput{ObjectsCount: 10},
+			wantOutput: &ClearBucketOutput{ObjectsCount: 10},
+		},
+		{
+			name:       "some tables were deleted",
+			input:      ClearBucketInput{},
+			output:     &ClearBucketOutput{TablesCount: 1},
+			wantOutput: &ClearBucketOutput{TablesCount: 1},
+		},
+		{
+			name:   "nothing was deleted",
+			input:  ClearBucketInput{},
+			output: &ClearBucketOutput{},
+		},
+		{
+			name:   "dry run",
+			input:  ClearBucketInput{DryRun: true},
+			output: &ClearBucketOutput{ObjectsCount: 10},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := partiallyCleared(tt.input, tt.output, clearErr)
+			assert.ErrorIs(t, err, clearErr)
+			assert.Equal(t, clearErr.Error(), err.Error())
+
+			var partialErr *PartiallyClearedError
+			if tt.wantOutput == nil {
+				assert.False(t, errors.As(err, &partialErr))
+				return
+			}
+			require.True(t, errors.As(err, &partialErr))
+			assert.Equal(t, tt.wantOutput, partialErr.Output)
+		})
+	}
+}
+
+func TestGetTargetEnvironment(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name  string
+		input CreateS3WrapperInput
+		want  *TargetEnvironment
+	}{
+		{
+			name: "non-AWS endpoint does not identify the account",
+			input: CreateS3WrapperInput{
+				Region:      "ap-northeast-1",
+				EndpointUrl: "http://localhost:9000",
+			},
+			want: &TargetEnvironment{
+				AccountId:   "",
+				Region:      "ap-northeast-1",
+				EndpointUrl: "http://localhost:9000",
+			},
+		},
+		{
+			name: "Cloudflare R2 endpoint uses the auto region by default",
+			input: CreateS3WrapperInput{
+				EndpointUrl: "https://account.r2.cloudflarestorage.com",
+			},
+			want: &TargetEnvironment{
+				AccountId:   "",
+				Region:      "auto",
+				EndpointUrl: "https://account.r2.cloudflarestorage.com",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("AWS_REGION", "")
+			t.Setenv("AWS_DEFAULT_REGION", "")
+			t.Setenv("AWS_CONFIG_FILE", "/dev/null")
+
+			got, err := GetTargetEnvironment(ctx, tt.input)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
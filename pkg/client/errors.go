@@ -1,6 +1,11 @@
 package client
 
-import "fmt"
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
 
 var _ error = (*ClientError)(nil)
 
@@ -23,3 +28,21 @@ func (e *ClientError) Error() string {
 func (e *ClientError) Unwrap() error {
 	return e.Err
 }
+
+var _ error = (*DeleteObjectsError)(nil)
+
+// DeleteObjectsError provides the errors for the objects that DeleteObjects failed to delete
+type DeleteObjectsError struct {
+	Errors []types.Error
+}
+
+func (e *DeleteObjectsError) Error() string {
+	msg := fmt.Sprintf("DeleteObjectsError: %v objects with errors were found. ", len(e.Errors))
+	for _, err := range e.Errors {
+		msg += fmt.Sprintf("\nCode: %v\n", aws.ToString(err.Code))
+		msg += fmt.Sprintf("Key: %v\n", aws.ToString(err.Key))
+		msg += fmt.Sprintf("VersionId: %v\n", aws.ToString(err.VersionId))
+		msg += fmt.Sprintf("Message: %v\n", aws.ToString(err.Message))
+	}
+	return msg
+}
@@ -10,6 +10,7 @@ package client
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
 	types "github.com/aws/aws-sdk-go-v2/service/s3/types"
@@ -84,6 +85,36 @@ func (mr *MockIS3MockRecorder) GetBucketLocation(ctx, bucketName any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketLocation", reflect.TypeOf((*MockIS3)(nil).GetBucketLocation), ctx, bucketName)
 }
 
+// GetBucketTagging mocks base method.
+func (m *MockIS3) GetBucketTagging(ctx context.Context, bucketName *string, region string) (map[string]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBucketTagging", ctx, bucketName, region)
+	ret0, _ := ret[0].(map[string]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBucketTagging indicates an expected call of GetBucketTagging.
+func (mr *MockIS3MockRecorder) GetBucketTagging(ctx, bucketName, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBucketTagging", reflect.TypeOf((*MockIS3)(nil).GetBucketTagging), ctx, bucketName, region)
+}
+
+// GetObject mocks base method.
+func (m *MockIS3) GetObject(ctx context.Context, bucketName, key *string, region string) (io.ReadCloser, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetObject", ctx, bucketName, key, region)
+	ret0, _ := ret[0].(io.ReadCloser)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetObject indicates an expected call of GetObject.
+func (mr *MockIS3MockRecorder) GetObject(ctx, bucketName, key, region any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetObject", reflect.TypeOf((*MockIS3)(nil).GetObject), ctx, bucketName, key, region)
+}
+
 // ListBucketsOrDirectoryBuckets mocks base method.
 func (m *MockIS3) ListBucketsOrDirectoryBuckets(ctx context.Context) ([]types.Bucket, error) {
 	m.ctrl.T.Helper()
@@ -99,17 +130,32 @@ func (mr *MockIS3MockRecorder) ListBucketsOrDirectoryBuckets(ctx any) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBucketsOrDirectoryBuckets", reflect.TypeOf((*MockIS3)(nil).ListBucketsOrDirectoryBuckets), ctx)
 }
 
+// ListCommonPrefixes mocks base method.
+func (m *MockIS3) ListCommonPrefixes(ctx context.Context, bucketName *string, region string, keyPrefix *string, delimiter string) ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCommonPrefixes", ctx, bucketName, region, keyPrefix, delimiter)
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCommonPrefixes indicates an expected call of ListCommonPrefixes.
+func (mr *MockIS3MockRecorder) ListCommonPrefixes(ctx, bucketName, region, keyPrefix, delimiter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCommonPrefixes", reflect.TypeOf((*MockIS3)(nil).ListCommonPrefixes), ctx, bucketName, region, keyPrefix, delimiter)
+}
+
 // ListObjectsOrVersionsByPage mocks base method.
-func (m *MockIS3) ListObjectsOrVersionsByPage(ctx context.Context, bucketName *string, region string, oldVersionsOnly bool, keyMarker, versionIdMarker, keyPrefix *string) (*ListObjectsOrVersionsByPageOutput, error) {
+func (m *MockIS3) ListObjectsOrVersionsByPage(ctx context.Context, bucketName *string, region string, oldVersionsOnly bool, keyMarker, versionIdMarker, keyPrefix *string, filter *ObjectFilter, versionsCursor *VersionsCursor, keyRange *KeyRange) (*ListObjectsOrVersionsByPageOutput, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "ListObjectsOrVersionsByPage", ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix)
+	ret := m.ctrl.Call(m, "ListObjectsOrVersionsByPage", ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix, filter, versionsCursor, keyRange)
 	ret0, _ := ret[0].(*ListObjectsOrVersionsByPageOutput)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // ListObjectsOrVersionsByPage indicates an expected call of ListObjectsOrVersionsByPage.
-func (mr *MockIS3MockRecorder) ListObjectsOrVersionsByPage(ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix any) *gomock.Call {
+func (mr *MockIS3MockRecorder) ListObjectsOrVersionsByPage(ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix, filter, versionsCursor, keyRange any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsOrVersionsByPage", reflect.TypeOf((*MockIS3)(nil).ListObjectsOrVersionsByPage), ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListObjectsOrVersionsByPage", reflect.TypeOf((*MockIS3)(nil).ListObjectsOrVersionsByPage), ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix, filter, versionsCursor, keyRange)
 }
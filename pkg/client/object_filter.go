@@ -0,0 +1,286 @@
+package client
+
+import (
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+// ObjectFilter narrows down the objects to be deleted in a listed page. A nil filter matches all objects.
+type ObjectFilter struct {
+	OlderThan           *time.Time       // only the objects last modified before this time if not nil
+	NewerThan           *time.Time       // only the objects last modified after this time if not nil
+	KeepVersions        int              // keeps the newest N noncurrent versions of each key if positive
+	KeepNoncurrentSince *time.Time       // keeps the noncurrent versions that became noncurrent after this time if not nil
+	StorageClasses      []string         // only the objects in these storage classes if not empty
+	MinSize             *int64           // only the objects of this size in bytes or larger if not nil
+	MaxSize             *int64           // only the objects of this size in bytes or smaller if not nil
+	Include             []*regexp.Regexp // only the objects whose keys match any of these patterns if not empty
+	Exclude             []*regexp.Regexp // except the objects whose keys match any of these patterns
+}
+
+// FilterTimes are the times of an ObjectFilter resolved from the options relative to now (e.g. 7d).
+// They are saved in a checkpoint so that a resumed clearing deletes the same objects as before it stopped.
+type FilterTimes struct {
+	OlderThan           *time.Time `json:"olderThan,omitempty"`
+	NewerThan           *time.Time `json:"newerThan,omitempty"`
+	KeepNoncurrentSince *time.Time `json:"keepNoncurrentSince,omitempty"`
+}
+
+// Times returns the times of the filter, or nil if it has none
+func (f *ObjectFilter) Times() *FilterTimes {
+	if f == nil || (f.OlderThan == nil && f.NewerThan == nil && f.KeepNoncurrentSince == nil) {
+		return nil
+	}
+	return &FilterTimes{
+		OlderThan:           f.OlderThan,
+		NewerThan:           f.NewerThan,
+		KeepNoncurrentSince: f.KeepNoncurrentSince,
+	}
+}
+
+// WithTimes returns a copy of the filter with the times, or the filter itself if the times are nil
+func (f *ObjectFilter) WithTimes(times *FilterTimes) *ObjectFilter {
+	if f == nil || times == nil {
+		return f
+	}
+	filter := *f
+	filter.OlderThan = times.OlderThan
+	filter.NewerThan = times.NewerThan
+	filter.KeepNoncurrentSince = times.KeepNoncurrentSince
+	return &filter
+}
+
+// VersionsCursor carries the versions of the last key in a page over to the next page for the retention,
+// because the versions of a key can span pages of ListObjectVersions.
+type VersionsCursor struct {
+	Key             string     `json:"key"`
+	NoncurrentCount int        `json:"noncurrentCount"`        // number of noncurrent versions listed for the key
+	LastModified    *time.Time `json:"lastModified,omitempty"` // time of the oldest version listed for the key
+}
+
+// KeyRange limits the keys to be listed so that a bucket can be listed in parallel by the ranges. A nil range is all keys.
+type KeyRange struct {
+	StartAfter *string // lists the keys after this key if not nil
+	LastKey    *string // lists the keys up to this key (inclusive) if not nil
+}
+
+// includes returns whether a key is not after the range. The start is not checked because the listing starts after it.
+func (r *KeyRange) includes(key *string) bool {
+	if r == nil || r.LastKey == nil || key == nil {
+		return true
+	}
+	return *key <= *r.LastKey
+}
+
+// matchLastModified returns whether an object last modified at the time is a deletion target
+func (f *ObjectFilter) matchLastModified(lastModified *time.Time) bool {
+	if f == nil || (f.OlderThan == nil && f.NewerThan == nil) {
+		return true
+	}
+	// NOTE: An object without the time cannot be judged, so it is kept.
+	if lastModified == nil {
+		return false
+	}
+	if f.OlderThan != nil && !lastModified.Before(*f.OlderThan) {
+		return false
+	}
+	if f.NewerThan != nil && !lastModified.After(*f.NewerThan) {
+		return false
+	}
+	return true
+}
+
+// matchKey returns whether an object with the key is a deletion target
+func (f *ObjectFilter) matchKey(key *string) bool {
+	if f == nil {
+		return true
+	}
+	k := aws.ToString(key)
+	matchFn := func(pattern *regexp.Regexp) bool {
+		return pattern.MatchString(k)
+	}
+	if len(f.Include) > 0 && !slices.ContainsFunc(f.Include, matchFn) {
+		return false
+	}
+	return !slices.ContainsFunc(f.Exclude, matchFn)
+}
+
+// matchAttributes returns whether an object in the storage class and of the size is a deletion target
+func (f *ObjectFilter) matchAttributes(storageClass string, size *int64) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.StorageClasses) > 0 && !slices.Contains(f.StorageClasses, storageClass) {
+		return false
+	}
+	if f.MinSize == nil && f.MaxSize == nil {
+		return true
+	}
+	// NOTE: An object without the size (e.g. a delete marker) cannot be judged, so it is kept.
+	if size == nil {
+		return false
+	}
+	if f.MinSize != nil && *size < *f.MinSize {
+		return false
+	}
+	if f.MaxSize != nil && *size > *f.MaxSize {
+		return false
+	}
+	return true
+}
+
+// MatchObject returns whether an object given without listing (e.g. in an S3 Inventory report) is a deletion
+// target. The retention of noncurrent versions is not applied because it needs all versions of the key.
+func (f *ObjectFilter) MatchObject(key string, lastModified *time.Time, storageClass string, size *int64) bool {
+	return f.matchKey(&key) && f.matchLastModified(lastModified) && f.matchAttributes(storageClass, size)
+}
+
+// hasRetention returns whether noncurrent versions are kept by the number or the days
+func (f *ObjectFilter) hasRetention() bool {
+	return f != nil && (f.KeepVersions > 0 || f.KeepNoncurrentSince != nil)
+}
+
+type versionEntry struct {
+	key            string
+	lastModified   *time.Time
+	isLatest       bool
+	isDeleteMarker bool
+	index          int
+}
+
+// retainVersions returns whether each of the versions and the delete markers in a page is kept by the retention,
+// and the cursor to be passed with the next page. The current versions are always kept.
+func (f *ObjectFilter) retainVersions(
+	versions []types.ObjectVersion,
+	deleteMarkers []types.DeleteMarkerEntry,
+	cursor *VersionsCursor,
+) ([]bool, []bool, *VersionsCursor) {
+	keptVersions := make([]bool, len(versions))
+	keptDeleteMarkers := make([]bool, len(deleteMarkers))
+	if !f.hasRetention() {
+		return keptVersions, keptDeleteMarkers, nil
+	}
+
+	entries := make([]versionEntry, 0, len(versions)+len(deleteMarkers))
+	for i, version := range versions {
+		entries = append(entries, versionEntry{
+			key:          aws.ToString(version.Key),
+			lastModified: version.LastModified,
+			isLatest:     version.IsLatest == nil || *version.IsLatest,
+			index:        i,
+		})
+	}
+	for i, deleteMarker := range deleteMarkers {
+		entries = append(entries, versionEntry{
+			key:            aws.ToString(deleteMarker.Key),
+			lastModified:   deleteMarker.LastModified,
+			isLatest:       deleteMarker.IsLatest != nil && *deleteMarker.IsLatest,
+			isDeleteMarker: true,
+			index:          i,
+		})
+	}
+	// NOTE: ListObjectVersions returns the versions and the delete markers separately, so they are merged
+	// into the order of the keys and then from the newest to the oldest in each key.
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].key != entries[j].key {
+			return entries[i].key < entries[j].key
+		}
+		if entries[i].isLatest != entries[j].isLatest {
+			return entries[i].isLatest
+		}
+		return aws.ToTime(entries[i].lastModified).After(aws.ToTime(entries[j].lastModified))
+	})
+
+	var current VersionsCursor
+	if cursor != nil {
+		current = *cursor
+	}
+	for _, entry := range entries {
+		if entry.key != current.Key {
+			current = VersionsCursor{Key: entry.key}
+		}
+
+		kept := f.keepVersion(entry, current)
+		if entry.isDeleteMarker {
+			keptDeleteMarkers[entry.index] = kept
+		} else {
+			keptVersions[entry.index] = kept
+		}
+
+		if !entry.isLatest {
+			current.NoncurrentCount++
+		}
+		current.LastModified = entry.lastModified
+	}
+
+	return keptVersions, keptDeleteMarkers, &current
+}
+
+// keepVersion returns whether a version is kept with the cursor of the newer versions of the same key
+func (f *ObjectFilter) keepVersion(entry versionEntry, newer VersionsCursor) bool {
+	if entry.isLatest {
+		return true
+	}
+	// NOTE: The time a version became noncurrent is when the next newer version was created,
+	// so the version is kept if it is not known (e.g. a checkpoint saved without the cursor).
+	if newer.LastModified == nil {
+		return true
+	}
+	if newer.NoncurrentCount < f.KeepVersions {
+		return true
+	}
+	if f.KeepNoncurrentSince != nil && newer.LastModified.After(*f.KeepNoncurrentSince) {
+		return true
+	}
+	return false
+}
+
+// RegexPatternPrefix is the prefix of a key pattern to be a regular expression instead of a glob.
+const RegexPatternPrefix = "regex:"
+
+// CompileKeyPattern compiles a glob for whole keys, or a regular expression with the "regex:" prefix
+// to be searched in keys. In a glob, `*` matches any characters including the delimiter ( / ),
+// `?` matches any single character, and `[...]` matches a character class (`[!...]` for negation).
+func CompileKeyPattern(pattern string) (*regexp.Regexp, error) {
+	if expr, ok := strings.CutPrefix(pattern, RegexPatternPrefix); ok {
+		return regexp.Compile(expr)
+	}
+	return regexp.Compile(globToRegexp(pattern))
+}
+
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	// NOTE: Keys can have newlines, so `.` must match them too.
+	b.WriteString("(?s)^")
+	runes := []rune(glob)
+	for i := 0; i < len(runes); i++ {
+		switch r := runes[i]; r {
+		case '*':
+			b.WriteString(".*")
+		case '?':
+			b.WriteString(".")
+		case '[':
+			end := slices.Index(runes[i+1:], ']')
+			if end <= 0 {
+				b.WriteString(regexp.QuoteMeta(string(r)))
+				continue
+			}
+			class := string(runes[i+1 : i+1+end])
+			if negated, ok := strings.CutPrefix(class, "!"); ok {
+				class = "^" + negated
+			}
+			b.WriteString("[" + strings.ReplaceAll(class, `\`, `\\`) + "]")
+			i += end + 1
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return b.String()
+}
@@ -0,0 +1,485 @@
+package client
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+)
+
+func TestObjectFilter_matchLastModified(t *testing.T) {
+	olderThan := time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)
+	newerThan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	cases := []struct {
+		name         string
+		filter       *ObjectFilter
+		lastModified *time.Time
+		want         bool
+	}{
+		{
+			name:         "nil filter matches all objects",
+			filter:       nil,
+			lastModified: aws.Time(olderThan),
+			want:         true,
+		},
+		{
+			name:         "empty filter matches objects without the time",
+			filter:       &ObjectFilter{},
+			lastModified: nil,
+			want:         true,
+		},
+		{
+			name:         "object before older than matches",
+			filter:       &ObjectFilter{OlderThan: &olderThan},
+			lastModified: aws.Time(olderThan.Add(-time.Second)),
+			want:         true,
+		},
+		{
+			name:         "object at older than does not match",
+			filter:       &ObjectFilter{OlderThan: &olderThan},
+			lastModified: aws.Time(olderThan),
+			want:         false,
+		},
+		{
+			name:         "object after newer than matches",
+			filter:       &ObjectFilter{NewerThan: &newerThan},
+			lastModified: aws.Time(newerThan.Add(time.Second)),
+			want:         true,
+		},
+		{
+			name:         "object at newer than does not match",
+			filter:       &ObjectFilter{NewerThan: &newerThan},
+			lastModified: aws.Time(newerThan),
+			want:         false,
+		},
+		{
+			name:         "object in the time window matches",
+			filter:       &ObjectFilter{OlderThan: &olderThan, NewerThan: &newerThan},
+			lastModified: aws.Time(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+			want:         true,
+		},
+		{
+			name:         "object without the time does not match the time filter",
+			filter:       &ObjectFilter{OlderThan: &olderThan},
+			lastModified: nil,
+			want:         false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matchLastModified(tt.lastModified); got != tt.want {
+				t.Errorf("matchLastModified() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectFilter_matchKey(t *testing.T) {
+	mustCompile := func(patterns ...string) []*regexp.Regexp {
+		compiled := []*regexp.Regexp{}
+		for _, pattern := range patterns {
+			re, err := CompileKeyPattern(pattern)
+			if err != nil {
+				t.Fatal(err)
+			}
+			compiled = append(compiled, re)
+		}
+		return compiled
+	}
+
+	cases := []struct {
+		name   string
+		filter *ObjectFilter
+		key    string
+		want   bool
+	}{
+		{
+			name:   "nil filter matches all objects",
+			filter: nil,
+			key:    "builds/1/app.zip",
+			want:   true,
+		},
+		{
+			name:   "empty filter matches all objects",
+			filter: &ObjectFilter{},
+			key:    "builds/1/app.zip",
+			want:   true,
+		},
+		{
+			name:   "object matching an include pattern matches",
+			filter: &ObjectFilter{Include: mustCompile("*.log", "*.zip")},
+			key:    "builds/1/app.zip",
+			want:   true,
+		},
+		{
+			name:   "object matching no include patterns does not match",
+			filter: &ObjectFilter{Include: mustCompile("*.log")},
+			key:    "builds/1/app.zip",
+			want:   false,
+		},
+		{
+			name:   "object matching an exclude pattern does not match",
+			filter: &ObjectFilter{Exclude: mustCompile("*/release/*")},
+			key:    "builds/1/release/app.zip",
+			want:   false,
+		},
+		{
+			name:   "exclude pattern takes precedence over include pattern",
+			filter: &ObjectFilter{Include: mustCompile("builds/*"), Exclude: mustCompile("*/release/*")},
+			key:    "builds/1/release/app.zip",
+			want:   false,
+		},
+		{
+			name:   "regular expression is searched in the key",
+			filter: &ObjectFilter{Include: mustCompile("regex:/[0-9]{1,3}/")},
+			key:    "builds/12/app.zip",
+			want:   true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matchKey(aws.String(tt.key)); got != tt.want {
+				t.Errorf("matchKey() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileKeyPattern(t *testing.T) {
+	cases := []struct {
+		pattern   string
+		matches   []string
+		unmatches []string
+		wantErr   bool
+	}{
+		{
+			pattern:   "builds/*.zip",
+			matches:   []string{"builds/app.zip", "builds/1/app.zip"},
+			unmatches: []string{"builds/app.zip.bak", "old/builds/app.zip"},
+		},
+		{
+			pattern:   "log-??.txt",
+			matches:   []string{"log-01.txt"},
+			unmatches: []string{"log-1.txt", "log-001.txt"},
+		},
+		{
+			pattern:   "data[0-9].csv",
+			matches:   []string{"data1.csv"},
+			unmatches: []string{"dataA.csv"},
+		},
+		{
+			pattern:   "data[!0-9].csv",
+			matches:   []string{"dataA.csv"},
+			unmatches: []string{"data1.csv"},
+		},
+		{
+			pattern:   "a+b(1).txt",
+			matches:   []string{"a+b(1).txt"},
+			unmatches: []string{"aab1.txt"},
+		},
+		{
+			pattern:   "tmp/*",
+			matches:   []string{"tmp/a\nb"},
+			unmatches: []string{"tmp"},
+		},
+		{
+			pattern:   "regex:^tmp/.*\\.bak$",
+			matches:   []string{"tmp/a.bak"},
+			unmatches: []string{"tmp/a.bak2", "tmp/abak"},
+		},
+		{
+			pattern: "regex:(",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.pattern, func(t *testing.T) {
+			re, err := CompileKeyPattern(tt.pattern)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("error = %v, wantErr %v", err, tt.wantErr)
+			}
+			for _, key := range tt.matches {
+				if !re.MatchString(key) {
+					t.Errorf("%q does not match %q", tt.pattern, key)
+				}
+			}
+			for _, key := range tt.unmatches {
+				if re.MatchString(key) {
+					t.Errorf("%q matches %q", tt.pattern, key)
+				}
+			}
+		})
+	}
+}
+
+func TestObjectFilter_matchAttributes(t *testing.T) {
+	cases := []struct {
+		name         string
+		filter       *ObjectFilter
+		storageClass string
+		size         *int64
+		want         bool
+	}{
+		{
+			name:         "nil filter matches all objects",
+			filter:       nil,
+			storageClass: "GLACIER",
+			size:         aws.Int64(1),
+			want:         true,
+		},
+		{
+			name:         "empty filter matches objects without the size",
+			filter:       &ObjectFilter{},
+			storageClass: "",
+			size:         nil,
+			want:         true,
+		},
+		{
+			name:         "object in the storage classes matches",
+			filter:       &ObjectFilter{StorageClasses: []string{"STANDARD", "STANDARD_IA"}},
+			storageClass: "STANDARD_IA",
+			size:         aws.Int64(1),
+			want:         true,
+		},
+		{
+			name:         "object in another storage class does not match",
+			filter:       &ObjectFilter{StorageClasses: []string{"STANDARD"}},
+			storageClass: "DEEP_ARCHIVE",
+			size:         aws.Int64(1),
+			want:         false,
+		},
+		{
+			name:         "object of the min size matches",
+			filter:       &ObjectFilter{MinSize: aws.Int64(100)},
+			storageClass: "STANDARD",
+			size:         aws.Int64(100),
+			want:         true,
+		},
+		{
+			name:         "object smaller than the min size does not match",
+			filter:       &ObjectFilter{MinSize: aws.Int64(100)},
+			storageClass: "STANDARD",
+			size:         aws.Int64(99),
+			want:         false,
+		},
+		{
+			name:         "object of the max size matches",
+			filter:       &ObjectFilter{MaxSize: aws.Int64(100)},
+			storageClass: "STANDARD",
+			size:         aws.Int64(100),
+			want:         true,
+		},
+		{
+			name:         "object larger than the max size does not match",
+			filter:       &ObjectFilter{MaxSize: aws.Int64(100)},
+			storageClass: "STANDARD",
+			size:         aws.Int64(101),
+			want:         false,
+		},
+		{
+			name:         "object without the size does not match the size filter",
+			filter:       &ObjectFilter{MinSize: aws.Int64(0)},
+			storageClass: "",
+			size:         nil,
+			want:         false,
+		},
+		{
+			name:         "object without the storage class does not match the storage class filter",
+			filter:       &ObjectFilter{StorageClasses: []string{"STANDARD"}},
+			storageClass: "",
+			size:         nil,
+			want:         false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.matchAttributes(tt.storageClass, tt.size); got != tt.want {
+				t.Errorf("matchAttributes() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectFilter_MatchObject(t *testing.T) {
+	now := time.Date(2025, 6, 15, 12, 0, 0, 0, time.UTC)
+	include, err := CompileKeyPattern("logs/*")
+	if err != nil {
+		t.Fatal(err)
+	}
+	filter := &ObjectFilter{
+		OlderThan: aws.Time(now),
+		MinSize:   aws.Int64(100),
+		Include:   []*regexp.Regexp{include},
+	}
+
+	cases := []struct {
+		name         string
+		filter       *ObjectFilter
+		key          string
+		lastModified *time.Time
+		storageClass string
+		size         *int64
+		want         bool
+	}{
+		{
+			name:   "nil filter matches all objects",
+			filter: nil,
+			key:    "Key",
+			want:   true,
+		},
+		{
+			name:         "object matching all conditions matches",
+			filter:       filter,
+			key:          "logs/a",
+			lastModified: aws.Time(now.Add(-time.Hour)),
+			storageClass: "STANDARD",
+			size:         aws.Int64(100),
+			want:         true,
+		},
+		{
+			name:         "object with another key does not match",
+			filter:       filter,
+			key:          "tmp/a",
+			lastModified: aws.Time(now.Add(-time.Hour)),
+			storageClass: "STANDARD",
+			size:         aws.Int64(100),
+			want:         false,
+		},
+		{
+			name:         "object last modified after the time does not match",
+			filter:       filter,
+			key:          "logs/a",
+			lastModified: aws.Time(now.Add(time.Hour)),
+			storageClass: "STANDARD",
+			size:         aws.Int64(100),
+			want:         false,
+		},
+		{
+			name:         "object smaller than the min size does not match",
+			filter:       filter,
+			key:          "logs/a",
+			lastModified: aws.Time(now.Add(-time.Hour)),
+			storageClass: "STANDARD",
+			size:         aws.Int64(99),
+			want:         false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.filter.MatchObject(tt.key, tt.lastModified, tt.storageClass, tt.size); got != tt.want {
+				t.Errorf("MatchObject() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObjectFilter_retainVersions(t *testing.T) {
+	day := func(d int) *time.Time {
+		return aws.Time(time.Date(2025, 1, d, 0, 0, 0, 0, time.UTC))
+	}
+	versions := []types.ObjectVersion{
+		{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(5)},
+		{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(3)},
+		{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(1)},
+	}
+	deleteMarkers := []types.DeleteMarkerEntry{
+		{Key: aws.String("KeyA"), IsLatest: aws.Bool(true), LastModified: day(10)},
+	}
+
+	cases := []struct {
+		name                  string
+		filter                *ObjectFilter
+		versions              []types.ObjectVersion
+		deleteMarkers         []types.DeleteMarkerEntry
+		cursor                *VersionsCursor
+		wantKeptVersions      []bool
+		wantKeptDeleteMarkers []bool
+		wantCursor            *VersionsCursor
+	}{
+		{
+			name:                  "nothing is kept without the retention",
+			filter:                &ObjectFilter{OlderThan: day(20)},
+			versions:              versions,
+			deleteMarkers:         deleteMarkers,
+			wantKeptVersions:      []bool{false, false, false},
+			wantKeptDeleteMarkers: []bool{false},
+			wantCursor:            nil,
+		},
+		{
+			name:                  "keep the newest noncurrent versions after the latest delete marker",
+			filter:                &ObjectFilter{KeepVersions: 1},
+			versions:              versions,
+			deleteMarkers:         deleteMarkers,
+			wantKeptVersions:      []bool{true, false, false},
+			wantKeptDeleteMarkers: []bool{true},
+			wantCursor:            &VersionsCursor{Key: "KeyA", NoncurrentCount: 3, LastModified: day(1)},
+		},
+		{
+			name:                  "keep the versions which became noncurrent after the time",
+			filter:                &ObjectFilter{KeepNoncurrentSince: day(4)},
+			versions:              versions,
+			deleteMarkers:         deleteMarkers,
+			wantKeptVersions:      []bool{true, true, false},
+			wantKeptDeleteMarkers: []bool{true},
+			wantCursor:            &VersionsCursor{Key: "KeyA", NoncurrentCount: 3, LastModified: day(1)},
+		},
+		{
+			name:                  "keep the versions matching either the number or the time",
+			filter:                &ObjectFilter{KeepVersions: 2, KeepNoncurrentSince: day(4)},
+			versions:              versions,
+			deleteMarkers:         deleteMarkers,
+			wantKeptVersions:      []bool{true, true, false},
+			wantKeptDeleteMarkers: []bool{true},
+			wantCursor:            &VersionsCursor{Key: "KeyA", NoncurrentCount: 3, LastModified: day(1)},
+		},
+		{
+			name:   "count the versions of the key in the previous page",
+			filter: &ObjectFilter{KeepVersions: 2},
+			versions: []types.ObjectVersion{
+				{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(3)},
+				{Key: aws.String("KeyB"), IsLatest: aws.Bool(true), LastModified: day(2)},
+			},
+			cursor:                &VersionsCursor{Key: "KeyA", NoncurrentCount: 2, LastModified: day(4)},
+			wantKeptVersions:      []bool{false, true},
+			wantKeptDeleteMarkers: []bool{},
+			wantCursor:            &VersionsCursor{Key: "KeyB", NoncurrentCount: 0, LastModified: day(2)},
+		},
+		{
+			name:   "keep the noncurrent versions whose newer versions are unknown",
+			filter: &ObjectFilter{KeepVersions: 1},
+			versions: []types.ObjectVersion{
+				{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(3)},
+				{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(2)},
+				{Key: aws.String("KeyA"), IsLatest: aws.Bool(false), LastModified: day(1)},
+			},
+			cursor:                nil,
+			wantKeptVersions:      []bool{true, false, false},
+			wantKeptDeleteMarkers: []bool{},
+			wantCursor:            &VersionsCursor{Key: "KeyA", NoncurrentCount: 3, LastModified: day(1)},
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			keptVersions, keptDeleteMarkers, cursor := tt.filter.retainVersions(tt.versions, tt.deleteMarkers, tt.cursor)
+			if !reflect.DeepEqual(keptVersions, tt.wantKeptVersions) {
+				t.Errorf("keptVersions = %v, want %v", keptVersions, tt.wantKeptVersions)
+			}
+			if !reflect.DeepEqual(keptDeleteMarkers, tt.wantKeptDeleteMarkers) {
+				t.Errorf("keptDeleteMarkers = %v, want %v", keptDeleteMarkers, tt.wantKeptDeleteMarkers)
+			}
+			if !reflect.DeepEqual(cursor, tt.wantCursor) {
+				t.Errorf("cursor = %#v, want %#v", cursor, tt.wantCursor)
+			}
+		})
+	}
+}
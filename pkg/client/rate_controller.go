@@ -0,0 +1,173 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+)
+
+const (
+	minRequestInterval = 50 * time.Millisecond
+	maxRequestInterval = 5 * time.Second
+)
+
+// RateController adapts the number of the requests in flight to the throttling responses in the AIMD
+// (additive-increase/multiplicative-decrease) way. It halves the limit when a request is throttled
+// and raises it by one for each limit's worth of the successful requests. When the limit cannot be
+// lowered any more, it spaces out the requests instead. Without the maximum, the requests are not
+// limited until the first throttle, which limits them to half of the ones in flight.
+//
+// It is shared by all clients via the context (see WithRateController), so that all buckets cleared
+// in parallel slow down together when S3 asks for it.
+type RateController struct {
+	maxLimit     float64 // unlimited if 0
+	limit        float64 // unlimited if 0
+	inFlight     int
+	interval     time.Duration // the time between the starts of the requests, only when the limit is 1
+	nextStart    time.Time
+	lastDecrease time.Time
+	changed      chan struct{} // closed and replaced when a request finishes
+	mtx          sync.Mutex
+	requests     atomic.Int64
+	throttles    atomic.Int64
+}
+
+// RateControllerStats is a snapshot of a RateController for the live display.
+type RateControllerStats struct {
+	Requests  int64 // the requests finished
+	Throttles int64 // the requests throttled
+	Limit     int   // the current limit of the requests in flight, or 0 if unlimited
+}
+
+// NewRateController creates a RateController that starts with the maximum number of the requests in flight,
+// or without the limit if it is not positive.
+func NewRateController(maxInFlight int) *RateController {
+	maxInFlight = max(0, maxInFlight)
+	return &RateController{
+		maxLimit: float64(maxInFlight),
+		limit:    float64(maxInFlight),
+		changed:  make(chan struct{}),
+	}
+}
+
+// Acquire waits until a request can be sent, and returns the function to be called with the result of it.
+func (c *RateController) Acquire(ctx context.Context) (func(error), error) {
+	for {
+		c.mtx.Lock()
+		now := time.Now()
+		if (c.limit == 0 || c.inFlight < int(c.limit)) && !now.Before(c.nextStart) {
+			c.inFlight++
+			if c.interval > 0 {
+				c.nextStart = now.Add(c.interval)
+			}
+			c.mtx.Unlock()
+			return func(err error) {
+				c.release(now, err)
+			}, nil
+		}
+		changed := c.changed
+		wait := c.nextStart.Sub(now)
+		c.mtx.Unlock()
+
+		if err := c.wait(ctx, changed, wait); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (c *RateController) wait(ctx context.Context, changed chan struct{}, wait time.Duration) error {
+	var timerCh <-chan time.Time
+	if wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		timerCh = timer.C
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-changed:
+	case <-timerCh:
+	}
+	return nil
+}
+
+func (c *RateController) release(startedAt time.Time, err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.inFlight--
+	c.requests.Add(1)
+
+	switch {
+	case IsThrottleError(err):
+		c.throttles.Add(1)
+		// NOTE: The requests sent before the last decrease were sent at the higher rate, so their throttles
+		// are not counted again. Otherwise, a burst of throttles would drop the limit to 1 at once.
+		if startedAt.After(c.lastDecrease) {
+			c.lastDecrease = time.Now()
+			if c.limit == 0 {
+				// NOTE: The requests in flight include this one, which has just finished.
+				c.limit = max(1, float64(c.inFlight+1)/2)
+			} else if c.limit > 1 {
+				c.limit = max(1, c.limit/2)
+			} else {
+				c.interval = min(max(c.interval*2, minRequestInterval), maxRequestInterval)
+			}
+		}
+	case err == nil:
+		if c.interval > 0 {
+			c.interval /= 2
+			if c.interval < minRequestInterval {
+				c.interval = 0
+			}
+		} else if c.limit > 0 && c.maxLimit == 0 {
+			c.limit += 1 / c.limit
+		} else if c.limit > 0 && c.limit < c.maxLimit {
+			c.limit = min(c.maxLimit, c.limit+1/c.limit)
+		}
+	}
+
+	close(c.changed)
+	c.changed = make(chan struct{})
+}
+
+// Stats returns the current numbers of the controller.
+func (c *RateController) Stats() RateControllerStats {
+	c.mtx.Lock()
+	limit := int(c.limit)
+	c.mtx.Unlock()
+	return RateControllerStats{
+		Requests:  c.requests.Load(),
+		Throttles: c.throttles.Load(),
+		Limit:     limit,
+	}
+}
+
+// IsThrottleError returns true if the error is a throttling response such as SlowDown, 503 or 429.
+func IsThrottleError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err) == aws.TrueTernary {
+		return true
+	}
+	statusCode, ok := httpStatusCode(err)
+	return ok && (statusCode == http.StatusServiceUnavailable || statusCode == http.StatusTooManyRequests)
+}
+
+type rateControllerKey struct{}
+
+// WithRateController returns a context with which the requests of the clients are controlled by the controller.
+func WithRateController(ctx context.Context, controller *RateController) context.Context {
+	return context.WithValue(ctx, rateControllerKey{}, controller)
+}
+
+func rateControllerFromContext(ctx context.Context) *RateController {
+	controller, _ := ctx.Value(rateControllerKey{}).(*RateController)
+	return controller
+}
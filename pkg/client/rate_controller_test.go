@@ -0,0 +1,323 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+func newStatusCodeError(statusCode int) error {
+	return &awshttp.ResponseError{
+		ResponseError: &smithyhttp.ResponseError{
+			Response: &smithyhttp.Response{
+				Response: &http.Response{StatusCode: statusCode},
+			},
+			Err: errors.New("response error"),
+		},
+	}
+}
+
+/*
+	Test Cases
+*/
+
+func TestIsThrottleError(t *testing.T) {
+	cases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{
+			name: "SlowDown error",
+			err:  &smithy.GenericAPIError{Code: "SlowDown", Message: "Please reduce your request rate."},
+			want: true,
+		},
+		{
+			name: "Throttling error",
+			err:  &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"},
+			want: true,
+		},
+		{
+			name: "503 error",
+			err:  newStatusCodeError(http.StatusServiceUnavailable),
+			want: true,
+		},
+		{
+			name: "429 error",
+			err:  newStatusCodeError(http.StatusTooManyRequests),
+			want: true,
+		},
+		{
+			name: "500 error",
+			err:  newStatusCodeError(http.StatusInternalServerError),
+			want: false,
+		},
+		{
+			name: "other api error",
+			err:  &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"},
+			want: false,
+		},
+		{
+			name: "other error",
+			err:  errors.New("error"),
+			want: false,
+		},
+		{
+			name: "nil error",
+			err:  nil,
+			want: false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := IsThrottleError(tt.err); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRateController_release(t *testing.T) {
+	throttleErr := &smithy.GenericAPIError{Code: "SlowDown", Message: "Please reduce your request rate."}
+
+	cases := []struct {
+		name          string
+		maxInFlight   int
+		limit         float64
+		interval      time.Duration
+		inFlight      int // the other requests in flight
+		errs          []error
+		sameStart     bool // the requests are started at the same time, before the first release
+		wantLimit     int
+		wantInterval  time.Duration
+		wantThrottles int64
+	}{
+		{
+			name:          "halve the limit on a throttle",
+			maxInFlight:   100,
+			limit:         100,
+			errs:          []error{throttleErr},
+			wantLimit:     50,
+			wantThrottles: 1,
+		},
+		{
+			name:          "halve the limit once for the requests started before the last decrease",
+			maxInFlight:   100,
+			limit:         100,
+			errs:          []error{throttleErr, throttleErr, throttleErr},
+			sameStart:     true,
+			wantLimit:     50,
+			wantThrottles: 3,
+		},
+		{
+			name:          "halve the limit for each throttle of the requests started after the last decrease",
+			maxInFlight:   100,
+			limit:         100,
+			errs:          []error{throttleErr, throttleErr, throttleErr},
+			wantLimit:     12,
+			wantThrottles: 3,
+		},
+		{
+			name:          "limit the requests to half of the ones in flight on the first throttle without the maximum",
+			maxInFlight:   0,
+			limit:         0,
+			inFlight:      39,
+			errs:          []error{throttleErr},
+			wantLimit:     20,
+			wantThrottles: 1,
+		},
+		{
+			name:          "space out the requests on a throttle when the limit is 1",
+			maxInFlight:   100,
+			limit:         1,
+			errs:          []error{throttleErr},
+			wantLimit:     1,
+			wantInterval:  minRequestInterval,
+			wantThrottles: 1,
+		},
+		{
+			name:          "space out the requests up to the maximum interval",
+			maxInFlight:   100,
+			limit:         1,
+			interval:      maxRequestInterval,
+			errs:          []error{throttleErr},
+			wantLimit:     1,
+			wantInterval:  maxRequestInterval,
+			wantThrottles: 1,
+		},
+		{
+			name:         "shorten the interval on a success",
+			maxInFlight:  100,
+			limit:        1,
+			interval:     4 * minRequestInterval,
+			errs:         []error{nil},
+			wantLimit:    1,
+			wantInterval: 2 * minRequestInterval,
+		},
+		{
+			name:        "stop spacing out the requests when the interval gets shorter than the minimum",
+			maxInFlight: 100,
+			limit:       1,
+			interval:    minRequestInterval,
+			errs:        []error{nil},
+			wantLimit:   1,
+		},
+		{
+			name:        "raise the limit by one for each limit's worth of successes",
+			maxInFlight: 100,
+			limit:       4,
+			errs:        []error{nil, nil, nil, nil},
+			wantLimit:   4, // 4.99...
+		},
+		{
+			name:        "raise the limit up to the maximum",
+			maxInFlight: 4,
+			limit:       4,
+			errs:        []error{nil, nil, nil, nil, nil},
+			wantLimit:   4,
+		},
+		{
+			name:        "raise the limit without the maximum",
+			maxInFlight: 0,
+			limit:       4,
+			errs:        []error{nil, nil, nil, nil, nil},
+			wantLimit:   5,
+		},
+		{
+			name:        "keep the requests unlimited on successes without the maximum",
+			maxInFlight: 0,
+			limit:       0,
+			errs:        []error{nil, nil},
+			wantLimit:   0,
+		},
+		{
+			name:        "keep the limit on other errors",
+			maxInFlight: 100,
+			limit:       10,
+			errs:        []error{errors.New("error")},
+			wantLimit:   10,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			controller := NewRateController(tt.maxInFlight)
+			controller.limit = tt.limit
+			controller.interval = tt.interval
+			controller.inFlight = tt.inFlight
+
+			startedAt := time.Now()
+			for _, err := range tt.errs {
+				if !tt.sameStart {
+					startedAt = time.Now()
+				}
+				controller.inFlight++
+				controller.release(startedAt, err)
+			}
+
+			stats := controller.Stats()
+			if stats.Limit != tt.wantLimit {
+				t.Errorf("limit = %v, want %v", stats.Limit, tt.wantLimit)
+			}
+			if controller.interval != tt.wantInterval {
+				t.Errorf("interval = %v, want %v", controller.interval, tt.wantInterval)
+			}
+			if stats.Throttles != tt.wantThrottles {
+				t.Errorf("throttles = %v, want %v", stats.Throttles, tt.wantThrottles)
+			}
+			if stats.Requests != int64(len(tt.errs)) {
+				t.Errorf("requests = %v, want %v", stats.Requests, len(tt.errs))
+			}
+			if controller.inFlight != tt.inFlight {
+				t.Errorf("inFlight = %v, want %v", controller.inFlight, tt.inFlight)
+			}
+		})
+	}
+}
+
+func TestRateController_Acquire(t *testing.T) {
+	controller := NewRateController(1)
+
+	done, err := controller.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	acquired := make(chan error)
+	go func() {
+		secondDone, err := controller.Acquire(context.Background())
+		if err == nil {
+			secondDone(nil)
+		}
+		acquired <- err
+	}()
+
+	select {
+	case <-acquired:
+		t.Fatal("Acquire did not wait for the request in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done(nil)
+	if err := <-acquired; err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
+
+func TestRateController_Acquire_Unlimited(t *testing.T) {
+	controller := NewRateController(0)
+
+	for i := 0; i < 1000; i++ {
+		done, err := controller.Acquire(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer done(nil)
+	}
+}
+
+func TestRateController_Acquire_Canceled(t *testing.T) {
+	controller := NewRateController(1)
+
+	done, err := controller.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer done(nil)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := controller.Acquire(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestRateController_Acquire_Interval(t *testing.T) {
+	controller := NewRateController(1)
+	controller.limit = 1
+	controller.interval = 100 * time.Millisecond
+
+	done, err := controller.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	startedAt := time.Now()
+	done(errors.New("error"))
+
+	done, err = controller.Acquire(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	done(nil)
+
+	if elapsed := time.Since(startedAt); elapsed < 90*time.Millisecond {
+		t.Errorf("elapsed = %v, want the interval of %v", elapsed, controller.interval)
+	}
+}
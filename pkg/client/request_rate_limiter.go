@@ -0,0 +1,79 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+)
+
+// RequestRateLimiter limits the rate of the requests sent through it, including the retries.
+// It is a token bucket that holds only one token, so the requests are spaced out evenly and never
+// exceed the rate even for a moment, whatever the number of the buckets cleared in parallel.
+//
+// It is shared by all clients via the context (see WithRequestRateLimiters).
+type RequestRateLimiter struct {
+	interval   time.Duration
+	deleteOnly bool      // limits only the delete operations (e.g. DeleteObjects) if true
+	next       time.Time // the time when the next token is available
+	mtx        sync.Mutex
+}
+
+// NewRequestRateLimiter creates a RequestRateLimiter that allows up to perSecond requests per second.
+// If deleteOnly is true, it limits only the delete operations such as DeleteObjects. perSecond must be positive.
+func NewRequestRateLimiter(perSecond int, deleteOnly bool) *RequestRateLimiter {
+	return &RequestRateLimiter{
+		interval:   time.Second / time.Duration(perSecond),
+		deleteOnly: deleteOnly,
+	}
+}
+
+// Wait waits until a request of the operation can be sent. It returns at once if the limiter is not for the operation.
+func (l *RequestRateLimiter) Wait(ctx context.Context, operation string) error {
+	if l.deleteOnly && !strings.HasPrefix(operation, "Delete") {
+		return nil
+	}
+
+	l.mtx.Lock()
+	now := time.Now()
+	sendAt := l.next
+	if sendAt.Before(now) {
+		sendAt = now
+	}
+	l.next = sendAt.Add(l.interval)
+	l.mtx.Unlock()
+
+	wait := sendAt.Sub(now)
+	if wait <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+type requestRateLimitersKey struct{}
+
+// WithRequestRateLimiters returns a context with which the requests of the clients are limited by the limiters.
+func WithRequestRateLimiters(ctx context.Context, limiters ...*RequestRateLimiter) context.Context {
+	return context.WithValue(ctx, requestRateLimitersKey{}, limiters)
+}
+
+// waitRequestRateLimiters waits for all limiters in the context for the operation of the request.
+func waitRequestRateLimiters(ctx context.Context) error {
+	limiters, _ := ctx.Value(requestRateLimitersKey{}).([]*RequestRateLimiter)
+	operation := awsmiddleware.GetOperationName(ctx)
+	for _, limiter := range limiters {
+		if err := limiter.Wait(ctx, operation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
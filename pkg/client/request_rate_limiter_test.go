@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+/*
+	Test Cases
+*/
+
+func TestRequestRateLimiter_Wait(t *testing.T) {
+	cases := []struct {
+		name        string
+		perSecond   int
+		deleteOnly  bool
+		operation   string
+		requests    int
+		wantElapsed time.Duration // the minimum time for the requests
+		wantMax     time.Duration // the maximum time for the requests
+	}{
+		{
+			name:        "space out the requests",
+			perSecond:   20,
+			operation:   "ListObjectVersions",
+			requests:    5,
+			wantElapsed: 200 * time.Millisecond, // the first one is sent at once
+			wantMax:     time.Second,
+		},
+		{
+			name:        "space out the delete requests with the limiter for the delete operations",
+			perSecond:   20,
+			deleteOnly:  true,
+			operation:   "DeleteObjects",
+			requests:    5,
+			wantElapsed: 200 * time.Millisecond,
+			wantMax:     time.Second,
+		},
+		{
+			name:        "do not wait for the other requests with the limiter for the delete operations",
+			perSecond:   1,
+			deleteOnly:  true,
+			operation:   "ListObjectVersions",
+			requests:    5,
+			wantElapsed: 0,
+			wantMax:     100 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewRequestRateLimiter(tt.perSecond, tt.deleteOnly)
+
+			startedAt := time.Now()
+			for range tt.requests {
+				if err := limiter.Wait(context.Background(), tt.operation); err != nil {
+					t.Fatal(err)
+				}
+			}
+			elapsed := time.Since(startedAt)
+
+			if elapsed < tt.wantElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.wantElapsed)
+			}
+			if elapsed > tt.wantMax {
+				t.Errorf("elapsed = %v, want at most %v", elapsed, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestRequestRateLimiter_Wait_Canceled(t *testing.T) {
+	limiter := NewRequestRateLimiter(1, false)
+	if err := limiter.Wait(context.Background(), "DeleteObjects"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx, "DeleteObjects"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("err = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestWaitRequestRateLimiters(t *testing.T) {
+	ctx := WithRequestRateLimiters(
+		context.Background(),
+		NewRequestRateLimiter(20, false),
+		NewRequestRateLimiter(1000, true),
+	)
+
+	startedAt := time.Now()
+	for range 3 {
+		if err := waitRequestRateLimiters(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if elapsed := time.Since(startedAt); elapsed < 100*time.Millisecond {
+		t.Errorf("elapsed = %v, want at least %v", elapsed, 100*time.Millisecond)
+	}
+
+	if err := waitRequestRateLimiters(context.Background()); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+}
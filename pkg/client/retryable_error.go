@@ -0,0 +1,122 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"maps"
+	"net"
+
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// DefaultS3RetryableErrorCodes are the API error codes of S3 retried by default.
+var DefaultS3RetryableErrorCodes = []string{"SlowDown", "InternalError", "ServiceUnavailable"}
+
+// DefaultS3TablesRetryableErrorCodes are the API error codes of S3 Tables retried by default.
+var DefaultS3TablesRetryableErrorCodes = []string{"SlowDown", "InternalError", "InternalServerErrorException", "TooManyRequestsException"}
+
+// DefaultS3VectorsRetryableErrorCodes are the API error codes of S3 Vectors retried by default.
+var DefaultS3VectorsRetryableErrorCodes = []string{
+	"SlowDown",
+	"InternalError",
+	"InternalServerException",
+	"ServiceUnavailableException",
+	"TooManyRequestsException",
+}
+
+// RetryableErrorClassifier decides whether a failed request is retried by the type of the error,
+// not by its message, which can contain the bucket names and the keys.
+//
+// In addition to the API error codes and the HTTP status codes given to it, the errors of the connection
+// (e.g. EOF or a reset) and of reading the response are retried. The errors of the context are never retried.
+type RetryableErrorClassifier struct {
+	codes       map[string]struct{}
+	statusCodes map[int]struct{}
+}
+
+// NewRetryableErrorClassifier creates a RetryableErrorClassifier that retries the errors with the API error codes
+// or the HTTP status codes.
+func NewRetryableErrorClassifier(codes []string, statusCodes []int) *RetryableErrorClassifier {
+	c := &RetryableErrorClassifier{
+		codes:       make(map[string]struct{}, len(codes)),
+		statusCodes: make(map[int]struct{}, len(statusCodes)),
+	}
+	for _, code := range codes {
+		c.codes[code] = struct{}{}
+	}
+	for _, statusCode := range statusCodes {
+		c.statusCodes[statusCode] = struct{}{}
+	}
+	return c
+}
+
+// WithCodes returns a copy of the classifier that also retries the errors with the API error codes.
+func (c *RetryableErrorClassifier) WithCodes(codes ...string) *RetryableErrorClassifier {
+	copied := &RetryableErrorClassifier{
+		codes:       maps.Clone(c.codes),
+		statusCodes: maps.Clone(c.statusCodes),
+	}
+	for _, code := range codes {
+		copied.codes[code] = struct{}{}
+	}
+	return copied
+}
+
+// IsRetryable returns true if the request that failed with the error should be retried.
+func (c *RetryableErrorClassifier) IsRetryable(err error) bool {
+	if err == nil {
+		return false
+	}
+	// NOTE: The requests canceled by the user or timed out are not retried even if the cause is a connection error.
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+
+	var apiErr smithy.APIError
+	if errors.As(err, &apiErr) {
+		if _, ok := c.codes[apiErr.ErrorCode()]; ok {
+			return true
+		}
+	}
+	if statusCode, ok := httpStatusCode(err); ok {
+		if _, ok := c.statusCodes[statusCode]; ok {
+			return true
+		}
+	}
+
+	// e.g. operation error S3: DeleteObjects, https response error StatusCode: 0, RequestID: , HostID: ,
+	// request send failed, Post "https://xxx.s3.us-east-1.amazonaws.com/?delete=": EOF
+	// See: https://github.com/go-to-k/cls3/issues/194
+	var sendErr *smithyhttp.RequestSendError
+	if errors.As(err, &sendErr) {
+		return true
+	}
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		return true
+	}
+	// e.g. operation error S3: DeleteObjects, https response error StatusCode: 200, RequestID: xxx, HostID: ,
+	// deserialization failed, failed to decode response body, stream error: stream ID 9; CANCEL; received from peer
+	var deserializationErr *smithy.DeserializationError
+	return errors.As(err, &deserializationErr)
+}
+
+// httpStatusCode returns the HTTP status code of the response of the error if it has one.
+func httpStatusCode(err error) (int, bool) {
+	var responseErr interface {
+		HTTPResponse() *smithyhttp.Response
+	}
+	if !errors.As(err, &responseErr) {
+		return 0, false
+	}
+	response := responseErr.HTTPResponse()
+	if response == nil || response.Response == nil {
+		return 0, false
+	}
+	return response.StatusCode, true
+}
@@ -0,0 +1,232 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"testing"
+
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/smithy-go"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+)
+
+// newRecordedError returns an error in the same shape as the SDK returns for a response of the operation.
+func newRecordedError(service string, operation string, statusCode int, err error) error {
+	return &smithy.OperationError{
+		ServiceID:     service,
+		OperationName: operation,
+		Err: &awshttp.ResponseError{
+			ResponseError: &smithyhttp.ResponseError{
+				Response: &smithyhttp.Response{
+					Response: &http.Response{StatusCode: statusCode},
+				},
+				Err: err,
+			},
+			RequestID: "xxxxxx",
+		},
+	}
+}
+
+/*
+	Test Cases
+*/
+
+func TestRetryableErrorClassifier_IsRetryable(t *testing.T) {
+	s3Classifier := NewRetryableErrorClassifier(DefaultS3RetryableErrorCodes, []int{http.StatusServiceUnavailable})
+	s3TablesClassifier := NewRetryableErrorClassifier(DefaultS3TablesRetryableErrorCodes, []int{http.StatusTooManyRequests})
+	s3VectorsClassifier := NewRetryableErrorClassifier(
+		DefaultS3VectorsRetryableErrorCodes,
+		[]int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	)
+
+	cases := []struct {
+		name       string
+		classifier *RetryableErrorClassifier
+		err        error
+		want       bool
+	}{
+		{
+			name:       "S3 SlowDown",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "DeleteObjects", 503, &smithy.GenericAPIError{
+				Code:    "SlowDown",
+				Message: "Please reduce your request rate.",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3 InternalError",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "ListObjectVersions", 500, &smithy.GenericAPIError{
+				Code:    "InternalError",
+				Message: "We encountered an internal error. Please try again.",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3 503 without an error code",
+			classifier: s3Classifier,
+			err:        newRecordedError("S3", "HeadBucket", 503, &smithy.GenericAPIError{}),
+			want:       true,
+		},
+		{
+			name:       "S3 request send failed with EOF",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "DeleteObjects", 0, &smithyhttp.RequestSendError{
+				Err: &url.Error{Op: "Post", URL: "https://test-bucket.s3.us-east-1.amazonaws.com/?delete=", Err: io.EOF},
+			}),
+			want: true,
+		},
+		{
+			name:       "S3 deserialization failed with a stream error",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "DeleteObjects", 200, &smithy.DeserializationError{
+				Err: errors.New("failed to decode response body, stream error: stream ID 9; CANCEL; received from peer"),
+			}),
+			want: true,
+		},
+		{
+			name:       "S3 connection reset",
+			classifier: s3Classifier,
+			err: &smithy.OperationError{
+				ServiceID:     "S3",
+				OperationName: "ListObjectsV2",
+				Err:           &net.OpError{Op: "read", Net: "tcp", Err: syscall.ECONNRESET},
+			},
+			want: true,
+		},
+		{
+			name:       "S3 unexpected EOF",
+			classifier: s3Classifier,
+			err:        &smithy.OperationError{ServiceID: "S3", OperationName: "GetObject", Err: io.ErrUnexpectedEOF},
+			want:       true,
+		},
+		{
+			name:       "S3 AccessDenied for a bucket whose name contains the retryable words",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "DeleteObjects", 403, &smithy.GenericAPIError{
+				Code:    "AccessDenied",
+				Message: "Access Denied for please-try-again-eof-bucket",
+			}),
+			want: false,
+		},
+		{
+			name:       "S3 NoSuchBucket",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "ListObjectVersions", 404, &smithy.GenericAPIError{
+				Code:    "NoSuchBucket",
+				Message: "The specified bucket does not exist",
+			}),
+			want: false,
+		},
+		{
+			name:       "S3 request canceled",
+			classifier: s3Classifier,
+			err: newRecordedError("S3", "DeleteObjects", 0, &smithyhttp.RequestSendError{
+				Err: &url.Error{Op: "Post", URL: "https://test-bucket.s3.us-east-1.amazonaws.com/?delete=", Err: context.Canceled},
+			}),
+			want: false,
+		},
+		{
+			name:       "S3 request timed out by the context",
+			classifier: s3Classifier,
+			err:        &smithy.OperationError{ServiceID: "S3", OperationName: "DeleteObjects", Err: context.DeadlineExceeded},
+			want:       false,
+		},
+		{
+			name:       "S3 additional code",
+			classifier: s3Classifier.WithCodes("AccessDenied"),
+			err: newRecordedError("S3", "DeleteObjects", 403, &smithy.GenericAPIError{
+				Code:    "AccessDenied",
+				Message: "Access Denied",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3Tables InternalServerErrorException",
+			classifier: s3TablesClassifier,
+			err: newRecordedError("S3Tables", "DeleteTable", 500, &smithy.GenericAPIError{
+				Code:    "InternalServerErrorException",
+				Message: "An internal error occurred. Try again.",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3Tables 429",
+			classifier: s3TablesClassifier,
+			err:        newRecordedError("S3Tables", "ListTables", 429, &smithy.GenericAPIError{}),
+			want:       true,
+		},
+		{
+			name:       "S3Tables ConflictException",
+			classifier: s3TablesClassifier,
+			err: newRecordedError("S3Tables", "DeleteNamespace", 409, &smithy.GenericAPIError{
+				Code:    "ConflictException",
+				Message: "The namespace is not empty.",
+			}),
+			want: false,
+		},
+		{
+			name:       "S3Vectors ServiceUnavailableException",
+			classifier: s3VectorsClassifier,
+			err: newRecordedError("S3Vectors", "DeleteIndex", 503, &smithy.GenericAPIError{
+				Code:    "ServiceUnavailableException",
+				Message: "Service unavailable",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3Vectors TooManyRequestsException",
+			classifier: s3VectorsClassifier,
+			err: newRecordedError("S3Vectors", "ListIndexes", 429, &smithy.GenericAPIError{
+				Code:    "TooManyRequestsException",
+				Message: "Too many requests",
+			}),
+			want: true,
+		},
+		{
+			name:       "S3Vectors ValidationException",
+			classifier: s3VectorsClassifier,
+			err: newRecordedError("S3Vectors", "DeleteIndex", 400, &smithy.GenericAPIError{
+				Code:    "ValidationException",
+				Message: "Invalid index name",
+			}),
+			want: false,
+		},
+		{
+			name:       "nil error",
+			classifier: s3Classifier,
+			err:        nil,
+			want:       false,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.classifier.IsRetryable(tt.err); got != tt.want {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryableErrorClassifier_WithCodes(t *testing.T) {
+	classifier := NewRetryableErrorClassifier([]string{"SlowDown"}, nil)
+	copied := classifier.WithCodes("AccessDenied")
+
+	err := &smithy.GenericAPIError{Code: "AccessDenied", Message: "Access Denied"}
+	if classifier.IsRetryable(err) {
+		t.Error("the original classifier retries the added code")
+	}
+	if !copied.IsRetryable(err) {
+		t.Error("the copied classifier does not retry the added code")
+	}
+	if !copied.IsRetryable(&smithy.GenericAPIError{Code: "SlowDown"}) {
+		t.Error("the copied classifier does not retry the original code")
+	}
+}
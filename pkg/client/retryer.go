@@ -1,6 +1,7 @@
 package client
 
 import (
+	"context"
 	"math/rand/v2"
 	"time"
 
@@ -9,16 +10,34 @@ import (
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 )
 
+// MaxAttempts is the default number of the attempts of a request, including the first one.
 const MaxAttempts = 20
 
+// DefaultRetryBaseDelay is the default delay before the first retry.
+const DefaultRetryBaseDelay = time.Second
+
+// RetryOptions configures the retries of the requests of a client.
+type RetryOptions struct {
+	MaxAttempts         int           // the number of the attempts including the first one, MaxAttempts if not positive
+	BaseDelay           time.Duration // the cap of the delay before the first retry, doubled for each retry
+	MaxDelay            time.Duration // the cap of the delay for all retries
+	RetryableErrorCodes []string      // retried in addition to the default codes of the client
+}
+
 type Retryer struct {
 	aws.RetryerV2
 }
 
-func NewRetryer(isErrorRetryableFunc func(error) bool, delayTimeSec int) *Retryer {
+// NewRetryer creates a Retryer that waits a random time up to the capped exponential delay (full jitter)
+// before each retry.
+func NewRetryer(isErrorRetryableFunc func(error) bool, options RetryOptions) *Retryer {
+	maxAttempts := options.MaxAttempts
+	if maxAttempts <= 0 {
+		maxAttempts = MaxAttempts
+	}
 	retryer := retry.NewStandard(func(o *retry.StandardOptions) {
-		o.MaxAttempts = MaxAttempts
-		o.Backoff = retry.BackoffDelayerFunc(backoffDelay(delayTimeSec))
+		o.MaxAttempts = maxAttempts
+		o.Backoff = retry.BackoffDelayerFunc(backoffDelay(options.BaseDelay, options.MaxDelay))
 		o.Retryables = append(o.Retryables, retry.IsErrorRetryableFunc(checkErrorRetryable(isErrorRetryableFunc)))
 		o.RateLimiter = ratelimit.None
 	})
@@ -28,14 +47,60 @@ func NewRetryer(isErrorRetryableFunc func(error) bool, delayTimeSec int) *Retrye
 	}
 }
 
-func backoffDelay(delayTimeSec int) func(int, error) (time.Duration, error) {
+// GetAttemptToken waits for the RequestRateLimiters and the RateController in the context, if any, before each attempt
+// including the retries, and reports the result of the attempt to the controller.
+func (r *Retryer) GetAttemptToken(ctx context.Context) (func(error) error, error) {
+	if err := waitRequestRateLimiters(ctx); err != nil {
+		return nil, err
+	}
+
+	controller := rateControllerFromContext(ctx)
+	if controller == nil {
+		return r.RetryerV2.GetAttemptToken(ctx)
+	}
+
+	done, err := controller.Acquire(ctx)
+	if err != nil {
+		return nil, err
+	}
+	release, err := r.RetryerV2.GetAttemptToken(ctx)
+	if err != nil {
+		done(err)
+		return nil, err
+	}
+	return func(err error) error {
+		done(err)
+		return release(err)
+	}, nil
+}
+
+// sleepWithContext sleeps for the duration, or returns the error of the context if it is canceled before that.
+func sleepWithContext(ctx context.Context, duration time.Duration) error {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func backoffDelay(baseDelay time.Duration, maxDelay time.Duration) func(int, error) (time.Duration, error) {
 	return func(attempt int, err error) (time.Duration, error) {
-		waitTime := 1
-		if delayTimeSec > 1 {
-			//nolint:gosec
-			waitTime += rand.IntN(delayTimeSec)
+		ceiling := min(baseDelay, maxDelay)
+		for i := 1; i < attempt && ceiling < maxDelay; i++ {
+			if ceiling > maxDelay/2 {
+				ceiling = maxDelay
+			} else {
+				ceiling *= 2
+			}
+		}
+		if ceiling <= 0 {
+			return 0, nil
 		}
-		return time.Duration(waitTime) * time.Second, nil
+		//nolint:gosec
+		return time.Duration(rand.Int64N(int64(ceiling))), nil
 	}
 }
 
@@ -6,6 +6,7 @@ import (
 	"strings"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -180,7 +181,7 @@ func TestCheckErrorRetryable_Integration(t *testing.T) {
 
 			retryer := NewRetryer(func(err error) bool {
 				return errors.Is(err, retryableErr)
-			}, 0)
+			}, RetryOptions{})
 
 			cfg, err := config.LoadDefaultConfig(
 				context.Background(),
@@ -223,7 +224,7 @@ func TestRetryer_NoRateLimitQuotaExceeded(t *testing.T) {
 
 	retryer := NewRetryer(func(err error) bool {
 		return errors.Is(err, retryableErr)
-	}, 0)
+	}, RetryOptions{})
 
 	cfg, err := config.LoadDefaultConfig(
 		context.Background(),
@@ -285,3 +286,255 @@ func TestRetryer_NoRateLimitQuotaExceeded(t *testing.T) {
 		t.Errorf("attemptCount = %d, want %d", attemptCount.Load(), expectedAttempts)
 	}
 }
+
+func TestRetryer_GetAttemptToken_RateController(t *testing.T) {
+	var attemptCount atomic.Int32
+
+	retryer := NewRetryer(func(err error) bool {
+		return false
+	}, RetryOptions{})
+
+	cfg, err := config.LoadDefaultConfig(
+		context.Background(),
+		config.WithRegion("us-east-1"),
+		config.WithCredentialsProvider(aws.AnonymousCredentials{}),
+		config.WithRetryer(func() aws.Retryer { return retryer }),
+		config.WithAPIOptions([]func(*middleware.Stack) error{
+			func(stack *middleware.Stack) error {
+				return stack.Finalize.Add(
+					middleware.FinalizeMiddlewareFunc(
+						"SlowDownThenSuccessMock",
+						func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+							if attemptCount.Add(1) == 1 {
+								return middleware.FinalizeOutput{
+									Result: nil,
+								}, middleware.Metadata{}, &smithy.GenericAPIError{
+									Code:    "SlowDown",
+									Message: "Please reduce your request rate.",
+								}
+							}
+							return middleware.FinalizeOutput{
+								Result: &s3.ListBucketsOutput{},
+							}, middleware.Metadata{}, nil
+						},
+					),
+					middleware.After,
+				)
+			},
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	controller := NewRateController(4)
+	ctx := WithRateController(context.Background(), controller)
+
+	client := s3.NewFromConfig(cfg)
+	if _, err := client.ListBuckets(ctx, &s3.ListBucketsInput{}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := controller.Stats()
+	if stats.Requests != 2 {
+		t.Errorf("requests = %d, want 2", stats.Requests)
+	}
+	if stats.Throttles != 1 {
+		t.Errorf("throttles = %d, want 1", stats.Throttles)
+	}
+	// NOTE: The limit is halved from 4 on the throttle, then raised by 1/2 on the success.
+	if stats.Limit != 2 {
+		t.Errorf("limit = %d, want 2", stats.Limit)
+	}
+}
+
+func TestRetryer_GetAttemptToken_RequestRateLimiters(t *testing.T) {
+	cases := []struct {
+		name        string
+		limiter     *RequestRateLimiter
+		wantElapsed time.Duration // the minimum time for the requests
+		wantMax     time.Duration // the maximum time for the requests
+	}{
+		{
+			name:        "limit the requests",
+			limiter:     NewRequestRateLimiter(10, false),
+			wantElapsed: 200 * time.Millisecond,
+			wantMax:     time.Second,
+		},
+		{
+			name:        "do not limit the requests other than the delete operations with the limiter for them",
+			limiter:     NewRequestRateLimiter(1, true),
+			wantElapsed: 0,
+			wantMax:     500 * time.Millisecond,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			retryer := NewRetryer(func(err error) bool {
+				return false
+			}, RetryOptions{})
+
+			cfg, err := config.LoadDefaultConfig(
+				context.Background(),
+				config.WithRegion("us-east-1"),
+				config.WithCredentialsProvider(aws.AnonymousCredentials{}),
+				config.WithRetryer(func() aws.Retryer { return retryer }),
+				config.WithAPIOptions([]func(*middleware.Stack) error{
+					func(stack *middleware.Stack) error {
+						return stack.Finalize.Add(
+							middleware.FinalizeMiddlewareFunc(
+								"SuccessMock",
+								func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+									return middleware.FinalizeOutput{
+										Result: &s3.ListBucketsOutput{},
+									}, middleware.Metadata{}, nil
+								},
+							),
+							middleware.After,
+						)
+					},
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			ctx := WithRequestRateLimiters(context.Background(), tt.limiter)
+			client := s3.NewFromConfig(cfg)
+
+			startedAt := time.Now()
+			for range 3 {
+				if _, err := client.ListBuckets(ctx, &s3.ListBucketsInput{}); err != nil {
+					t.Fatal(err)
+				}
+			}
+			elapsed := time.Since(startedAt)
+
+			if elapsed < tt.wantElapsed {
+				t.Errorf("elapsed = %v, want at least %v", elapsed, tt.wantElapsed)
+			}
+			if elapsed > tt.wantMax {
+				t.Errorf("elapsed = %v, want at most %v", elapsed, tt.wantMax)
+			}
+		})
+	}
+}
+
+func TestBackoffDelay(t *testing.T) {
+	tests := []struct {
+		name       string
+		baseDelay  time.Duration
+		maxDelay   time.Duration
+		attempt    int
+		wantMaxLen time.Duration // the delay is random between 0 and this
+	}{
+		{
+			name:       "first retry waits up to the base delay",
+			baseDelay:  time.Second,
+			maxDelay:   20 * time.Second,
+			attempt:    1,
+			wantMaxLen: time.Second,
+		},
+		{
+			name:       "third retry waits up to four times the base delay",
+			baseDelay:  time.Second,
+			maxDelay:   20 * time.Second,
+			attempt:    3,
+			wantMaxLen: 4 * time.Second,
+		},
+		{
+			name:       "later retry waits up to the max delay",
+			baseDelay:  time.Second,
+			maxDelay:   20 * time.Second,
+			attempt:    19,
+			wantMaxLen: 20 * time.Second,
+		},
+		{
+			name:       "base delay longer than the max delay is capped",
+			baseDelay:  time.Minute,
+			maxDelay:   3 * time.Second,
+			attempt:    1,
+			wantMaxLen: 3 * time.Second,
+		},
+		{
+			name:       "huge max delay does not overflow",
+			baseDelay:  time.Second,
+			maxDelay:   time.Duration(1<<63 - 1),
+			attempt:    100,
+			wantMaxLen: time.Duration(1<<63 - 1),
+		},
+		{
+			name:       "no delay",
+			baseDelay:  0,
+			maxDelay:   0,
+			attempt:    5,
+			wantMaxLen: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			delayFn := backoffDelay(tt.baseDelay, tt.maxDelay)
+			var longest time.Duration
+			for range 1000 {
+				delay, err := delayFn(tt.attempt, nil)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if delay < 0 || delay > tt.wantMaxLen {
+					t.Fatalf("delay = %v, want between 0 and %v", delay, tt.wantMaxLen)
+				}
+				longest = max(longest, delay)
+			}
+			// NOTE: With full jitter, some of the delays should be longer than half of the cap.
+			if longest < tt.wantMaxLen/2 {
+				t.Errorf("longest delay = %v, want longer than %v", longest, tt.wantMaxLen/2)
+			}
+		})
+	}
+}
+
+func TestNewRetryer_MaxAttempts(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+		want        int
+	}{
+		{
+			name:        "default max attempts",
+			maxAttempts: 0,
+			want:        MaxAttempts,
+		},
+		{
+			name:        "specified max attempts",
+			maxAttempts: 1,
+			want:        1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			retryer := NewRetryer(func(error) bool { return false }, RetryOptions{MaxAttempts: tt.maxAttempts})
+			if got := retryer.MaxAttempts(); got != tt.want {
+				t.Errorf("MaxAttempts() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSleepWithContext(t *testing.T) {
+	if err := sleepWithContext(context.Background(), 10*time.Millisecond); err != nil {
+		t.Errorf("err = %v, want nil", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	startedAt := time.Now()
+	if err := sleepWithContext(ctx, time.Hour); !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v, want %v", err, context.Canceled)
+	}
+	if elapsed := time.Since(startedAt); elapsed > time.Second {
+		t.Errorf("elapsed = %v, want to return at once when the context is canceled", elapsed)
+	}
+}
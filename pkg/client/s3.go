@@ -3,27 +3,47 @@ package client
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net/http"
 	"sort"
-	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/go-to-k/cls3/pkg/endpoint"
 )
 
+// SleepTimeSecForS3 is the default cap of the delay of the retries for S3 in seconds.
 var SleepTimeSecForS3 = 20
 
+// MaxDeleteObjectsCount is the maximum number of objects that can be deleted in one DeleteObjects.
+const MaxDeleteObjectsCount = 1000
+
+// MaxListBucketsCount is the maximum number of buckets returned by a ListBuckets call.
+const MaxListBucketsCount = 10000
+
+// ListObjectsOrVersionsByPageOutput holds one page of deletion targets.
+// ObjectIdentifiers holds versions first and delete markers last, and the counts show the breakdown of them.
 type ListObjectsOrVersionsByPageOutput struct {
 	ObjectIdentifiers   []types.ObjectIdentifier
 	NextKeyMarker       *string
 	NextVersionIdMarker *string
+	ObjectsCount        int // latest versions, or all objects when versions are not listed
+	VersionsCount       int // noncurrent versions
+	DeleteMarkersCount  int
+	VersionsCursor      *VersionsCursor // to be passed with the next page if the filter has the retention
 }
 type listObjectVersionsByPageOutput struct {
 	ObjectIdentifiers   []types.ObjectIdentifier
 	NextKeyMarker       *string
 	NextVersionIdMarker *string
+	ObjectsCount        int
+	VersionsCount       int
+	DeleteMarkersCount  int
+	VersionsCursor      *VersionsCursor
 }
 type listObjectsByPageOutput struct {
 	ObjectIdentifiers []types.ObjectIdentifier
@@ -46,9 +66,15 @@ type IS3 interface {
 		keyMarker *string,
 		versionIdMarker *string,
 		keyPrefix *string,
+		filter *ObjectFilter,
+		versionsCursor *VersionsCursor,
+		keyRange *KeyRange,
 	) (*ListObjectsOrVersionsByPageOutput, error)
+	ListCommonPrefixes(ctx context.Context, bucketName *string, region string, keyPrefix *string, delimiter string) ([]string, error)
 	ListBucketsOrDirectoryBuckets(ctx context.Context) ([]types.Bucket, error)
 	GetBucketLocation(ctx context.Context, bucketName *string) (string, error)
+	GetBucketTagging(ctx context.Context, bucketName *string, region string) (map[string]string, error)
+	GetObject(ctx context.Context, bucketName *string, key *string, region string) (io.ReadCloser, error)
 }
 
 var _ IS3 = (*S3)(nil)
@@ -57,37 +83,50 @@ type S3 struct {
 	client               *s3.Client
 	directoryBucketsMode bool
 	retryer              *Retryer
+	classifier           *RetryableErrorClassifier // also classifies the errors of the objects in DeleteObjects
+	expectedBucketOwner  *string
 }
 
-func NewS3(client *s3.Client, directoryBucketsMode bool) *S3 {
-	retryable := func(err error) bool {
-		isRetryable :=
-			strings.Contains(err.Error(), "api error SlowDown") ||
-				// See: https://github.com/go-to-k/cls3/issues/194
-				strings.Contains(err.Error(), "https response error StatusCode: 0") ||
-				// ex: ERR [resource par-cls-019] operation error S3: DeleteObjects, https response error StatusCode: 0, RequestID: , HostID: , request send failed, Post "https://par-cls-019.s3.us-east-1.amazonaws.com/?delete=": EOF
-				// but one condition above, it didn't catch on.
-				strings.Contains(err.Error(), "EOF") ||
-				// ex: ERR [resource cls3-test-xxx] operation error S3: ListObjectVersions, https response error StatusCode: 500, RequestID: xxxxxx, HostID: xxxxxx=, api error InternalError: We encountered an internal error. Please try again.
-				strings.Contains(err.Error(), "Please try again") ||
-				// ex: ERR [resource cls3-test-xxx] operation error S3: DeleteObjects, https response error StatusCode: 200, RequestID: xxxxxx, HostID: , deserialization failed, failed to decode response body, stream error: stream ID 9; CANCEL; received from peer
-				strings.Contains(err.Error(), "deserialization failed")
-
-		return isRetryable
+// NewS3 creates an S3 client. The retries of its requests can be configured with optFns.
+func NewS3(client *s3.Client, directoryBucketsMode bool, optFns ...func(*RetryOptions)) *S3 {
+	options := RetryOptions{
+		MaxAttempts: MaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    time.Duration(SleepTimeSecForS3) * time.Second,
+	}
+	for _, optFn := range optFns {
+		optFn(&options)
 	}
-	retryer := NewRetryer(retryable, SleepTimeSecForS3)
+
+	classifier := NewRetryableErrorClassifier(DefaultS3RetryableErrorCodes, []int{http.StatusServiceUnavailable}).
+		WithCodes(options.RetryableErrorCodes...)
+	retryer := NewRetryer(classifier.IsRetryable, options)
 
 	return &S3{
-		client,
-		directoryBucketsMode,
-		retryer,
+		client:               client,
+		directoryBucketsMode: directoryBucketsMode,
+		retryer:              retryer,
+		classifier:           classifier,
+	}
+}
+
+// WithExpectedBucketOwner makes the requests to list and delete the objects and to delete the buckets
+// fail with 403 Forbidden if the bucket is not owned by the account. Nothing is checked if it is empty.
+func (s *S3) WithExpectedBucketOwner(accountId string) *S3 {
+	if accountId != "" {
+		s.expectedBucketOwner = aws.String(accountId)
 	}
+	return s
 }
 
 func (s *S3) DeleteBucket(ctx context.Context, bucketName *string, region string) error {
 	input := &s3.DeleteBucketInput{
 		Bucket: bucketName,
 	}
+	// NOTE: Directory Buckets do not support the expected bucket owner for DeleteBucket (501 Not Implemented).
+	if !s.directoryBucketsMode {
+		input.ExpectedBucketOwner = s.expectedBucketOwner
+	}
 
 	optFn := func(o *s3.Options) {
 		o.Retryer = s.retryer
@@ -138,6 +177,7 @@ func (s *S3) DeleteObjects(
 				Objects: objects,
 				Quiet:   aws.Bool(true),
 			},
+			ExpectedBucketOwner: s.expectedBucketOwner,
 		}
 
 		optFn := func(o *s3.Options) {
@@ -161,7 +201,8 @@ func (s *S3) DeleteObjects(
 
 		retryCounts++
 
-		if retryCounts > s.retryer.MaxAttempts() {
+		// NOTE: The attempts include the first one, so the objects are sent up to MaxAttempts times in total.
+		if retryCounts >= s.retryer.MaxAttempts() {
 			errors = append(errors, output.Errors...)
 			break
 		}
@@ -171,7 +212,11 @@ func (s *S3) DeleteObjects(
 			// Error example:
 			// 	 Code: InternalError
 			// 	 Message: We encountered an internal error. Please try again.
-			if strings.Contains(*err.Message, "Please try again") {
+			// NOTE: The errors of the objects are retried by the code in the same way as the errors of the requests.
+			if err.Code != nil && s.classifier.IsRetryable(&smithy.GenericAPIError{
+				Code:    aws.ToString(err.Code),
+				Message: aws.ToString(err.Message),
+			}) {
 				objects = append(objects, types.ObjectIdentifier{
 					Key:       err.Key,
 					VersionId: err.VersionId,
@@ -182,8 +227,13 @@ func (s *S3) DeleteObjects(
 		}
 		// random sleep
 		if len(objects) > 0 {
-			sleepTime, _ := s.retryer.RetryDelay(0, nil)
-			time.Sleep(sleepTime)
+			sleepTime, _ := s.retryer.RetryDelay(retryCounts, nil)
+			if err := sleepWithContext(ctx, sleepTime); err != nil {
+				return errors, &ClientError{
+					ResourceName: bucketName,
+					Err:          err,
+				}
+			}
 		}
 	}
 
@@ -198,34 +248,36 @@ func (s *S3) ListObjectsOrVersionsByPage(
 	keyMarker *string,
 	versionIdMarker *string,
 	keyPrefix *string,
+	filter *ObjectFilter,
+	versionsCursor *VersionsCursor,
+	keyRange *KeyRange,
 ) (*ListObjectsOrVersionsByPageOutput, error) {
-	var objectIdentifiers []types.ObjectIdentifier
-	var nextKeyMarker *string
-	var nextVersionIdMarker *string
-
 	if !s.supportsVersions() {
-		output, err := s.listObjectsByPage(ctx, bucketName, region, keyMarker, keyPrefix)
+		output, err := s.listObjectsByPage(ctx, bucketName, region, keyMarker, keyPrefix, filter, keyRange)
 		if err != nil {
 			return nil, err
 		}
 
-		objectIdentifiers = output.ObjectIdentifiers
-		nextKeyMarker = output.NextToken
-	} else {
-		output, err := s.listObjectVersionsByPage(ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix)
-		if err != nil {
-			return nil, err
-		}
+		return &ListObjectsOrVersionsByPageOutput{
+			ObjectIdentifiers: output.ObjectIdentifiers,
+			NextKeyMarker:     output.NextToken,
+			ObjectsCount:      len(output.ObjectIdentifiers),
+		}, nil
+	}
 
-		objectIdentifiers = output.ObjectIdentifiers
-		nextKeyMarker = output.NextKeyMarker
-		nextVersionIdMarker = output.NextVersionIdMarker
+	output, err := s.listObjectVersionsByPage(ctx, bucketName, region, oldVersionsOnly, keyMarker, versionIdMarker, keyPrefix, filter, versionsCursor, keyRange)
+	if err != nil {
+		return nil, err
 	}
 
 	return &ListObjectsOrVersionsByPageOutput{
-		ObjectIdentifiers:   objectIdentifiers,
-		NextKeyMarker:       nextKeyMarker,
-		NextVersionIdMarker: nextVersionIdMarker,
+		ObjectIdentifiers:   output.ObjectIdentifiers,
+		NextKeyMarker:       output.NextKeyMarker,
+		NextVersionIdMarker: output.NextVersionIdMarker,
+		ObjectsCount:        output.ObjectsCount,
+		VersionsCount:       output.VersionsCount,
+		DeleteMarkersCount:  output.DeleteMarkersCount,
+		VersionsCursor:      output.VersionsCursor,
 	}, nil
 }
 
@@ -237,13 +289,22 @@ func (s *S3) listObjectVersionsByPage(
 	keyMarker *string,
 	versionIdMarker *string,
 	keyPrefix *string,
+	filter *ObjectFilter,
+	versionsCursor *VersionsCursor,
+	keyRange *KeyRange,
 ) (*listObjectVersionsByPageOutput, error) {
 	objectIdentifiers := []types.ObjectIdentifier{}
+	var objectsCount, versionsCount, deleteMarkersCount int
 	input := &s3.ListObjectVersionsInput{
-		Bucket:          bucketName,
-		KeyMarker:       keyMarker,
-		VersionIdMarker: versionIdMarker,
-		Prefix:          keyPrefix,
+		Bucket:              bucketName,
+		KeyMarker:           keyMarker,
+		VersionIdMarker:     versionIdMarker,
+		Prefix:              keyPrefix,
+		ExpectedBucketOwner: s.expectedBucketOwner,
+	}
+	// NOTE: The key marker without the version id marker lists the versions of the keys after it.
+	if keyMarker == nil && keyRange != nil {
+		input.KeyMarker = keyRange.StartAfter
 	}
 
 	optFn := func(o *s3.Options) {
@@ -261,10 +322,25 @@ func (s *S3) listObjectVersionsByPage(
 		}
 	}
 
-	for _, version := range output.Versions {
-		if oldVersionsOnly && (version.IsLatest == nil || *version.IsLatest) {
+	keptVersions, keptDeleteMarkers, nextVersionsCursor := filter.retainVersions(output.Versions, output.DeleteMarkers, versionsCursor)
+
+	for i, version := range output.Versions {
+		isLatest := version.IsLatest == nil || *version.IsLatest
+		if oldVersionsOnly && isLatest {
 			continue
 		}
+		if !keyRange.includes(version.Key) {
+			continue
+		}
+		if keptVersions[i] || !filter.matchKey(version.Key) || !filter.matchLastModified(version.LastModified) ||
+			!filter.matchAttributes(string(version.StorageClass), version.Size) {
+			continue
+		}
+		if isLatest {
+			objectsCount++
+		} else {
+			versionsCount++
+		}
 		objectIdentifier := types.ObjectIdentifier{
 			Key:       version.Key,
 			VersionId: version.VersionId,
@@ -272,7 +348,14 @@ func (s *S3) listObjectVersionsByPage(
 		objectIdentifiers = append(objectIdentifiers, objectIdentifier)
 	}
 
-	for _, deleteMarker := range output.DeleteMarkers {
+	for i, deleteMarker := range output.DeleteMarkers {
+		// NOTE: Delete markers have neither the storage class nor the size, so they are kept
+		// if the filter has them.
+		if keptDeleteMarkers[i] || !keyRange.includes(deleteMarker.Key) || !filter.matchKey(deleteMarker.Key) ||
+			!filter.matchLastModified(deleteMarker.LastModified) || !filter.matchAttributes("", nil) {
+			continue
+		}
+		deleteMarkersCount++
 		objectIdentifier := types.ObjectIdentifier{
 			Key:       deleteMarker.Key,
 			VersionId: deleteMarker.VersionId,
@@ -280,10 +363,20 @@ func (s *S3) listObjectVersionsByPage(
 		objectIdentifiers = append(objectIdentifiers, objectIdentifier)
 	}
 
+	// NOTE: The listing is in the order of the keys, so it ends when the keys pass the range.
+	nextKeyMarker, nextVersionIdMarker := output.NextKeyMarker, output.NextVersionIdMarker
+	if !keyRange.includes(nextKeyMarker) {
+		nextKeyMarker, nextVersionIdMarker = nil, nil
+	}
+
 	return &listObjectVersionsByPageOutput{
 		ObjectIdentifiers:   objectIdentifiers,
-		NextKeyMarker:       output.NextKeyMarker,
-		NextVersionIdMarker: output.NextVersionIdMarker,
+		NextKeyMarker:       nextKeyMarker,
+		NextVersionIdMarker: nextVersionIdMarker,
+		ObjectsCount:        objectsCount,
+		VersionsCount:       versionsCount,
+		DeleteMarkersCount:  deleteMarkersCount,
+		VersionsCursor:      nextVersionsCursor,
 	}, nil
 }
 
@@ -293,12 +386,19 @@ func (s *S3) listObjectsByPage(
 	region string,
 	token *string,
 	keyPrefix *string,
+	filter *ObjectFilter,
+	keyRange *KeyRange,
 ) (*listObjectsByPageOutput, error) {
 	objectIdentifiers := []types.ObjectIdentifier{}
 	input := &s3.ListObjectsV2Input{
-		Bucket:            bucketName,
-		ContinuationToken: token,
-		Prefix:            keyPrefix,
+		Bucket:              bucketName,
+		ContinuationToken:   token,
+		Prefix:              keyPrefix,
+		ExpectedBucketOwner: s.expectedBucketOwner,
+	}
+	// NOTE: StartAfter is ignored with the continuation token, so it is only for the first page.
+	if token == nil && keyRange != nil {
+		input.StartAfter = keyRange.StartAfter
 	}
 
 	optFn := func(o *s3.Options) {
@@ -316,7 +416,17 @@ func (s *S3) listObjectsByPage(
 		}
 	}
 
+	nextToken := output.NextContinuationToken
 	for _, object := range output.Contents {
+		if !keyRange.includes(object.Key) {
+			// NOTE: The listing is in the order of the keys, so it ends when the keys pass the range.
+			nextToken = nil
+			break
+		}
+		if !filter.matchKey(object.Key) || !filter.matchLastModified(object.LastModified) ||
+			!filter.matchAttributes(string(object.StorageClass), object.Size) {
+			continue
+		}
 		objectIdentifier := types.ObjectIdentifier{
 			Key: object.Key,
 		}
@@ -325,10 +435,74 @@ func (s *S3) listObjectsByPage(
 
 	return &listObjectsByPageOutput{
 		ObjectIdentifiers: objectIdentifiers,
-		NextToken:         output.NextContinuationToken,
+		NextToken:         nextToken,
 	}, nil
 }
 
+// ListCommonPrefixes returns the prefixes of the keys under a key prefix up to the first delimiter after it
+// (e.g. the top-level "directories" with "/"). The keys of only old versions and delete markers are included.
+func (s *S3) ListCommonPrefixes(ctx context.Context, bucketName *string, region string, keyPrefix *string, delimiter string) ([]string, error) {
+	prefixes := []string{}
+
+	optFn := func(o *s3.Options) {
+		o.Retryer = s.retryer
+		if region != "" {
+			o.Region = region
+		}
+	}
+
+	if !s.supportsVersions() {
+		var token *string
+		for {
+			output, err := s.client.ListObjectsV2(ctx, &s3.ListObjectsV2Input{
+				Bucket:              bucketName,
+				ContinuationToken:   token,
+				Prefix:              keyPrefix,
+				Delimiter:           aws.String(delimiter),
+				ExpectedBucketOwner: s.expectedBucketOwner,
+			}, optFn)
+			if err != nil {
+				return nil, &ClientError{
+					ResourceName: bucketName,
+					Err:          err,
+				}
+			}
+			for _, commonPrefix := range output.CommonPrefixes {
+				prefixes = append(prefixes, aws.ToString(commonPrefix.Prefix))
+			}
+			token = output.NextContinuationToken
+			if token == nil {
+				return prefixes, nil
+			}
+		}
+	}
+
+	var keyMarker, versionIdMarker *string
+	for {
+		output, err := s.client.ListObjectVersions(ctx, &s3.ListObjectVersionsInput{
+			Bucket:              bucketName,
+			KeyMarker:           keyMarker,
+			VersionIdMarker:     versionIdMarker,
+			Prefix:              keyPrefix,
+			Delimiter:           aws.String(delimiter),
+			ExpectedBucketOwner: s.expectedBucketOwner,
+		}, optFn)
+		if err != nil {
+			return nil, &ClientError{
+				ResourceName: bucketName,
+				Err:          err,
+			}
+		}
+		for _, commonPrefix := range output.CommonPrefixes {
+			prefixes = append(prefixes, aws.ToString(commonPrefix.Prefix))
+		}
+		keyMarker, versionIdMarker = output.NextKeyMarker, output.NextVersionIdMarker
+		if keyMarker == nil {
+			return prefixes, nil
+		}
+	}
+}
+
 func (s *S3) ListBucketsOrDirectoryBuckets(ctx context.Context) ([]types.Bucket, error) {
 	var listBucketsFunc func(ctx context.Context) ([]types.Bucket, error)
 
@@ -358,8 +532,10 @@ func (s *S3) listBuckets(ctx context.Context) ([]types.Bucket, error) {
 		default:
 		}
 
+		// NOTE: The regions of the buckets are only returned when any parameter is specified.
 		input := &s3.ListBucketsInput{
 			ContinuationToken: continuationToken,
+			MaxBuckets:        aws.Int32(MaxListBucketsCount),
 		}
 
 		optFn := func(o *s3.Options) {
@@ -458,6 +634,62 @@ func (s *S3) GetBucketLocation(ctx context.Context, bucketName *string) (string,
 	return string(output.LocationConstraint), nil
 }
 
+// GetBucketTagging returns the tags of a bucket, or an empty map if the bucket has no tags
+func (s *S3) GetBucketTagging(ctx context.Context, bucketName *string, region string) (map[string]string, error) {
+	input := &s3.GetBucketTaggingInput{
+		Bucket: bucketName,
+	}
+
+	optFn := func(o *s3.Options) {
+		o.Retryer = s.retryer
+		if region != "" {
+			o.Region = region
+		}
+	}
+
+	tags := map[string]string{}
+	output, err := s.client.GetBucketTagging(ctx, input, optFn)
+	if err != nil {
+		// NOTE: S3 returns NoSuchTagSet instead of an empty tag set for a bucket without tags.
+		var apiErr smithy.APIError
+		if errors.As(err, &apiErr) && apiErr.ErrorCode() == "NoSuchTagSet" {
+			return tags, nil
+		}
+		return nil, &ClientError{
+			ResourceName: bucketName,
+			Err:          err,
+		}
+	}
+	for _, tag := range output.TagSet {
+		tags[aws.ToString(tag.Key)] = aws.ToString(tag.Value)
+	}
+	return tags, nil
+}
+
+// GetObject returns the body of an object, which must be closed by the caller
+func (s *S3) GetObject(ctx context.Context, bucketName *string, key *string, region string) (io.ReadCloser, error) {
+	input := &s3.GetObjectInput{
+		Bucket: bucketName,
+		Key:    key,
+	}
+
+	optFn := func(o *s3.Options) {
+		o.Retryer = s.retryer
+		if region != "" {
+			o.Region = region
+		}
+	}
+
+	output, err := s.client.GetObject(ctx, input, optFn)
+	if err != nil {
+		return nil, &ClientError{
+			ResourceName: bucketName,
+			Err:          err,
+		}
+	}
+	return output.Body, nil
+}
+
 func (s *S3) supportsVersions() bool {
 	if s.directoryBucketsMode {
 		return false
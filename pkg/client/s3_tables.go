@@ -3,14 +3,16 @@ package client
 
 import (
 	"context"
+	"net/http"
 	"sort"
-	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3tables"
 	"github.com/aws/aws-sdk-go-v2/service/s3tables/types"
 )
 
+// SleepTimeSecForS3Tables is the default cap of the delay of the retries for S3 Tables in seconds.
 var SleepTimeSecForS3Tables = 3 // NOTE: Because S3Tables is a serial operation, a low value is OK.
 
 type ListNamespacesByPageOutput struct {
@@ -39,18 +41,20 @@ type S3Tables struct {
 	retryer *Retryer
 }
 
-func NewS3Tables(client *s3tables.Client) *S3Tables {
-	retryable := func(err error) bool {
-		isRetryable :=
-			strings.Contains(err.Error(), "api error SlowDown") ||
-				strings.Contains(err.Error(), "An internal error occurred. Try again.") ||
-				strings.Contains(err.Error(), "StatusCode: 429") ||
-				// I haven't encountered this error yet, but I got this error on S3, so I'll add it here too, just in case.
-				strings.Contains(err.Error(), "Please try again")
-
-		return isRetryable
+// NewS3Tables creates an S3 Tables client. The retries of its requests can be configured with optFns.
+func NewS3Tables(client *s3tables.Client, optFns ...func(*RetryOptions)) *S3Tables {
+	options := RetryOptions{
+		MaxAttempts: MaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    time.Duration(SleepTimeSecForS3Tables) * time.Second,
+	}
+	for _, optFn := range optFns {
+		optFn(&options)
 	}
-	retryer := NewRetryer(retryable, SleepTimeSecForS3Tables)
+
+	classifier := NewRetryableErrorClassifier(DefaultS3TablesRetryableErrorCodes, []int{http.StatusTooManyRequests}).
+		WithCodes(options.RetryableErrorCodes...)
+	retryer := NewRetryer(classifier.IsRetryable, options)
 
 	return &S3Tables{
 		client,
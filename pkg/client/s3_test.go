@@ -3,14 +3,18 @@ package client
 import (
 	"context"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/aws/retry"
 	"github.com/aws/aws-sdk-go-v2/config"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
+	"github.com/aws/smithy-go"
 	"github.com/aws/smithy-go/middleware"
 )
 
@@ -29,6 +33,21 @@ func getTokenForListBucketsInitialize(
 	return next.HandleInitialize(ctx, in)
 }
 
+type maxBucketsForListBuckets struct{}
+
+func getMaxBucketsForListBucketsInitialize(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (
+	out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+) {
+	//nolint:gocritic
+	switch v := in.Parameters.(type) {
+	case *s3.ListBucketsInput:
+		ctx = middleware.WithStackValue(ctx, maxBucketsForListBuckets{}, v.MaxBuckets)
+	}
+	return next.HandleInitialize(ctx, in)
+}
+
 type tokenForListDirectoryBuckets struct{}
 
 func getTokenForListDirectoryBucketsInitialize(
@@ -44,6 +63,22 @@ func getTokenForListDirectoryBucketsInitialize(
 	return next.HandleInitialize(ctx, in)
 }
 
+type markerForListCommonPrefixes struct{}
+
+func getMarkerForListCommonPrefixesInitialize(
+	ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler,
+) (
+	out middleware.InitializeOutput, metadata middleware.Metadata, err error,
+) {
+	switch v := in.Parameters.(type) {
+	case *s3.ListObjectVersionsInput:
+		ctx = middleware.WithStackValue(ctx, markerForListCommonPrefixes{}, v.KeyMarker)
+	case *s3.ListObjectsV2Input:
+		ctx = middleware.WithStackValue(ctx, markerForListCommonPrefixes{}, v.ContinuationToken)
+	}
+	return next.HandleInitialize(ctx, in)
+}
+
 type targetObjectsForDeleteObjects struct{}
 
 func setTargetObjectsForDeleteObjectsInitialize(
@@ -569,8 +604,8 @@ func TestS3_DeleteObjects(t *testing.T) {
 										},
 										{
 											Key:       aws.String("Key2"),
-											Code:      aws.String("InternalError"),
-											Message:   aws.String("Other Error"),
+											Code:      aws.String("AccessDenied"),
+											Message:   aws.String("Access Denied"),
 											VersionId: aws.String("VersionId2"),
 										},
 										// 3rd object is not an error
@@ -593,8 +628,8 @@ func TestS3_DeleteObjects(t *testing.T) {
 				output: []types.Error{
 					{
 						Key:       aws.String("Key2"),
-						Code:      aws.String("InternalError"),
-						Message:   aws.String("Other Error"),
+						Code:      aws.String("AccessDenied"),
+						Message:   aws.String("Access Denied"),
 						VersionId: aws.String("VersionId2"),
 					},
 				},
@@ -654,8 +689,8 @@ func TestS3_DeleteObjects(t *testing.T) {
 									errors = []types.Error{
 										{
 											Key:       aws.String("Key1"),
-											Code:      aws.String("InternalError"),
-											Message:   aws.String("Other Error"),
+											Code:      aws.String("AccessDenied"),
+											Message:   aws.String("Access Denied"),
 											VersionId: aws.String("VersionId1"),
 										},
 									}
@@ -675,8 +710,8 @@ func TestS3_DeleteObjects(t *testing.T) {
 				output: []types.Error{
 					{
 						Key:       aws.String("Key1"),
-						Code:      aws.String("InternalError"),
-						Message:   aws.String("Other Error"),
+						Code:      aws.String("AccessDenied"),
+						Message:   aws.String("Access Denied"),
 						VersionId: aws.String("VersionId1"),
 					},
 				},
@@ -684,7 +719,127 @@ func TestS3_DeleteObjects(t *testing.T) {
 			},
 			wantErr: false,
 		},
-	}
+
+		{
+			name: "retry output errors by the code whatever the message is",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				objects: []types.ObjectIdentifier{
+					{
+						Key:       aws.String("Key1"),
+						VersionId: aws.String("VersionId1"),
+					},
+					{
+						Key:       aws.String("Key2"),
+						VersionId: aws.String("VersionId2"),
+					},
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"SetTargetObjects",
+							setTargetObjectsForDeleteObjectsInitialize,
+						), middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"DeleteObjectsWithRetryableErrorsMock",
+							func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								objects := middleware.GetStackValue(ctx, targetObjectsForDeleteObjects{}).([]types.ObjectIdentifier)
+								var errors []types.Error
+								// first loop
+								if len(objects) == 2 {
+									errors = []types.Error{
+										{
+											Key:       aws.String("Key1"),
+											Code:      aws.String("SlowDown"),
+											Message:   aws.String("Please reduce your request rate."),
+											VersionId: aws.String("VersionId1"),
+										},
+										{
+											Key:       aws.String("Key2"),
+											Code:      aws.String("AccessDenied"),
+											Message:   aws.String("Please try again."),
+											VersionId: aws.String("VersionId2"),
+										},
+									}
+								} else {
+									errors = []types.Error{}
+								}
+								return middleware.FinalizeOutput{
+									Result: &s3.DeleteObjectsOutput{
+										Errors: errors,
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: []types.Error{
+					{
+						Key:       aws.String("Key2"),
+						Code:      aws.String("AccessDenied"),
+						Message:   aws.String("Please try again."),
+						VersionId: aws.String("VersionId2"),
+					},
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "return output errors without the code and the message without retrying",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				objects: []types.ObjectIdentifier{
+					{
+						Key:       aws.String("Key1"),
+						VersionId: aws.String("VersionId1"),
+					},
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"DeleteObjectsWithErrorsMock",
+							func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.DeleteObjectsOutput{
+										Errors: []types.Error{
+											{
+												Key:       aws.String("Key1"),
+												VersionId: aws.String("VersionId1"),
+											},
+										},
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: []types.Error{
+					{
+						Key:       aws.String("Key1"),
+						VersionId: aws.String("VersionId1"),
+					},
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},	}
 
 	for _, tt := range cases {
 		t.Run(tt.name, func(t *testing.T) {
@@ -717,6 +872,85 @@ func TestS3_DeleteObjects(t *testing.T) {
 	}
 }
 
+func TestS3_DeleteObjects_MaxAttempts(t *testing.T) {
+	cases := []struct {
+		name        string
+		maxAttempts int
+		wantCalls   int
+	}{
+		{
+			name:        "send the objects only once without retries",
+			maxAttempts: 1,
+			wantCalls:   1,
+		},
+		{
+			name:        "send the objects up to the max attempts including the first one",
+			maxAttempts: 3,
+			wantCalls:   3,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			cfg, err := config.LoadDefaultConfig(
+				context.Background(),
+				config.WithRegion("us-east-1"),
+				config.WithAPIOptions([]func(*middleware.Stack) error{
+					func(stack *middleware.Stack) error {
+						return stack.Finalize.Add(
+							middleware.FinalizeMiddlewareFunc(
+								"DeleteObjectsWithRetryableErrorsMock",
+								func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+									calls++
+									return middleware.FinalizeOutput{
+										Result: &s3.DeleteObjectsOutput{
+											Errors: []types.Error{
+												{
+													Key:       aws.String("Key1"),
+													Code:      aws.String("InternalError"),
+													Message:   aws.String("We encountered an internal error. Please try again."),
+													VersionId: aws.String("VersionId1"),
+												},
+											},
+										},
+									}, middleware.Metadata{}, nil
+								},
+							),
+							middleware.Before,
+						)
+					},
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s3Client := NewS3(s3.NewFromConfig(cfg), false, func(o *RetryOptions) {
+				o.MaxAttempts = tt.maxAttempts
+				o.BaseDelay = time.Millisecond
+				o.MaxDelay = time.Millisecond
+			})
+
+			output, err := s3Client.DeleteObjects(context.Background(), aws.String("test"), []types.ObjectIdentifier{
+				{
+					Key:       aws.String("Key1"),
+					VersionId: aws.String("VersionId1"),
+				},
+			}, "us-east-1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(output) != 1 {
+				t.Errorf("output = %#v, want the error of the object", output)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("calls = %v, want %v", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
 func TestS3_ListObjectsOrVersionsByPage(t *testing.T) {
 	type args struct {
 		ctx                  context.Context
@@ -727,6 +961,8 @@ func TestS3_ListObjectsOrVersionsByPage(t *testing.T) {
 		versionIdMarker      *string
 		directoryBucketsMode bool
 		keyPrefix            *string
+		filter               *ObjectFilter
+		versionsCursor       *VersionsCursor
 		withAPIOptionsFunc   func(*middleware.Stack) error
 	}
 
@@ -787,6 +1023,7 @@ func TestS3_ListObjectsOrVersionsByPage(t *testing.T) {
 						},
 					},
 					NextKeyMarker: aws.String("NextContinuationToken"),
+					ObjectsCount: 2,
 				},
 				err: nil,
 			},
@@ -846,6 +1083,8 @@ func TestS3_ListObjectsOrVersionsByPage(t *testing.T) {
 					},
 					NextKeyMarker:       aws.String("NextKeyMarker"),
 					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+					ObjectsCount:       1,
+					DeleteMarkersCount: 1,
 				},
 				err: nil,
 			},
@@ -929,7 +1168,7 @@ func TestS3_ListObjectsOrVersionsByPage(t *testing.T) {
 			client := s3.NewFromConfig(cfg)
 			s3Client := NewS3(client, tt.args.directoryBucketsMode)
 
-			output, err := s3Client.ListObjectsOrVersionsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.oldVersionsOnly, tt.args.keyMarker, tt.args.versionIdMarker, tt.args.keyPrefix)
+			output, err := s3Client.ListObjectsOrVersionsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.oldVersionsOnly, tt.args.keyMarker, tt.args.versionIdMarker, tt.args.keyPrefix, tt.args.filter, tt.args.versionsCursor, nil)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
 				return
@@ -960,6 +1199,9 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 		keyMarker          *string
 		versionIdMarker    *string
 		keyPrefix          *string
+		filter             *ObjectFilter
+		versionsCursor     *VersionsCursor
+		keyRange           *KeyRange
 		withAPIOptionsFunc func(*middleware.Stack) error
 	}
 
@@ -975,7 +1217,7 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "list objects versions successfully",
+			name: "list only objects versions in the time window of the filter",
 			args: args{
 				ctx:             context.Background(),
 				bucketName:      aws.String("test"),
@@ -984,6 +1226,10 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 				keyMarker:       nil,
 				versionIdMarker: nil,
 				keyPrefix:       nil,
+				filter: &ObjectFilter{
+					OlderThan: aws.Time(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)),
+					NewerThan: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+				},
 				withAPIOptionsFunc: func(stack *middleware.Stack) error {
 					return stack.Finalize.Add(
 						middleware.FinalizeMiddlewareFunc(
@@ -993,14 +1239,34 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 									Result: &s3.ListObjectVersionsOutput{
 										Versions: []types.ObjectVersion{
 											{
-												Key:       aws.String("KeyForVersions"),
-												VersionId: aws.String("VersionIdForVersions"),
+												Key:          aws.String("KeyNew"),
+												VersionId:    aws.String("VersionIdNew"),
+												IsLatest:     aws.Bool(true),
+												LastModified: aws.Time(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyInWindow"),
+												VersionId:    aws.String("VersionIdInWindow"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 5, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyOld"),
+												VersionId:    aws.String("VersionIdOld"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC)),
 											},
 										},
 										DeleteMarkers: []types.DeleteMarkerEntry{
 											{
-												Key:       aws.String("KeyForDeleteMarkers"),
-												VersionId: aws.String("VersionIdForDeleteMarkers"),
+												Key:          aws.String("KeyForDeleteMarkersInWindow"),
+												VersionId:    aws.String("VersionIdForDeleteMarkersInWindow"),
+												LastModified: aws.Time(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyForDeleteMarkersNew"),
+												VersionId:    aws.String("VersionIdForDeleteMarkersNew"),
+												LastModified: aws.Time(time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)),
 											},
 										},
 										NextKeyMarker:       aws.String("NextKeyMarker"),
@@ -1017,23 +1283,25 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 				output: &listObjectVersionsByPageOutput{
 					ObjectIdentifiers: []types.ObjectIdentifier{
 						{
-							Key:       aws.String("KeyForVersions"),
-							VersionId: aws.String("VersionIdForVersions"),
+							Key:       aws.String("KeyInWindow"),
+							VersionId: aws.String("VersionIdInWindow"),
 						},
 						{
-							Key:       aws.String("KeyForDeleteMarkers"),
-							VersionId: aws.String("VersionIdForDeleteMarkers"),
+							Key:       aws.String("KeyForDeleteMarkersInWindow"),
+							VersionId: aws.String("VersionIdForDeleteMarkersInWindow"),
 						},
 					},
 					NextKeyMarker:       aws.String("NextKeyMarker"),
 					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+					VersionsCount:       1,
+					DeleteMarkersCount:  1,
 				},
 				err: nil,
 			},
 			wantErr: false,
 		},
 		{
-			name: "list objects versions failure",
+			name: "list only objects versions in the storage classes of the filter without delete markers",
 			args: args{
 				ctx:             context.Background(),
 				bucketName:      aws.String("test"),
@@ -1042,14 +1310,38 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 				keyMarker:       nil,
 				versionIdMarker: nil,
 				keyPrefix:       nil,
+				filter: &ObjectFilter{
+					StorageClasses: []string{"STANDARD"},
+				},
 				withAPIOptionsFunc: func(stack *middleware.Stack) error {
 					return stack.Finalize.Add(
 						middleware.FinalizeMiddlewareFunc(
-							"ListObjectVersionsErrorMock",
+							"ListObjectVersionsMock",
 							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
 								return middleware.FinalizeOutput{
-									Result: &s3.ListObjectVersionsOutput{},
-								}, middleware.Metadata{}, fmt.Errorf("ListObjectVersionsError")
+									Result: &s3.ListObjectVersionsOutput{
+										Versions: []types.ObjectVersion{
+											{
+												Key:          aws.String("KeyStandard"),
+												VersionId:    aws.String("VersionIdStandard"),
+												IsLatest:     aws.Bool(true),
+												StorageClass: types.ObjectVersionStorageClassStandard,
+											},
+											{
+												Key:          aws.String("KeyDeepArchive"),
+												VersionId:    aws.String("VersionIdDeepArchive"),
+												IsLatest:     aws.Bool(false),
+												StorageClass: types.ObjectVersionStorageClass("DEEP_ARCHIVE"),
+											},
+										},
+										DeleteMarkers: []types.DeleteMarkerEntry{
+											{
+												Key:       aws.String("KeyForDeleteMarkers"),
+												VersionId: aws.String("VersionIdForDeleteMarkers"),
+											},
+										},
+									},
+								}, middleware.Metadata{}, nil
 							},
 						),
 						middleware.Before,
@@ -1057,35 +1349,90 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 				},
 			},
 			want: want{
-				output: nil,
-				err: &ClientError{
-					ResourceName: aws.String("test"),
-					Err:          fmt.Errorf("operation error S3: ListObjectVersions, ListObjectVersionsError"),
+				output: &listObjectVersionsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key:       aws.String("KeyStandard"),
+							VersionId: aws.String("VersionIdStandard"),
+						},
+					},
+					NextKeyMarker:       nil,
+					NextVersionIdMarker: nil,
+					ObjectsCount:        1,
 				},
+				err: nil,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 		{
-			name: "list objects versions failure for api error SlowDown",
+			name: "keep the newest noncurrent versions of each key continued from the previous page",
 			args: args{
 				ctx:             context.Background(),
 				bucketName:      aws.String("test"),
 				region:          "us-east-1",
-				oldVersionsOnly: false,
-				keyMarker:       nil,
-				versionIdMarker: nil,
+				oldVersionsOnly: true,
+				keyMarker:       aws.String("KeyA"),
+				versionIdMarker: aws.String("VersionIdA4"),
 				keyPrefix:       nil,
+				filter: &ObjectFilter{
+					KeepVersions: 2,
+				},
+				versionsCursor: &VersionsCursor{
+					Key:             "KeyA",
+					NoncurrentCount: 1,
+					LastModified:    aws.Time(time.Date(2025, 1, 4, 0, 0, 0, 0, time.UTC)),
+				},
 				withAPIOptionsFunc: func(stack *middleware.Stack) error {
 					return stack.Finalize.Add(
 						middleware.FinalizeMiddlewareFunc(
-							"ListObjectVersionsApiErrorMock",
+							"ListObjectVersionsMock",
 							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
 								return middleware.FinalizeOutput{
-										Result: &s3.ListObjectVersionsOutput{},
-									}, middleware.Metadata{}, &retry.MaxAttemptsError{
-										Attempt: MaxAttempts,
-										Err:     fmt.Errorf("api error SlowDown"),
-									}
+									Result: &s3.ListObjectVersionsOutput{
+										Versions: []types.ObjectVersion{
+											{
+												Key:          aws.String("KeyA"),
+												VersionId:    aws.String("VersionIdA3"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 3, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyA"),
+												VersionId:    aws.String("VersionIdA2"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyB"),
+												VersionId:    aws.String("VersionIdB3"),
+												IsLatest:     aws.Bool(true),
+												LastModified: aws.Time(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyB"),
+												VersionId:    aws.String("VersionIdB2"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 8, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("KeyB"),
+												VersionId:    aws.String("VersionIdB1"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)),
+											},
+										},
+										DeleteMarkers: []types.DeleteMarkerEntry{
+											{
+												Key:          aws.String("KeyB"),
+												VersionId:    aws.String("VersionIdForDeleteMarkersB"),
+												IsLatest:     aws.Bool(false),
+												LastModified: aws.Time(time.Date(2025, 1, 9, 0, 0, 0, 0, time.UTC)),
+											},
+										},
+										NextKeyMarker:       aws.String("KeyB"),
+										NextVersionIdMarker: aws.String("VersionIdB1"),
+									},
+								}, middleware.Metadata{}, nil
 							},
 						),
 						middleware.Before,
@@ -1093,7 +1440,153 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 				},
 			},
 			want: want{
-				output: nil,
+				output: &listObjectVersionsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key:       aws.String("KeyA"),
+							VersionId: aws.String("VersionIdA2"),
+						},
+						{
+							Key:       aws.String("KeyB"),
+							VersionId: aws.String("VersionIdB1"),
+						},
+					},
+					NextKeyMarker:       aws.String("KeyB"),
+					NextVersionIdMarker: aws.String("VersionIdB1"),
+					VersionsCount:       2,
+					DeleteMarkersCount:  0,
+					VersionsCursor: &VersionsCursor{
+						Key:             "KeyB",
+						NoncurrentCount: 3,
+						LastModified:    aws.Time(time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC)),
+					},
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list objects versions successfully",
+			args: args{
+				ctx:             context.Background(),
+				bucketName:      aws.String("test"),
+				region:          "us-east-1",
+				oldVersionsOnly: false,
+				keyMarker:       nil,
+				versionIdMarker: nil,
+				keyPrefix:       nil,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectVersionsOutput{
+										Versions: []types.ObjectVersion{
+											{
+												Key:       aws.String("KeyForVersions"),
+												VersionId: aws.String("VersionIdForVersions"),
+											},
+										},
+										DeleteMarkers: []types.DeleteMarkerEntry{
+											{
+												Key:       aws.String("KeyForDeleteMarkers"),
+												VersionId: aws.String("VersionIdForDeleteMarkers"),
+											},
+										},
+										NextKeyMarker:       aws.String("NextKeyMarker"),
+										NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectVersionsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key:       aws.String("KeyForVersions"),
+							VersionId: aws.String("VersionIdForVersions"),
+						},
+						{
+							Key:       aws.String("KeyForDeleteMarkers"),
+							VersionId: aws.String("VersionIdForDeleteMarkers"),
+						},
+					},
+					NextKeyMarker:       aws.String("NextKeyMarker"),
+					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+					ObjectsCount:       1,
+					DeleteMarkersCount: 1,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list objects versions failure",
+			args: args{
+				ctx:             context.Background(),
+				bucketName:      aws.String("test"),
+				region:          "us-east-1",
+				oldVersionsOnly: false,
+				keyMarker:       nil,
+				versionIdMarker: nil,
+				keyPrefix:       nil,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectVersionsOutput{},
+								}, middleware.Metadata{}, fmt.Errorf("ListObjectVersionsError")
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: nil,
+				err: &ClientError{
+					ResourceName: aws.String("test"),
+					Err:          fmt.Errorf("operation error S3: ListObjectVersions, ListObjectVersionsError"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "list objects versions failure for api error SlowDown",
+			args: args{
+				ctx:             context.Background(),
+				bucketName:      aws.String("test"),
+				region:          "us-east-1",
+				oldVersionsOnly: false,
+				keyMarker:       nil,
+				versionIdMarker: nil,
+				keyPrefix:       nil,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsApiErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+										Result: &s3.ListObjectVersionsOutput{},
+									}, middleware.Metadata{}, &retry.MaxAttemptsError{
+										Attempt: MaxAttempts,
+										Err:     fmt.Errorf("api error SlowDown"),
+									}
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: nil,
 				err: &ClientError{
 					ResourceName: aws.String("test"),
 					Err:          fmt.Errorf("operation error S3: ListObjectVersions, exceeded maximum number of attempts, 20, api error SlowDown"),
@@ -1182,6 +1675,7 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 					},
 					NextKeyMarker:       aws.String("NextKeyMarker"),
 					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+					ObjectsCount: 1,
 				},
 				err: nil,
 			},
@@ -1231,6 +1725,7 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 					},
 					NextKeyMarker:       aws.String("NextKeyMarker"),
 					NextVersionIdMarker: aws.String("NextVersionIdMarker"),
+					DeleteMarkersCount: 1,
 				},
 				err: nil,
 			},
@@ -1289,6 +1784,8 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 					},
 					NextKeyMarker:       nil,
 					NextVersionIdMarker: nil,
+					ObjectsCount:       1,
+					DeleteMarkersCount: 1,
 				},
 				err: nil,
 			},
@@ -1406,6 +1903,163 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 					},
 					NextKeyMarker:       nil,
 					NextVersionIdMarker: nil,
+					VersionsCount:      1,
+					DeleteMarkersCount: 3,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list only objects versions in the key range from the start after key",
+			args: args{
+				ctx:             context.Background(),
+				bucketName:      aws.String("test"),
+				region:          "us-east-1",
+				oldVersionsOnly: false,
+				keyMarker:       nil,
+				versionIdMarker: nil,
+				keyPrefix:       nil,
+				keyRange: &KeyRange{
+					StartAfter: aws.String("KeyA"),
+					LastKey:    aws.String("KeyB"),
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"CheckKeyMarker",
+							func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+								if keyMarker := in.Parameters.(*s3.ListObjectVersionsInput).KeyMarker; aws.ToString(keyMarker) != "KeyA" {
+									return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("KeyMarkerError: %v", aws.ToString(keyMarker))
+								}
+								return next.HandleInitialize(ctx, in)
+							},
+						),
+						middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectVersionsOutput{
+										Versions: []types.ObjectVersion{
+											{
+												Key:       aws.String("KeyB"),
+												VersionId: aws.String("VersionIdB"),
+											},
+											{
+												Key:       aws.String("KeyC"),
+												VersionId: aws.String("VersionIdC"),
+											},
+										},
+										DeleteMarkers: []types.DeleteMarkerEntry{
+											{
+												Key:       aws.String("KeyB"),
+												VersionId: aws.String("VersionIdForDeleteMarkersB"),
+											},
+											{
+												Key:       aws.String("KeyC"),
+												VersionId: aws.String("VersionIdForDeleteMarkersC"),
+											},
+										},
+										NextKeyMarker:       aws.String("KeyC"),
+										NextVersionIdMarker: aws.String("VersionIdForDeleteMarkersC"),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectVersionsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key:       aws.String("KeyB"),
+							VersionId: aws.String("VersionIdB"),
+						},
+						{
+							Key:       aws.String("KeyB"),
+							VersionId: aws.String("VersionIdForDeleteMarkersB"),
+						},
+					},
+					NextKeyMarker:       nil,
+					NextVersionIdMarker: nil,
+					ObjectsCount:        1,
+					DeleteMarkersCount:  1,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list objects versions from the key marker instead of the start after key of the key range",
+			args: args{
+				ctx:             context.Background(),
+				bucketName:      aws.String("test"),
+				region:          "us-east-1",
+				oldVersionsOnly: false,
+				keyMarker:       aws.String("KeyMarker"),
+				versionIdMarker: aws.String("VersionIdMarker"),
+				keyPrefix:       nil,
+				keyRange: &KeyRange{
+					StartAfter: aws.String("KeyA"),
+					LastKey:    aws.String("KeyZ"),
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"CheckKeyMarker",
+							func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+								if keyMarker := in.Parameters.(*s3.ListObjectVersionsInput).KeyMarker; aws.ToString(keyMarker) != "KeyMarker" {
+									return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("KeyMarkerError: %v", aws.ToString(keyMarker))
+								}
+								return next.HandleInitialize(ctx, in)
+							},
+						),
+						middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectVersionsOutput{
+										Versions: []types.ObjectVersion{
+											{
+												Key:       aws.String("KeyM"),
+												VersionId: aws.String("VersionIdM"),
+											},
+										},
+										NextKeyMarker:       aws.String("KeyM"),
+										NextVersionIdMarker: aws.String("VersionIdM"),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectVersionsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key:       aws.String("KeyM"),
+							VersionId: aws.String("VersionIdM"),
+						},
+					},
+					NextKeyMarker:       aws.String("KeyM"),
+					NextVersionIdMarker: aws.String("VersionIdM"),
+					ObjectsCount:        1,
 				},
 				err: nil,
 			},
@@ -1427,7 +2081,7 @@ func TestS3_listObjectVersionsByPage(t *testing.T) {
 			client := s3.NewFromConfig(cfg)
 			s3Client := NewS3(client, false)
 
-			output, err := s3Client.listObjectVersionsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.oldVersionsOnly, tt.args.keyMarker, tt.args.versionIdMarker, tt.args.keyPrefix)
+			output, err := s3Client.listObjectVersionsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.oldVersionsOnly, tt.args.keyMarker, tt.args.versionIdMarker, tt.args.keyPrefix, tt.args.filter, tt.args.versionsCursor, tt.args.keyRange)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
 				return
@@ -1456,6 +2110,8 @@ func TestS3_listObjectsByPage(t *testing.T) {
 		region             string
 		token              *string
 		keyPrefix          *string
+		filter             *ObjectFilter
+		keyRange           *KeyRange
 		withAPIOptionsFunc func(*middleware.Stack) error
 	}
 
@@ -1519,28 +2175,142 @@ func TestS3_listObjectsByPage(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "list objects failure",
+			name: "list only objects in the storage classes and the size range of the filter",
 			args: args{
 				ctx:        context.Background(),
 				bucketName: aws.String("test"),
 				region:     "us-east-1",
 				token:      nil,
 				keyPrefix:  nil,
+				filter: &ObjectFilter{
+					StorageClasses: []string{"STANDARD"},
+					MinSize:        aws.Int64(1024),
+					MaxSize:        aws.Int64(4096),
+				},
 				withAPIOptionsFunc: func(stack *middleware.Stack) error {
 					return stack.Finalize.Add(
 						middleware.FinalizeMiddlewareFunc(
-							"ListObjectsV2ErrorMock",
+							"ListObjectsV2Mock",
 							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
 								return middleware.FinalizeOutput{
-									Result: &s3.ListObjectsV2Output{},
-								}, middleware.Metadata{}, fmt.Errorf("ListObjectsV2Error")
-							},
-						),
-						middleware.Before,
-					)
-				},
-			},
-			want: want{
+									Result: &s3.ListObjectsV2Output{
+										Contents: []types.Object{
+											{
+												Key:          aws.String("KeyStandard"),
+												StorageClass: types.ObjectStorageClassStandard,
+												Size:         aws.Int64(2048),
+											},
+											{
+												Key:          aws.String("KeyGlacier"),
+												StorageClass: types.ObjectStorageClassGlacier,
+												Size:         aws.Int64(2048),
+											},
+											{
+												Key:          aws.String("KeyTooSmall"),
+												StorageClass: types.ObjectStorageClassStandard,
+												Size:         aws.Int64(1023),
+											},
+											{
+												Key:          aws.String("KeyTooLarge"),
+												StorageClass: types.ObjectStorageClassStandard,
+												Size:         aws.Int64(4097),
+											},
+										},
+										NextContinuationToken: nil,
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key: aws.String("KeyStandard"),
+						},
+					},
+					NextToken: nil,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list only objects older than the filter",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				token:      nil,
+				keyPrefix:  nil,
+				filter: &ObjectFilter{
+					OlderThan: aws.Time(time.Date(2025, 1, 10, 0, 0, 0, 0, time.UTC)),
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectsV2Mock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectsV2Output{
+										Contents: []types.Object{
+											{
+												Key:          aws.String("Key1"),
+												LastModified: aws.Time(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)),
+											},
+											{
+												Key:          aws.String("Key2"),
+												LastModified: aws.Time(time.Date(2025, 1, 11, 0, 0, 0, 0, time.UTC)),
+											},
+										},
+										NextContinuationToken: aws.String("NextContinuationToken"),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key: aws.String("Key1"),
+						},
+					},
+					NextToken: aws.String("NextContinuationToken"),
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list objects failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				token:      nil,
+				keyPrefix:  nil,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectsV2ErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectsV2Output{},
+								}, middleware.Metadata{}, fmt.Errorf("ListObjectsV2Error")
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
 				output: nil,
 				err: &ClientError{
 					ResourceName: aws.String("test"),
@@ -1692,6 +2462,70 @@ func TestS3_listObjectsByPage(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "list only objects in the key range from the start after key",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				token:      nil,
+				keyPrefix:  nil,
+				keyRange: &KeyRange{
+					StartAfter: aws.String("KeyA"),
+					LastKey:    aws.String("KeyB"),
+				},
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"CheckStartAfter",
+							func(ctx context.Context, in middleware.InitializeInput, next middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+								if startAfter := in.Parameters.(*s3.ListObjectsV2Input).StartAfter; aws.ToString(startAfter) != "KeyA" {
+									return middleware.InitializeOutput{}, middleware.Metadata{}, fmt.Errorf("StartAfterError: %v", aws.ToString(startAfter))
+								}
+								return next.HandleInitialize(ctx, in)
+							},
+						),
+						middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectsV2Mock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectsV2Output{
+										Contents: []types.Object{
+											{
+												Key: aws.String("KeyB"),
+											},
+											{
+												Key: aws.String("KeyC"),
+											},
+										},
+										NextContinuationToken: aws.String("NextContinuationToken"),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: &listObjectsByPageOutput{
+					ObjectIdentifiers: []types.ObjectIdentifier{
+						{
+							Key: aws.String("KeyB"),
+						},
+					},
+					NextToken: nil,
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range cases {
@@ -1708,7 +2542,7 @@ func TestS3_listObjectsByPage(t *testing.T) {
 			client := s3.NewFromConfig(cfg)
 			s3Client := NewS3(client, true)
 
-			output, err := s3Client.listObjectsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.token, tt.args.keyPrefix)
+			output, err := s3Client.listObjectsByPage(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.token, tt.args.keyPrefix, tt.args.filter, tt.args.keyRange)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
 				return
@@ -1966,6 +2800,54 @@ func TestS3_listBuckets(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "list buckets with the regions because a parameter is specified",
+			args: args{
+				ctx: context.Background(),
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"GetMaxBuckets",
+							getMaxBucketsForListBucketsInitialize,
+						), middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListBucketsWithRegionMock",
+							func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								bucket := types.Bucket{
+									Name: aws.String("test"),
+								}
+								// NOTE: S3 returns the regions only when any parameter is specified.
+								if maxBuckets := middleware.GetStackValue(ctx, maxBucketsForListBuckets{}).(*int32); maxBuckets != nil {
+									bucket.BucketRegion = aws.String("ap-northeast-1")
+								}
+								return middleware.FinalizeOutput{
+									Result: &s3.ListBucketsOutput{
+										Buckets: []types.Bucket{bucket},
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				buckets: []types.Bucket{
+					{
+						Name:         aws.String("test"),
+						BucketRegion: aws.String("ap-northeast-1"),
+					},
+				},
+				err: nil,
+			},
+			wantErr: false,
+		},
 		{
 			name: "list buckets successfully but empty",
 			args: args{
@@ -2738,35 +3620,485 @@ func TestS3_GetBucketLocation(t *testing.T) {
 	}
 }
 
-func TestS3_supportsVersions(t *testing.T) {
-	type fields struct {
-		directoryBucketsMode bool
-		baseEndpoint         *string
+func TestS3_GetBucketTagging(t *testing.T) {
+	type args struct {
+		ctx                context.Context
+		bucketName         *string
+		region             string
+		withAPIOptionsFunc func(*middleware.Stack) error
 	}
 
-	tests := []struct {
-		name   string
-		fields fields
-		want   bool
+	type want struct {
+		tags map[string]string
+		err  error
+	}
+
+	cases := []struct {
+		name    string
+		args    args
+		want    want
+		wantErr bool
 	}{
 		{
-			name: "directory buckets mode returns false",
-			fields: fields{
-				directoryBucketsMode: true,
-				baseEndpoint:         nil,
+			name: "get bucket tagging successfully",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "ap-northeast-1",
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"GetBucketTaggingMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.GetBucketTaggingOutput{
+										TagSet: []types.Tag{
+											{Key: aws.String("env"), Value: aws.String("pr")},
+											{Key: aws.String("owner"), Value: aws.String("")},
+										},
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
 			},
-			want: false,
+			want: want{
+				tags: map[string]string{"env": "pr", "owner": ""},
+				err:  nil,
+			},
+			wantErr: false,
 		},
 		{
-			name: "nil endpoint with standard mode returns true",
-			fields: fields{
-				directoryBucketsMode: false,
-				baseEndpoint:         nil,
+			name: "return empty tags for a bucket without tags",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"GetBucketTaggingNoSuchTagSetMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: nil,
+								}, middleware.Metadata{}, &smithy.GenericAPIError{
+									Code:    "NoSuchTagSet",
+									Message: "The TagSet does not exist",
+								}
+							},
+						),
+						middleware.Before,
+					)
+				},
 			},
-			want: true,
+			want: want{
+				tags: map[string]string{},
+				err:  nil,
+			},
+			wantErr: false,
 		},
 		{
-			name: "empty endpoint with standard mode returns true",
+			name: "get bucket tagging failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				region:     "us-east-1",
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"GetBucketTaggingErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: nil,
+								}, middleware.Metadata{}, fmt.Errorf("GetBucketTaggingError")
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				tags: nil,
+				err: &ClientError{
+					ResourceName: aws.String("test"),
+					Err:          fmt.Errorf("operation error S3: GetBucketTagging, GetBucketTaggingError"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadDefaultConfig(
+				tt.args.ctx,
+				config.WithRegion("us-east-1"),
+				config.WithAPIOptions([]func(*middleware.Stack) error{tt.args.withAPIOptionsFunc}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := s3.NewFromConfig(cfg)
+			s3Client := NewS3(client, false)
+
+			output, err := s3Client.GetBucketTagging(tt.args.ctx, tt.args.bucketName, tt.args.region)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.want.err.Error() {
+				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.err.Error())
+				return
+			}
+			if !reflect.DeepEqual(output, tt.want.tags) {
+				t.Errorf("output = %#v, want %#v", output, tt.want.tags)
+			}
+		})
+	}
+}
+
+func TestS3_GetObject(t *testing.T) {
+	type args struct {
+		ctx                context.Context
+		bucketName         *string
+		key                *string
+		region             string
+		withAPIOptionsFunc func(*middleware.Stack) error
+	}
+
+	type want struct {
+		body string
+		err  error
+	}
+
+	cases := []struct {
+		name    string
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name: "get object successfully",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				key:        aws.String("Key"),
+				region:     "us-east-1",
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"GetObjectMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: &s3.GetObjectOutput{
+										Body: io.NopCloser(strings.NewReader("Body")),
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				body: "Body",
+				err:  nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "get object failure",
+			args: args{
+				ctx:        context.Background(),
+				bucketName: aws.String("test"),
+				key:        aws.String("Key"),
+				region:     "us-east-1",
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"GetObjectErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: nil,
+								}, middleware.Metadata{}, fmt.Errorf("GetObjectError")
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				body: "",
+				err: &ClientError{
+					ResourceName: aws.String("test"),
+					Err:          fmt.Errorf("operation error S3: GetObject, GetObjectError"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadDefaultConfig(
+				tt.args.ctx,
+				config.WithRegion("us-east-1"),
+				config.WithAPIOptions([]func(*middleware.Stack) error{tt.args.withAPIOptionsFunc}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := s3.NewFromConfig(cfg)
+			s3Client := NewS3(client, false)
+
+			output, err := s3Client.GetObject(tt.args.ctx, tt.args.bucketName, tt.args.key, tt.args.region)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				if err.Error() != tt.want.err.Error() {
+					t.Errorf("err = %#v, want %#v", err.Error(), tt.want.err.Error())
+				}
+				return
+			}
+			defer output.Close()
+			body, err := io.ReadAll(output)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(body) != tt.want.body {
+				t.Errorf("output = %#v, want %#v", string(body), tt.want.body)
+			}
+		})
+	}
+}
+
+func TestS3_ListCommonPrefixes(t *testing.T) {
+	type args struct {
+		ctx                  context.Context
+		bucketName           *string
+		region               string
+		keyPrefix            *string
+		directoryBucketsMode bool
+		withAPIOptionsFunc   func(*middleware.Stack) error
+	}
+
+	type want struct {
+		output []string
+		err    error
+	}
+
+	cases := []struct {
+		name    string
+		args    args
+		want    want
+		wantErr bool
+	}{
+		{
+			name: "list common prefixes of object versions across pages",
+			args: args{
+				ctx:                  context.Background(),
+				bucketName:           aws.String("test"),
+				region:               "us-east-1",
+				keyPrefix:            aws.String("prefix/"),
+				directoryBucketsMode: false,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"GetMarker",
+							getMarkerForListCommonPrefixesInitialize,
+						), middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsMock",
+							func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								keyMarker := middleware.GetStackValue(ctx, markerForListCommonPrefixes{}).(*string)
+								if keyMarker == nil {
+									return middleware.FinalizeOutput{
+										Result: &s3.ListObjectVersionsOutput{
+											CommonPrefixes: []types.CommonPrefix{
+												{Prefix: aws.String("prefix/a/")},
+												{Prefix: aws.String("prefix/b/")},
+											},
+											NextKeyMarker:       aws.String("prefix/b/"),
+											NextVersionIdMarker: aws.String("VersionIdMarker"),
+										},
+									}, middleware.Metadata{}, nil
+								}
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectVersionsOutput{
+										CommonPrefixes: []types.CommonPrefix{
+											{Prefix: aws.String("prefix/c/")},
+										},
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: []string{"prefix/a/", "prefix/b/", "prefix/c/"},
+				err:    nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list common prefixes of objects across pages for directory buckets",
+			args: args{
+				ctx:                  context.Background(),
+				bucketName:           aws.String("test"),
+				region:               "us-east-1",
+				keyPrefix:            nil,
+				directoryBucketsMode: true,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					err := stack.Initialize.Add(
+						middleware.InitializeMiddlewareFunc(
+							"GetMarker",
+							getMarkerForListCommonPrefixesInitialize,
+						), middleware.Before,
+					)
+					if err != nil {
+						return err
+					}
+
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectsV2Mock",
+							func(ctx context.Context, input middleware.FinalizeInput, handler middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								token := middleware.GetStackValue(ctx, markerForListCommonPrefixes{}).(*string)
+								if token == nil {
+									return middleware.FinalizeOutput{
+										Result: &s3.ListObjectsV2Output{
+											CommonPrefixes: []types.CommonPrefix{
+												{Prefix: aws.String("a/")},
+											},
+											NextContinuationToken: aws.String("NextContinuationToken"),
+										},
+									}, middleware.Metadata{}, nil
+								}
+								return middleware.FinalizeOutput{
+									Result: &s3.ListObjectsV2Output{
+										CommonPrefixes: []types.CommonPrefix{
+											{Prefix: aws.String("b/")},
+										},
+									},
+								}, middleware.Metadata{}, nil
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: []string{"a/", "b/"},
+				err:    nil,
+			},
+			wantErr: false,
+		},
+		{
+			name: "list common prefixes failure",
+			args: args{
+				ctx:                  context.Background(),
+				bucketName:           aws.String("test"),
+				region:               "us-east-1",
+				keyPrefix:            nil,
+				directoryBucketsMode: false,
+				withAPIOptionsFunc: func(stack *middleware.Stack) error {
+					return stack.Finalize.Add(
+						middleware.FinalizeMiddlewareFunc(
+							"ListObjectVersionsErrorMock",
+							func(context.Context, middleware.FinalizeInput, middleware.FinalizeHandler) (middleware.FinalizeOutput, middleware.Metadata, error) {
+								return middleware.FinalizeOutput{
+									Result: nil,
+								}, middleware.Metadata{}, fmt.Errorf("ListObjectVersionsError")
+							},
+						),
+						middleware.Before,
+					)
+				},
+			},
+			want: want{
+				output: nil,
+				err: &ClientError{
+					ResourceName: aws.String("test"),
+					Err:          fmt.Errorf("operation error S3: ListObjectVersions, ListObjectVersionsError"),
+				},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range cases {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.LoadDefaultConfig(
+				tt.args.ctx,
+				config.WithRegion("us-east-1"),
+				config.WithAPIOptions([]func(*middleware.Stack) error{tt.args.withAPIOptionsFunc}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := s3.NewFromConfig(cfg)
+			s3Client := NewS3(client, tt.args.directoryBucketsMode)
+
+			output, err := s3Client.ListCommonPrefixes(tt.args.ctx, tt.args.bucketName, tt.args.region, tt.args.keyPrefix, "/")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("error = %#v, wantErr %#v", err.Error(), tt.wantErr)
+				return
+			}
+			if tt.wantErr && err.Error() != tt.want.err.Error() {
+				t.Errorf("err = %#v, want %#v", err.Error(), tt.want.err.Error())
+				return
+			}
+			if !reflect.DeepEqual(output, tt.want.output) {
+				t.Errorf("output = %#v, want %#v", output, tt.want.output)
+			}
+		})
+	}
+}
+
+func TestS3_supportsVersions(t *testing.T) {
+	type fields struct {
+		directoryBucketsMode bool
+		baseEndpoint         *string
+	}
+
+	tests := []struct {
+		name   string
+		fields fields
+		want   bool
+	}{
+		{
+			name: "directory buckets mode returns false",
+			fields: fields{
+				directoryBucketsMode: true,
+				baseEndpoint:         nil,
+			},
+			want: false,
+		},
+		{
+			name: "nil endpoint with standard mode returns true",
+			fields: fields{
+				directoryBucketsMode: false,
+				baseEndpoint:         nil,
+			},
+			want: true,
+		},
+		{
+			name: "empty endpoint with standard mode returns true",
 			fields: fields{
 				directoryBucketsMode: false,
 				baseEndpoint:         aws.String(""),
@@ -2846,3 +4178,106 @@ func TestS3_supportsVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestS3_WithExpectedBucketOwner(t *testing.T) {
+	tests := []struct {
+		name                 string
+		accountId            string
+		directoryBucketsMode bool
+		want                 map[string]*string
+	}{
+		{
+			name:      "pass the expected bucket owner to the requests to list and delete",
+			accountId: "123456789012",
+			want: map[string]*string{
+				"ListObjectVersions": aws.String("123456789012"),
+				"ListObjectsV2":      aws.String("123456789012"),
+				"DeleteObjects":      aws.String("123456789012"),
+				"DeleteBucket":       aws.String("123456789012"),
+			},
+		},
+		{
+			name:                 "do not pass the expected bucket owner to DeleteBucket for directory buckets",
+			accountId:            "123456789012",
+			directoryBucketsMode: true,
+			want: map[string]*string{
+				"ListObjectVersions": aws.String("123456789012"),
+				"ListObjectsV2":      aws.String("123456789012"),
+				"DeleteObjects":      aws.String("123456789012"),
+				"DeleteBucket":       nil,
+			},
+		},
+		{
+			name:      "do not pass the expected bucket owner if the account id is empty",
+			accountId: "",
+			want: map[string]*string{
+				"ListObjectVersions": nil,
+				"ListObjectsV2":      nil,
+				"DeleteObjects":      nil,
+				"DeleteBucket":       nil,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			got := map[string]*string{}
+
+			cfg, err := config.LoadDefaultConfig(
+				ctx,
+				config.WithRegion("us-east-1"),
+				config.WithAPIOptions([]func(*middleware.Stack) error{
+					func(stack *middleware.Stack) error {
+						return stack.Initialize.Add(
+							middleware.InitializeMiddlewareFunc(
+								"ExpectedBucketOwnerMock",
+								func(_ context.Context, in middleware.InitializeInput, _ middleware.InitializeHandler) (middleware.InitializeOutput, middleware.Metadata, error) {
+									var result any
+									switch v := in.Parameters.(type) {
+									case *s3.ListObjectVersionsInput:
+										got["ListObjectVersions"] = v.ExpectedBucketOwner
+										result = &s3.ListObjectVersionsOutput{}
+									case *s3.ListObjectsV2Input:
+										got["ListObjectsV2"] = v.ExpectedBucketOwner
+										result = &s3.ListObjectsV2Output{}
+									case *s3.DeleteObjectsInput:
+										got["DeleteObjects"] = v.ExpectedBucketOwner
+										result = &s3.DeleteObjectsOutput{}
+									case *s3.DeleteBucketInput:
+										got["DeleteBucket"] = v.ExpectedBucketOwner
+										result = &s3.DeleteBucketOutput{}
+									}
+									return middleware.InitializeOutput{Result: result}, middleware.Metadata{}, nil
+								},
+							),
+							middleware.Before,
+						)
+					},
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			s3Client := NewS3(s3.NewFromConfig(cfg), tt.directoryBucketsMode).WithExpectedBucketOwner(tt.accountId)
+
+			if _, err := s3Client.listObjectVersionsByPage(ctx, aws.String("test"), "us-east-1", false, nil, nil, nil, nil, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s3Client.listObjectsByPage(ctx, aws.String("test"), "us-east-1", nil, nil, nil, nil); err != nil {
+				t.Fatal(err)
+			}
+			if _, err := s3Client.DeleteObjects(ctx, aws.String("test"), []types.ObjectIdentifier{{Key: aws.String("key")}}, "us-east-1"); err != nil {
+				t.Fatal(err)
+			}
+			if err := s3Client.DeleteBucket(ctx, aws.String("test"), "us-east-1"); err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
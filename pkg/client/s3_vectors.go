@@ -3,14 +3,16 @@ package client
 
 import (
 	"context"
+	"net/http"
 	"sort"
-	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3vectors"
 	"github.com/aws/aws-sdk-go-v2/service/s3vectors/types"
 )
 
+// SleepTimeSecForS3Vectors is the default cap of the delay of the retries for S3 Vectors in seconds.
 var SleepTimeSecForS3Vectors = 20
 
 type ListIndexesByPageOutput struct {
@@ -32,18 +34,22 @@ type S3Vectors struct {
 	retryer *Retryer
 }
 
-func NewS3Vectors(client *s3vectors.Client) *S3Vectors {
-	retryable := func(err error) bool {
-		isRetryable :=
-			strings.Contains(err.Error(), "api error SlowDown") ||
-				strings.Contains(err.Error(), "An internal error occurred. Try again.") ||
-				strings.Contains(err.Error(), "StatusCode: 429") ||
-				strings.Contains(err.Error(), "StatusCode: 503") ||
-				strings.Contains(err.Error(), "Please try again")
-
-		return isRetryable
+// NewS3Vectors creates an S3 Vectors client. The retries of its requests can be configured with optFns.
+func NewS3Vectors(client *s3vectors.Client, optFns ...func(*RetryOptions)) *S3Vectors {
+	options := RetryOptions{
+		MaxAttempts: MaxAttempts,
+		BaseDelay:   DefaultRetryBaseDelay,
+		MaxDelay:    time.Duration(SleepTimeSecForS3Vectors) * time.Second,
+	}
+	for _, optFn := range optFns {
+		optFn(&options)
 	}
-	retryer := NewRetryer(retryable, SleepTimeSecForS3Vectors)
+
+	classifier := NewRetryableErrorClassifier(
+		DefaultS3VectorsRetryableErrorCodes,
+		[]int{http.StatusTooManyRequests, http.StatusServiceUnavailable},
+	).WithCodes(options.RetryableErrorCodes...)
+	retryer := NewRetryer(classifier.IsRetryable, options)
 
 	return &S3Vectors{
 		client,
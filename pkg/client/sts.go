@@ -0,0 +1,37 @@
+//go:generate mockgen -source=$GOFILE -destination=mock_$GOFILE -package=$GOPACKAGE -write_package_comment=false
+package client
+
+import (
+	"context"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+type ISTS interface {
+	GetCallerAccountId(ctx context.Context) (string, error)
+}
+
+var _ ISTS = (*STS)(nil)
+
+type STS struct {
+	client *sts.Client
+}
+
+func NewSTS(client *sts.Client) *STS {
+	return &STS{
+		client,
+	}
+}
+
+func (s *STS) GetCallerAccountId(ctx context.Context) (string, error) {
+	input := &sts.GetCallerIdentityInput{}
+
+	output, err := s.client.GetCallerIdentity(ctx, input)
+	if err != nil {
+		return "", &ClientError{
+			Err: err,
+		}
+	}
+	return aws.ToString(output.Account), nil
+}